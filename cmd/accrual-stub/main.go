@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/pkg/accrualstub"
+	"github.com/spf13/pflag"
+)
+
+// заглушка системы начислений для локальной разработки:
+// отвечает на GET /api/orders/{number} по yaml-сценарию
+func main() {
+	logging.Setup()
+
+	flags := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+	address := flags.StringP("address", "a", "localhost:8181", "address:port to listen on")
+	scenarioPath := flags.StringP("scenario", "s", "", "path to YAML scenario file; default scenario is used if empty")
+	_ = flags.Parse(os.Args[1:])
+
+	scenario, err := accrualstub.LoadScenario(*scenarioPath)
+	if err != nil {
+		logging.LogFatal(err)
+	}
+
+	server := &http.Server{
+		Addr:              *address,
+		Handler:           accrualstub.NewServer(scenario),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	go func() {
+		logging.LogInfoF("accrual stub listening on %s", *address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.LogError(err, "accrual stub error")
+			cancel()
+		}
+	}()
+
+	<-ctx.Done()
+
+	sCtx, sCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer sCancel()
+	if err := server.Shutdown(sCtx); err != nil {
+		logging.LogError(err, "err stopping accrual stub")
+	}
+}
@@ -17,13 +17,21 @@ var (
 func main() {
 	logging.Setup()
 	fmt.Printf("Build version: %s\nBuild date: %s\nBuild commit: %s\n", buildVersion, buildDate, buildCommit)
-	logging.LogInfo("starting server...")
 
 	config, err := config.Parse()
 	if err != nil {
 		logging.LogFatal(err)
 	}
 
+	if len(config.Args) > 0 {
+		if err = runCommand(config, config.Args); err != nil {
+			logging.LogFatal(err)
+		}
+		return
+	}
+
+	logging.LogInfo("starting server...")
+
 	apl, err := NewApp(config)
 	if err != nil {
 		logging.LogFatal(err)
@@ -35,6 +43,15 @@ func main() {
 	}
 }
 
+func runCommand(config *config.Config, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(config.DB, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
 func NewApp(config *config.Config) (*app.App, error) {
 	return app.New(config, nil, nil, nil)
 }
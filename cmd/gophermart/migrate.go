@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage/migrator"
+)
+
+const migrateUsage = "usage: gophermart migrate up | down [N] | to N | status | force N"
+
+var errMigrateUsage = errors.New(migrateUsage)
+
+// gophermart migrate <command> [N]: управление схемой базы без запуска сервера
+func runMigrate(dbConfig config.DB, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	m, err := migrator.New(dbConfig)
+	if err != nil {
+		return fmt.Errorf("migrator.New() failed: %w", err)
+	}
+	defer m.Close()
+
+	command, params := args[0], args[1:]
+
+	switch command {
+	case "up":
+		if len(params) != 0 {
+			return errMigrateUsage
+		}
+		err = m.Up()
+	case "down":
+		steps := 1
+		if len(params) > 1 {
+			return errMigrateUsage
+		}
+		if len(params) == 1 {
+			if steps, err = strconv.Atoi(params[0]); err != nil {
+				return fmt.Errorf("invalid number of steps %q: %w", params[0], err)
+			}
+		}
+		err = m.Down(steps)
+	case "to":
+		if len(params) != 1 {
+			return errMigrateUsage
+		}
+
+		var version uint64
+		if version, err = strconv.ParseUint(params[0], 10, 0); err != nil {
+			return fmt.Errorf("invalid version %q: %w", params[0], err)
+		}
+		err = m.To(uint(version))
+	case "force":
+		if len(params) != 1 {
+			return errMigrateUsage
+		}
+
+		var version int
+		if version, err = strconv.Atoi(params[0]); err != nil {
+			return fmt.Errorf("invalid version %q: %w", params[0], err)
+		}
+		err = m.Force(version)
+	case "status":
+		if len(params) != 0 {
+			return errMigrateUsage
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", command, migrateUsage)
+	}
+
+	if err != nil {
+		return fmt.Errorf("migrate %s failed: %w", command, err)
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	logging.LogInfoF("schema version: %d, latest: %d, dirty: %t, pending: %t", status.Version, status.Latest, status.Dirty, status.Pending())
+
+	return nil
+}
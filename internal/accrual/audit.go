@@ -0,0 +1,75 @@
+package accrual
+
+import (
+	"context"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+// как часто удаляются обращения старше срока хранения
+const callsCleanupInterval = time.Hour
+
+// в журнале хранится только начало ответа, чтобы большие ответы не раздували таблицу
+const maxCallBodyLen = 64 << 10
+
+// журнал обращений к системам начислений
+type IAuditLog interface {
+	Record(ctx context.Context, call domain.AccrualCall)
+}
+
+type AuditLog struct {
+	repo    repository.IAccrualCallRepository
+	timeout time.Duration
+}
+
+func NewAuditLog(repo repository.IAccrualCallRepository, timeout time.Duration) *AuditLog {
+	return &AuditLog{repo: repo, timeout: timeout}
+}
+
+// ошибка записи не влияет на обработку ответа, а отмена контекста запроса
+// не мешает сохранить уже полученный ответ
+func (a *AuditLog) Record(ctx context.Context, call domain.AccrualCall) {
+	call.ResponseBody = storableText(call.ResponseBody, maxCallBodyLen)
+	call.Error = storableText(call.Error, maxCallBodyLen)
+
+	tCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.timeout)
+	defer cancel()
+
+	if err := a.repo.AccrualCallCreate(tCtx, call); err != nil {
+		logging.LogErrorCtx(ctx, err, "error recording accrual call")
+	}
+}
+
+// периодически удаляет обращения старше срока хранения
+func (s *Service) cleanupCalls() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			logging.LogInfo("accrual calls cleanup stopped")
+			return
+		case <-time.After(callsCleanupInterval):
+			if err := s.deleteExpiredCalls(); err != nil {
+				logging.LogError(err, "error deleting expired accrual calls")
+			}
+		}
+	}
+}
+
+func (s *Service) deleteExpiredCalls() error {
+	ctx, cancel := context.WithTimeout(s.ctx, s.contextTimeout)
+	defer cancel()
+
+	deleted, err := s.callRepo.AccrualCallDeleteOlderThan(ctx, s.callsRetention)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		logging.LogInfoF("deleted %d expired accrual calls", deleted)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package accrual_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClient_WithAudit_RecordsResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"order":"12345678903","status":"PROCESSED","accrual":100}`))
+	}))
+	defer server.Close()
+
+	repo := mock_repository.NewMockIAccrualCallRepository(ctrl)
+
+	var recorded domain.AccrualCall
+	repo.EXPECT().AccrualCallCreate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, call domain.AccrualCall) error {
+		recorded = call
+		return nil
+	})
+
+	client := accrual.NewClient(server.URL, time.Second).WithAudit("partner", accrual.NewAuditLog(repo, time.Second))
+
+	_, cErr := client.GetBonuses(context.Background(), "12345678903")
+	require.Nil(t, cErr)
+
+	assert.Equal(t, "12345678903", recorded.OrderNumber)
+	assert.Equal(t, "partner", recorded.Provider)
+	assert.Equal(t, server.URL+"/api/orders/12345678903", recorded.URL)
+	assert.Equal(t, http.StatusOK, recorded.HTTPStatus)
+	assert.JSONEq(t, `{"order":"12345678903","status":"PROCESSED","accrual":100}`, recorded.ResponseBody)
+	assert.Empty(t, recorded.Error)
+	assert.GreaterOrEqual(t, recorded.Latency, time.Duration(0))
+}
+
+func TestClient_WithAudit_RecordsNetworkError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	address := server.URL
+	server.Close()
+
+	repo := mock_repository.NewMockIAccrualCallRepository(ctrl)
+
+	var recorded domain.AccrualCall
+	repo.EXPECT().AccrualCallCreate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, call domain.AccrualCall) error {
+		recorded = call
+		return nil
+	})
+
+	client := accrual.NewClient(address, time.Second).WithAudit(accrual.DefaultProvider, accrual.NewAuditLog(repo, time.Second))
+
+	_, cErr := client.GetBonuses(context.Background(), "12345678903")
+	require.NotNil(t, cErr)
+
+	assert.Equal(t, accrual.DefaultProvider, recorded.Provider)
+	assert.Zero(t, recorded.HTTPStatus)
+	assert.NotEmpty(t, recorded.Error)
+}
+
+func TestAuditLog_Record_TruncatesBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockIAccrualCallRepository(ctrl)
+
+	var recorded domain.AccrualCall
+	repo.EXPECT().AccrualCallCreate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, call domain.AccrualCall) error {
+		recorded = call
+		return nil
+	})
+
+	accrual.NewAuditLog(repo, time.Second).Record(context.Background(), domain.AccrualCall{
+		OrderNumber:  "12345678903",
+		ResponseBody: strings.Repeat("x", 100<<10),
+	})
+
+	assert.Len(t, recorded.ResponseBody, 64<<10)
+}
+
+func TestAuditLog_Record_NotUTF8Body(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockIAccrualCallRepository(ctrl)
+
+	var recorded domain.AccrualCall
+	repo.EXPECT().AccrualCallCreate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, call domain.AccrualCall) error {
+		recorded = call
+		return nil
+	})
+
+	accrual.NewAuditLog(repo, time.Second).Record(context.Background(), domain.AccrualCall{
+		OrderNumber:  "12345678903",
+		ResponseBody: "\xff\x00" + strings.Repeat("я", 40<<10),
+	})
+
+	assert.True(t, utf8.ValidString(recorded.ResponseBody))
+	assert.NotContains(t, recorded.ResponseBody, "\x00")
+	assert.LessOrEqual(t, len(recorded.ResponseBody), 64<<10)
+	assert.Greater(t, len(recorded.ResponseBody), 64<<10-4)
+}
+
+// ошибка записи и отмененный контекст запроса не мешают обработке ответа
+func TestAuditLog_Record_IgnoresErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_repository.NewMockIAccrualCallRepository(ctrl)
+	repo.EXPECT().AccrualCallCreate(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ domain.AccrualCall) error {
+		assert.NoError(t, ctx.Err())
+		return errors.New("database error")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	accrual.NewAuditLog(repo, time.Second).Record(ctx, domain.AccrualCall{OrderNumber: "12345678903"})
+}
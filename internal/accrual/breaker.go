@@ -0,0 +1,145 @@
+package accrual
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/logging"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+var ErrCircuitOpen = errors.New("accrual circuit breaker is open")
+
+// размыкается после threshold ошибок доступности подряд;
+// через probeInterval пропускает один пробный запрос и по его результату замыкается или снова размыкается
+type Breaker struct {
+	mu sync.Mutex
+
+	threshold     int
+	probeInterval time.Duration
+
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(threshold int, probeInterval time.Duration) *Breaker {
+	return &Breaker{
+		threshold:     threshold,
+		probeInterval: probeInterval,
+		state:         CircuitClosed,
+	}
+}
+
+// можно ли отправить запрос; в полуоткрытом состоянии пропускается только один пробный запрос
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.probeInterval {
+			return false
+		}
+
+		b.setState(CircuitHalfOpen)
+		b.probing = true
+		return true
+
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+
+		b.probing = true
+		return true
+
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	b.setState(CircuitClosed)
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.openedAt = time.Now()
+		b.setState(CircuitOpen)
+	}
+}
+
+func (b *Breaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// время пробного запроса, если цепь разомкнута; иначе нулевое время
+func (b *Breaker) ProbeAt() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitOpen {
+		return time.Time{}
+	}
+
+	return b.openedAt.Add(b.probeInterval)
+}
+
+func (b *Breaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+
+	logging.LogWarnF("accrual circuit breaker: %s -> %s (failures=%d)", b.state, state, b.failures)
+	b.state = state
+}
+
+// клиент системы начислений под защитой Breaker
+type BreakerClient struct {
+	client  IClient
+	breaker *Breaker
+}
+
+func NewBreakerClient(client IClient, breaker *Breaker) *BreakerClient {
+	return &BreakerClient{client: client, breaker: breaker}
+}
+
+func (c *BreakerClient) GetBonuses(ctx context.Context, orderNumber string) (*Response, *ClientError) {
+	if !c.breaker.Allow() {
+		return nil, &ClientError{error: ErrCircuitOpen}
+	}
+
+	res, err := c.client.GetBonuses(ctx, orderNumber)
+	if err != nil && err.IsUnavailable() {
+		c.breaker.Failure()
+	} else {
+		c.breaker.Success()
+	}
+
+	return res, err
+}
@@ -0,0 +1,89 @@
+package accrual_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b := accrual.NewBreaker(3, time.Minute)
+
+	b.Failure()
+	b.Failure()
+	assert.Equal(t, accrual.CircuitClosed, b.State())
+	assert.True(t, b.Allow())
+
+	b.Failure()
+	assert.Equal(t, accrual.CircuitOpen, b.State())
+	assert.False(t, b.Allow())
+	assert.WithinDuration(t, time.Now().Add(time.Minute), b.ProbeAt(), time.Second)
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b := accrual.NewBreaker(2, time.Minute)
+
+	b.Failure()
+	b.Success()
+	b.Failure()
+
+	assert.Equal(t, accrual.CircuitClosed, b.State())
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	b := accrual.NewBreaker(1, 50*time.Millisecond)
+
+	b.Failure()
+	assert.False(t, b.Allow())
+
+	time.Sleep(60 * time.Millisecond)
+
+	// пропускается только один пробный запрос
+	assert.True(t, b.Allow())
+	assert.Equal(t, accrual.CircuitHalfOpen, b.State())
+	assert.False(t, b.Allow())
+
+	// неудачная проба снова размыкает цепь
+	b.Failure()
+	assert.Equal(t, accrual.CircuitOpen, b.State())
+
+	time.Sleep(60 * time.Millisecond)
+
+	// успешная проба замыкает цепь
+	assert.True(t, b.Allow())
+	b.Success()
+	assert.Equal(t, accrual.CircuitClosed, b.State())
+	assert.True(t, b.ProbeAt().IsZero())
+}
+
+func TestBreakerClient_GetBonuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	breaker := accrual.NewBreaker(2, time.Minute)
+	client := accrual.NewBreakerClient(mockClient, breaker)
+
+	// 4xx не говорит о недоступности системы начислений
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "1").Return(nil, accrual.NewClientError(errors.New("bad request"), http.StatusBadRequest))
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "1").Return(nil, accrual.NewClientError(errors.New("bad gateway"), http.StatusBadGateway)).Times(2)
+
+	_, err := client.GetBonuses(context.Background(), "1")
+	assert.NotNil(t, err)
+	assert.Equal(t, accrual.CircuitClosed, breaker.State())
+
+	_, _ = client.GetBonuses(context.Background(), "1")
+	_, _ = client.GetBonuses(context.Background(), "1")
+	assert.Equal(t, accrual.CircuitOpen, breaker.State())
+
+	// цепь разомкнута - запрос не отправляется
+	_, err = client.GetBonuses(context.Background(), "1")
+	assert.True(t, errors.Is(err, accrual.ErrCircuitOpen))
+}
@@ -0,0 +1,76 @@
+package accrual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+)
+
+var ErrUnknownOrder = errors.New("unknown order")
+var ErrInvalidAccrualStatus = errors.New("invalid accrual status")
+
+type ApplyResult string
+
+const (
+	ApplyResultApplied ApplyResult = "applied" // статус или начисление заказа изменены
+	ApplyResultIgnored ApplyResult = "ignored" // результат уже применен или ничего не меняет
+)
+
+func (s AccrualStatus) IsValid() bool {
+	switch s {
+	case StatusRegistered, StatusInvalid, StatusProcessing, StatusProcessed:
+		return true
+	}
+
+	return false
+}
+
+// применяет результат, присланный системой начислений, с теми же переходами, что и при опросе;
+// повторная доставка того же результата ничего не меняет
+func (s *Service) Apply(ctx context.Context, res Response) (ApplyResult, error) {
+	if !res.Status.IsValid() {
+		return "", ErrInvalidAccrualStatus
+	}
+
+	tCtx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	order, err := s.orderRepo.OrderFindByNumber(tCtx, res.OrderNumber)
+	if err != nil {
+		if errors.Is(err, storage.ErrRecordNotFound) {
+			return "", ErrUnknownOrder
+		}
+		return "", err
+	}
+
+	// заказ в конечном статусе больше не меняется, а совпадающий промежуточный статус
+	// не требует обновления; отложенный опрос при этом сохраняется как страховка.
+	// заказ на ручной проверке ждет решения администратора.
+	// начисления по заказам со встроенным расчетом внешняя система не присылает
+	if order.IsFinal() || order.AwaitsReview() || !statusChanges(order.Status, res.Status) || s.engineFor(order) == domain.AccrualEngineInternal {
+		logging.LogDebugCtx(ctx, fmt.Sprintf("%s: callback with status %s ignored", order, res.Status))
+		return ApplyResultIgnored, nil
+	}
+
+	task := NewTask(s, order)
+	if err := task.apply(tCtx, &res); err != nil {
+		return "", err
+	}
+
+	return ApplyResultApplied, nil
+}
+
+func statusChanges(current domain.OrderStatus, status AccrualStatus) bool {
+	switch status {
+	case StatusRegistered:
+		return false
+	case StatusProcessing:
+		return current != domain.OrderStatusProcessing
+	}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package accrual_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_Apply_Processed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	order := &domain.Order{ID: 1, UserID: 2, Number: "12345678903", Status: domain.OrderStatusProcessing}
+	mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(order, nil)
+
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+
+	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o domain.Order) error {
+			assert.Equal(t, domain.OrderStatusProcessed, o.Status)
+			assert.True(t, decimal.NewFromInt(500).Equal(o.Accrual))
+			return nil
+		},
+	)
+	mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), order.UserID).Return(nil)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(), &cfg.Accrual, mock_accrual.NewMockIClient(ctrl),
+		mockStorage, &repository.Repositories{
+			User:       mockUserRepo,
+			Order:      mockOrderRepo,
+			AccrualJob: mockJobRepo,
+		}, nil,
+	)
+
+	result, err := service.Apply(context.Background(), accrual.Response{
+		OrderNumber: "12345678903",
+		Status:      accrual.StatusProcessed,
+		Amount:      decimal.NewFromInt(500),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, accrual.ApplyResultApplied, result)
+}
+
+func TestService_Apply_Ignored(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  domain.OrderStatus
+		engine domain.AccrualEngine
+		status accrual.AccrualStatus
+	}{
+		{"already processed", domain.OrderStatusProcessed, "", accrual.StatusProcessed},
+		{"already invalid", domain.OrderStatusInvalid, "", accrual.StatusProcessed},
+		{"still processing", domain.OrderStatusProcessing, "", accrual.StatusProcessing},
+		{"registered", domain.OrderStatusNew, "", accrual.StatusRegistered},
+		{"internal engine", domain.OrderStatusNew, domain.AccrualEngineInternal, accrual.StatusProcessed},
+		{"awaits review", domain.OrderStatusNeedsReview, "", accrual.StatusProcessed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+			order := &domain.Order{ID: 1, Number: "12345678903", Status: tt.order, Engine: tt.engine}
+			mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(order, nil)
+			mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Times(0)
+
+			cfg, _ := config.NewDefault(&config.Config{})
+			service := accrual.NewService(
+				context.Background(), &cfg.Accrual, mock_accrual.NewMockIClient(ctrl),
+				mockStorage, &repository.Repositories{
+					User:       mock_repository.NewMockIUserRepository(ctrl),
+					Order:      mockOrderRepo,
+					AccrualJob: mock_repository.NewMockIAccrualJobRepository(ctrl),
+				}, nil,
+			)
+
+			result, err := service.Apply(context.Background(), accrual.Response{OrderNumber: "12345678903", Status: tt.status})
+
+			assert.NoError(t, err)
+			assert.Equal(t, accrual.ApplyResultIgnored, result)
+		})
+	}
+}
+
+func TestService_Apply_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(nil, storage.ErrRecordNotFound)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(), &cfg.Accrual, mock_accrual.NewMockIClient(ctrl),
+		mock_storage.NewMockIPGXStorage(ctrl), &repository.Repositories{
+			User:       mock_repository.NewMockIUserRepository(ctrl),
+			Order:      mockOrderRepo,
+			AccrualJob: mock_repository.NewMockIAccrualJobRepository(ctrl),
+		}, nil,
+	)
+
+	_, err := service.Apply(context.Background(), accrual.Response{OrderNumber: "12345678903", Status: "DONE"})
+	assert.ErrorIs(t, err, accrual.ErrInvalidAccrualStatus)
+
+	_, err = service.Apply(context.Background(), accrual.Response{OrderNumber: "12345678903", Status: accrual.StatusInvalid})
+	assert.ErrorIs(t, err, accrual.ErrUnknownOrder)
+}
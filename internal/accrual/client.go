@@ -1,16 +1,17 @@
 package accrual
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ex0rcist/gophermart/internal/domain"
 	"github.com/ex0rcist/gophermart/internal/logging"
 	"github.com/ex0rcist/gophermart/internal/utils"
 	"github.com/shopspring/decimal"
@@ -29,10 +30,19 @@ type IClient interface {
 	GetBonuses(ctx context.Context, orderNumber string) (*Response, *ClientError)
 }
 
+// путь запроса основной системы начислений
+const DefaultPath = "/api/orders/{number}"
+
 // клиент для работы с системой начисления бонусов
 type Client struct {
 	address string
+	path    string
+	mapper  IResponseMapper
 	client  *http.Client
+
+	// журнал обращений, nil - обращения не сохраняются
+	provider string
+	audit    IAuditLog
 }
 
 type Response struct {
@@ -45,37 +55,84 @@ type ClientError struct {
 	error
 	HTTPStatus int
 	RetryAfter time.Duration
+	Body       []byte // тело ответа, если он был получен
+}
+
+// ответ получен, но не может быть обработан: 4xx, 5xx или некорректный JSON;
+// 429 и сетевые ошибки считаются временными
+func (e *ClientError) IsBadResponse() bool {
+	return e.HTTPStatus != 0 && e.HTTPStatus != http.StatusTooManyRequests
+}
+
+// система начислений недоступна: сетевая ошибка, таймаут или 5xx
+func (e *ClientError) IsUnavailable() bool {
+	return e.HTTPStatus == 0 || e.HTTPStatus >= http.StatusInternalServerError
+}
+
+func (e *ClientError) Unwrap() error {
+	return e.error
+}
+
+func NewClientError(err error, httpStatus int) *ClientError {
+	return &ClientError{error: err, HTTPStatus: httpStatus}
 }
 
 func NewClient(address string, timeout time.Duration) *Client {
+	return NewMappedClient(address, DefaultPath, JSONMapper{}, timeout)
+}
+
+// клиент к системе начислений с другим путем запроса и форматом ответа
+func NewMappedClient(address, path string, mapper IResponseMapper, timeout time.Duration) *Client {
 	return &Client{
 		address: address,
+		path:    path,
+		mapper:  mapper,
 		client:  &http.Client{Timeout: timeout},
 	}
 }
 
+// сохранять каждое обращение в журнал под именем провайдера
+func (c *Client) WithAudit(provider string, audit IAuditLog) *Client {
+	c.provider = provider
+	c.audit = audit
+
+	return c
+}
+
 func (c *Client) GetBonuses(ctx context.Context, orderNumber string) (*Response, *ClientError) {
-	url := fmt.Sprintf("%s/api/orders/%s", c.address, orderNumber)
+	reqURL := c.address + strings.ReplaceAll(c.path, "{number}", url.PathEscape(orderNumber))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, &ClientError{error: err}
 	}
 
 	req.Header.Set("Content-Length", "0")
 
-	logRequest(ctx, url)
+	// по идентификатору запроса заказ прослеживается от создания до ответа системы начислений
+	requestID := utils.RequestIDFromContext(ctx)
+	if requestID != "" {
+		req.Header.Set("X-Request-Id", requestID)
+		req.Header.Set("traceparent", utils.TraceParent(requestID))
+	}
+
+	logRequest(ctx, reqURL)
+
+	started := time.Now()
 
 	res, err := c.client.Do(req)
 	if err != nil {
+		c.record(ctx, orderNumber, reqURL, started, 0, nil, err)
 		return nil, &ClientError{error: err}
 	}
 	defer res.Body.Close()
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
+		c.record(ctx, orderNumber, reqURL, started, res.StatusCode, body, err)
 		return nil, &ClientError{error: err}
 	}
 
+	c.record(ctx, orderNumber, reqURL, started, res.StatusCode, body, nil)
 	logResponse(ctx, res, body)
 
 	// 429 - возвращаем специальную ошибку
@@ -91,32 +148,70 @@ func (c *Client) GetBonuses(ctx context.Context, orderNumber string) (*Response,
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return nil, &ClientError{error: errors.New(http.StatusText(res.StatusCode)), HTTPStatus: res.StatusCode}
+		return nil, &ClientError{error: errors.New(http.StatusText(res.StatusCode)), HTTPStatus: res.StatusCode, Body: body}
 	}
 
-	accrualRes := &Response{}
-	err = json.NewDecoder(bytes.NewReader(body)).Decode(accrualRes)
+	accrualRes, err := c.mapper.Map(orderNumber, body)
 	if err != nil {
-		return nil, &ClientError{error: err}
+		return nil, &ClientError{error: fmt.Errorf("malformed response: %w", err), HTTPStatus: res.StatusCode, Body: body}
 	}
 	return accrualRes, nil
 }
 
+func (c *Client) record(ctx context.Context, orderNumber, reqURL string, started time.Time, status int, body []byte, err error) {
+	if c.audit == nil {
+		return
+	}
+
+	call := domain.AccrualCall{
+		OrderNumber:  orderNumber,
+		Provider:     c.provider,
+		URL:          reqURL,
+		HTTPStatus:   status,
+		ResponseBody: string(body),
+		Latency:      time.Since(started),
+		RequestID:    utils.RequestIDFromContext(ctx),
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+
+	c.audit.Record(ctx, call)
+}
+
+// Retry-After может быть задан в секундах или HTTP-датой;
+// если заголовок отсутствует или некорректен, RetryAfter остается нулевым
 func (c *Client) handleErrTooManyRequests(res *http.Response) *ClientError {
 	accrualErr := ClientError{
 		error:      errors.New(http.StatusText(res.StatusCode)),
 		HTTPStatus: res.StatusCode,
 	}
 
-	retryAfter, err := strconv.Atoi(res.Header.Get("Retry-After"))
-	if err != nil {
-		return &ClientError{error: err}
-	}
-	accrualErr.RetryAfter = utils.IntToDuration(retryAfter)
+	accrualErr.RetryAfter = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
 
 	return &accrualErr
 }
 
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return utils.IntToDuration(seconds)
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil || !date.After(now) {
+		return 0
+	}
+
+	return date.Sub(now)
+}
+
 func logRequest(ctx context.Context, url string) {
 	logging.LogInfoCtx(ctx, "sending request to: "+url)
 }
@@ -8,8 +8,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/ex0rcist/gophermart/pkg/accrualstub"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetBonuses_Success(t *testing.T) {
@@ -78,6 +81,47 @@ func TestGetBonuses_TooManyRequests(t *testing.T) {
 	assert.Equal(t, 5*time.Second, err.RetryAfter)
 }
 
+func TestGetBonuses_TooManyRequests_WithoutRetryAfter(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+
+	response, err := client.GetBonuses(context.Background(), "12345")
+
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, err.HTTPStatus)
+	assert.Equal(t, time.Duration(0), err.RetryAfter)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "60", time.Minute},
+		{"negative seconds", "-5", 0},
+		{"http date", "Wed, 01 May 2024 12:00:30 GMT", 30 * time.Second},
+		{"http date in the past", "Wed, 01 May 2024 11:00:00 GMT", 0},
+		{"garbage", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseRetryAfter(tt.value, now))
+		})
+	}
+}
+
 func TestGetBonuses_UnexpectedStatus(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -94,6 +138,28 @@ func TestGetBonuses_UnexpectedStatus(t *testing.T) {
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusInternalServerError, err.HTTPStatus)
+	assert.True(t, err.IsBadResponse())
+}
+
+func TestGetBonuses_MalformedJSON(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"order": "12345", "status":`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	response, err := client.GetBonuses(ctx, "12345")
+
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusOK, err.HTTPStatus)
+	assert.Equal(t, `{"order": "12345", "status":`, string(err.Body))
+	assert.True(t, err.IsBadResponse())
 }
 
 func TestGetBonuses_RequestCreationError(t *testing.T) {
@@ -125,3 +191,73 @@ func TestGetBonuses_BodyReadError(t *testing.T) {
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 }
+
+func TestGetBonuses_AccrualStub(t *testing.T) {
+	scenario, err := accrualstub.ParseScenario([]byte(`
+rules:
+  - prefix: "1"
+    steps: [{status: PROCESSED}]
+    accrual: {fixed: 150.5}
+    too_many_requests: {every: 2, retry_after: 3s}
+  - prefix: "2"
+    steps: [{status: PROCESSED}]
+    malformed: true
+`))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(accrualstub.NewServer(scenario))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+
+	response, cErr := client.GetBonuses(context.Background(), "12345")
+	assert.Nil(t, cErr)
+	assert.Equal(t, StatusProcessed, response.Status)
+	assert.True(t, decimal.RequireFromString("150.5").Equal(response.Amount))
+
+	_, cErr = client.GetBonuses(context.Background(), "12345")
+	assert.Equal(t, http.StatusTooManyRequests, cErr.HTTPStatus)
+	assert.Equal(t, 3*time.Second, cErr.RetryAfter)
+
+	_, cErr = client.GetBonuses(context.Background(), "23456")
+	assert.True(t, cErr.IsBadResponse())
+}
+
+func TestGetBonuses_SendsRequestID(t *testing.T) {
+	var headers http.Header
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+	ctx := utils.ContextWithRequestID(context.Background(), "4bf92f35-77b3-4da6-a3ce-929d0e0e4736")
+
+	_, err := client.GetBonuses(ctx, "12345")
+	require.Nil(t, err)
+
+	assert.Equal(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", headers.Get("X-Request-Id"))
+	assert.Regexp(t, `^00-4bf92f3577b34da6a3ce929d0e0e4736-[0-9a-f]{16}-01$`, headers.Get("traceparent"))
+}
+
+func TestGetBonuses_WithoutRequestID(t *testing.T) {
+	var headers http.Header
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+
+	_, err := client.GetBonuses(context.Background(), "12345")
+	require.Nil(t, err)
+
+	assert.Empty(t, headers.Get("X-Request-Id"))
+	assert.Empty(t, headers.Get("traceparent"))
+}
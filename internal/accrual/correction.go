@@ -0,0 +1,120 @@
+package accrual
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// исправления учитываются только для заказов, начисление по которым считает внешняя система;
+// начисление, одобренное администратором, не пересматривается
+func (s *Service) correctsOrder(order *domain.Order) bool {
+	return s.correctionWindow > 0 && !order.AwaitsReview() && s.engineFor(order) == domain.AccrualEngineExternal
+}
+
+func (t Task) inCorrection() bool {
+	return t.correctUntil != nil
+}
+
+// сверяет ответ системы начислений по обработанному заказу с уже проведенным начислением
+func (t Task) correct(ctx context.Context, res *Response) error {
+	if res.Status != StatusProcessed {
+		logging.LogWarnCtx(ctx, fmt.Sprintf("%s reported as %s after processing, ignored", t.order, res.Status))
+		return t.rescheduleCorrection(ctx)
+	}
+
+	accrual, base, err := t.service.orderRepo.OrderAccrualCurrent(ctx, t.order.ID)
+	if err != nil {
+		return err
+	}
+
+	if res.Amount.Equal(base) {
+		logging.LogDebugCtx(ctx, fmt.Sprintf("%s accrual is unchanged", t.order))
+		return t.rescheduleCorrection(ctx)
+	}
+
+	// исправление проверяется так же, как первое начисление
+	reason, err := t.service.checkAmount(ctx, t.order, res.Amount)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		logging.LogWarnCtx(ctx, fmt.Sprintf("%s correction ignored: %s", t.order, reason))
+		return t.rescheduleCorrection(ctx)
+	}
+
+	return t.adjust(ctx, accrual, base, res.Amount)
+}
+
+// проводит разницу между исправленным и текущим начислением корректировкой и пересчитывает баланс
+func (t Task) adjust(ctx context.Context, accrual, base, reported decimal.Decimal) error {
+	// множитель уровня, примененный при обработке заказа, сохраняется
+	target := reported
+	if base.IsPositive() {
+		target = reported.Mul(accrual).Div(base).Round(2)
+	}
+
+	return t.service.storage.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		adj := domain.OrderAdjustment{
+			OrderID:     t.order.ID,
+			UserID:      t.order.UserID,
+			Amount:      target.Sub(accrual),
+			BaseAccrual: reported,
+		}
+
+		// без разрешающей политики списывается не больше текущего баланса, остаток фиксируется в проводке
+		if adj.Amount.IsNegative() && !t.service.correctionAllowNegative {
+			balance, _, err := t.service.userRepo.UserGetBalance(ctx, t.order.UserID)
+			if err != nil {
+				return err
+			}
+
+			available := decimal.Max(*balance, decimal.Zero)
+			if adj.Amount.Neg().GreaterThan(available) {
+				adj.Unrecovered = adj.Amount.Neg().Sub(available)
+				adj.Amount = available.Neg()
+
+				logging.LogWarnCtx(ctx, fmt.Sprintf("%s correction exceeds balance, unrecovered=%s", t.order, adj.Unrecovered))
+			}
+		}
+
+		logging.LogInfoCtx(ctx, fmt.Sprintf("%s accrual corrected: %s -> %s, adjustment=%s", t.order, accrual, target, adj.Amount))
+
+		if err := t.service.orderRepo.OrderAdjustmentCreate(ctx, adj); err != nil {
+			return err
+		}
+
+		if err := t.service.userRepo.UserUpdateBalanceAndWithdrawals(ctx, t.order.UserID); err != nil {
+			return err
+		}
+
+		if t.service.program != nil {
+			tier, err := t.service.userRepo.UserGetTier(ctx, t.order.UserID)
+			if err != nil {
+				return err
+			}
+
+			if err = t.updateTier(ctx, tier); err != nil {
+				return err
+			}
+		}
+
+		return t.rescheduleCorrection(ctx)
+	})
+}
+
+// обработанный заказ опрашивается с постоянным редким интервалом
+func (t Task) rescheduleCorrection(ctx context.Context) error {
+	job := domain.AccrualJob{OrderID: t.order.ID, LeaseOwner: t.service.instanceID}
+	return t.service.jobRepo.AccrualJobReschedule(ctx, job, t.service.correctionInterval)
+}
+
+// окно исправлений закрылось - задача удаляется без опроса
+func (t Task) correctionExpired() bool {
+	return t.inCorrection() && time.Now().After(*t.correctUntil)
+}
@@ -0,0 +1,168 @@
+package accrual_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type correctionMocks struct {
+	client    *mock_accrual.MockIClient
+	orderRepo *mock_repository.MockIOrderRepository
+	userRepo  *mock_repository.MockIUserRepository
+	jobRepo   *mock_repository.MockIAccrualJobRepository
+	storage   *mock_storage.MockIPGXStorage
+}
+
+func newCorrectionService(ctrl *gomock.Controller, policy string) (*accrual.Service, correctionMocks) {
+	m := correctionMocks{
+		client:    mock_accrual.NewMockIClient(ctrl),
+		orderRepo: mock_repository.NewMockIOrderRepository(ctrl),
+		userRepo:  mock_repository.NewMockIUserRepository(ctrl),
+		jobRepo:   mock_repository.NewMockIAccrualJobRepository(ctrl),
+		storage:   mock_storage.NewMockIPGXStorage(ctrl),
+	}
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	cfg.Accrual.CorrectionWindow = 7 * 24 * time.Hour
+	cfg.Accrual.CorrectionInterval = time.Hour
+	cfg.Accrual.CorrectionNegativeBalance = policy
+
+	service := accrual.NewService(
+		context.Background(), &cfg.Accrual, m.client,
+		m.storage, &repository.Repositories{User: m.userRepo, Order: m.orderRepo, AccrualJob: m.jobRepo}, nil,
+	)
+
+	return service, m
+}
+
+func correctionJob(until time.Time) *domain.AccrualJob {
+	return &domain.AccrualJob{
+		OrderID:      1,
+		Order:        &domain.Order{ID: 1, UserID: 2, Number: "12345", Status: domain.OrderStatusProcessed},
+		CorrectUntil: &until,
+	}
+}
+
+func TestTask_Handle_StatusProcessed_StartsCorrectionWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newCorrectionService(ctrl, "clamp")
+	order := &domain.Order{ID: 1, UserID: 2, Number: "12345", Status: domain.OrderStatusProcessing}
+
+	m.storage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	m.client.EXPECT().GetBonuses(gomock.Any(), "12345").Return(&accrual.Response{
+		OrderNumber: "12345", Status: accrual.StatusProcessed, Amount: decimal.NewFromInt(100),
+	}, nil)
+
+	// задача не удаляется, а остается для опроса исправлений
+	m.orderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Return(nil)
+	m.userRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), order.UserID).Return(nil)
+	m.jobRepo.EXPECT().AccrualJobDelete(gomock.Any(), gomock.Any()).Times(0)
+	m.jobRepo.EXPECT().AccrualJobStartCorrection(gomock.Any(), order.ID, 7*24*time.Hour, time.Hour).Return(nil)
+
+	err := accrual.NewTask(service, order).Handle()
+	assert.NoError(t, err)
+}
+
+func TestTask_Handle_Correction_PostsAdjustment(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		reported    decimal.Decimal
+		balance     decimal.Decimal
+		amount      decimal.Decimal
+		unrecovered decimal.Decimal
+	}{
+		// начислено 150 за 100 с множителем 1.5; исправленные 120 дают 180
+		{"increase", "clamp", decimal.NewFromInt(120), decimal.NewFromInt(10), decimal.NewFromInt(30), decimal.Zero},
+		{"decrease within balance", "clamp", decimal.NewFromInt(80), decimal.NewFromInt(100), decimal.NewFromInt(-30), decimal.Zero},
+		{"decrease clamped to balance", "clamp", decimal.NewFromInt(80), decimal.NewFromInt(10), decimal.NewFromInt(-10), decimal.NewFromInt(20)},
+		{"decrease below zero allowed", "allow", decimal.NewFromInt(80), decimal.NewFromInt(10), decimal.NewFromInt(-30), decimal.Zero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newCorrectionService(ctrl, tt.policy)
+			job := correctionJob(time.Now().Add(time.Hour))
+
+			m.storage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+			m.client.EXPECT().GetBonuses(gomock.Any(), "12345").Return(&accrual.Response{
+				OrderNumber: "12345", Status: accrual.StatusProcessed, Amount: tt.reported,
+			}, nil)
+			m.orderRepo.EXPECT().OrderAccrualCurrent(gomock.Any(), job.OrderID).
+				Return(decimal.NewFromInt(150), decimal.NewFromInt(100), nil)
+
+			if tt.policy == "clamp" && tt.amount.IsNegative() {
+				m.userRepo.EXPECT().UserGetBalance(gomock.Any(), job.Order.UserID).Return(&tt.balance, &decimal.Zero, nil)
+			}
+
+			// начисление заказа не меняется, разница проводится корректировкой
+			m.orderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Times(0)
+			m.orderRepo.EXPECT().OrderAdjustmentCreate(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, a domain.OrderAdjustment) error {
+					assert.Equal(t, job.OrderID, a.OrderID)
+					assert.Equal(t, job.Order.UserID, a.UserID)
+					assert.True(t, tt.amount.Equal(a.Amount), "amount %s", a.Amount)
+					assert.True(t, tt.reported.Equal(a.BaseAccrual))
+					assert.True(t, tt.unrecovered.Equal(a.Unrecovered), "unrecovered %s", a.Unrecovered)
+					return nil
+				},
+			)
+			m.userRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), job.Order.UserID).Return(nil)
+			m.jobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), time.Hour).Return(nil)
+
+			err := accrual.NewTaskFromJob(service, job).Handle()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTask_Handle_Correction_Unchanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newCorrectionService(ctrl, "clamp")
+	job := correctionJob(time.Now().Add(time.Hour))
+
+	m.client.EXPECT().GetBonuses(gomock.Any(), "12345").Return(&accrual.Response{
+		OrderNumber: "12345", Status: accrual.StatusProcessed, Amount: decimal.NewFromInt(100),
+	}, nil)
+	m.orderRepo.EXPECT().OrderAccrualCurrent(gomock.Any(), job.OrderID).
+		Return(decimal.NewFromInt(150), decimal.NewFromInt(100), nil)
+	m.orderRepo.EXPECT().OrderAdjustmentCreate(gomock.Any(), gomock.Any()).Times(0)
+	m.jobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), time.Hour).Return(nil)
+
+	err := accrual.NewTaskFromJob(service, job).Handle()
+	assert.NoError(t, err)
+}
+
+func TestTask_Handle_Correction_WindowExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newCorrectionService(ctrl, "clamp")
+	job := correctionJob(time.Now().Add(-time.Minute))
+
+	// окно закрыто: система начислений не опрашивается
+	m.client.EXPECT().GetBonuses(gomock.Any(), gomock.Any()).Times(0)
+	m.jobRepo.EXPECT().AccrualJobDelete(gomock.Any(), job.OrderID).Return(nil)
+
+	err := accrual.NewTaskFromJob(service, job).Handle()
+	assert.NoError(t, err)
+}
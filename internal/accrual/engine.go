@@ -0,0 +1,60 @@
+package accrual
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/rules"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+// встроенный расчет начислений по локальным правилам, без обращения к внешней системе;
+// результат сразу окончательный
+type Engine struct {
+	rules     *rules.RuleSet
+	orderRepo repository.IOrderRepository
+}
+
+func NewEngine(ruleSet *rules.RuleSet, orderRepo repository.IOrderRepository) *Engine {
+	return &Engine{rules: ruleSet, orderRepo: orderRepo}
+}
+
+func (e *Engine) GetBonuses(ctx context.Context, orderNumber string) (*Response, *ClientError) {
+	purchase, err := e.orderRepo.OrderPurchaseFindByNumber(ctx, orderNumber)
+	if err != nil {
+		return nil, &ClientError{error: fmt.Errorf("error loading purchase: %w", err)}
+	}
+
+	// без состава покупки рассчитывать нечего
+	if len(purchase.Items) == 0 {
+		logging.LogInfoCtx(ctx, "order "+orderNumber+" has no purchase items, marking invalid")
+		return &Response{OrderNumber: orderNumber, Status: StatusInvalid, Amount: decimal.Zero}, nil
+	}
+
+	return &Response{OrderNumber: orderNumber, Status: StatusProcessed, Amount: e.rules.Calculate(purchase)}, nil
+}
+
+// способ расчета заказа: указанный при создании или заданный в конфигурации
+func (s *Service) engineFor(order *domain.Order) domain.AccrualEngine {
+	if order.Engine != "" {
+		return order.Engine
+	}
+
+	return s.defaultEngine
+}
+
+func (s *Service) clientFor(order *domain.Order) IClient {
+	if s.engineFor(order) == domain.AccrualEngineInternal {
+		return s.engine
+	}
+
+	return s.registry.RouteOrder(order).Client
+}
+
+// задает правила встроенного расчета; без правил начисления по нему нулевые
+func (s *Service) SetRules(ruleSet *rules.RuleSet) {
+	s.engine = NewEngine(ruleSet, s.orderRepo)
+}
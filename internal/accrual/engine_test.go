@@ -0,0 +1,138 @@
+package accrual_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/rules"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testEngineRules = `
+rules:
+  - name: electronics
+    category: electronics
+    percent: 5
+`
+
+func TestEngine_GetBonuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ruleSet, err := rules.ParseRuleSet([]byte(testEngineRules))
+	require.NoError(t, err)
+
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockOrderRepo.EXPECT().OrderPurchaseFindByNumber(gomock.Any(), "12345678903").Return(&domain.Purchase{
+		Items:     []domain.PurchaseItem{{Category: "electronics", Price: decimal.NewFromInt(1000), Quantity: 2}},
+		CreatedAt: time.Now(),
+	}, nil)
+
+	res, cErr := accrual.NewEngine(ruleSet, mockOrderRepo).GetBonuses(context.Background(), "12345678903")
+	require.Nil(t, cErr)
+
+	assert.Equal(t, accrual.StatusProcessed, res.Status)
+	assert.Equal(t, "100", res.Amount.String())
+}
+
+func TestEngine_GetBonuses_NoItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockOrderRepo.EXPECT().OrderPurchaseFindByNumber(gomock.Any(), "12345678903").Return(&domain.Purchase{}, nil)
+
+	res, cErr := accrual.NewEngine(nil, mockOrderRepo).GetBonuses(context.Background(), "12345678903")
+	require.Nil(t, cErr)
+
+	assert.Equal(t, accrual.StatusInvalid, res.Status)
+}
+
+func TestEngine_GetBonuses_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockOrderRepo.EXPECT().OrderPurchaseFindByNumber(gomock.Any(), "12345678903").Return(nil, errors.New("database error"))
+
+	res, cErr := accrual.NewEngine(nil, mockOrderRepo).GetBonuses(context.Background(), "12345678903")
+
+	assert.Nil(t, res)
+	require.NotNil(t, cErr)
+	assert.False(t, cErr.IsBadResponse())
+}
+
+// заказ со встроенным расчетом не обращается к внешней системе
+func TestTask_Handle_InternalEngine(t *testing.T) {
+	tests := []struct {
+		name          string
+		orderEngine   domain.AccrualEngine
+		defaultEngine string
+	}{
+		{"selected per order", domain.AccrualEngineInternal, "external"},
+		{"selected globally", "", "internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ruleSet, err := rules.ParseRuleSet([]byte(testEngineRules))
+			require.NoError(t, err)
+
+			mockClient := mock_accrual.NewMockIClient(ctrl)
+			mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+			mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+
+			order := &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusNew, Engine: tt.orderEngine}
+
+			mockClient.EXPECT().GetBonuses(gomock.Any(), gomock.Any()).Times(0)
+			mockOrderRepo.EXPECT().OrderPurchaseFindByNumber(gomock.Any(), order.Number).Return(&domain.Purchase{
+				Items: []domain.PurchaseItem{{Category: "electronics", Price: decimal.NewFromInt(1000), Quantity: 1}},
+			}, nil)
+
+			mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, o domain.Order) error {
+					assert.Equal(t, domain.OrderStatusProcessed, o.Status)
+					assert.Equal(t, "50", o.Accrual.String())
+					return nil
+				},
+			)
+			mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any()).Return(nil)
+			mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
+
+			cfg, _ := config.NewDefault(&config.Config{})
+			cfg.Accrual.Engine = tt.defaultEngine
+
+			service := accrual.NewService(
+				context.Background(),
+				&cfg.Accrual,
+				mockClient,
+				mockStorage,
+				&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+				nil,
+			)
+			service.SetRules(ruleSet)
+
+			err = accrual.NewTask(service, order).Handle()
+			assert.NoError(t, err)
+		})
+	}
+}
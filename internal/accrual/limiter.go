@@ -0,0 +1,138 @@
+package accrual
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/logging"
+)
+
+// token bucket, общий для всех воркеров; скорость подстраивается по AIMD:
+// после каждого успешного ответа растет на шаг, после 429 падает вдвое
+type Limiter struct {
+	mu sync.Mutex
+
+	maxRate float64
+	minRate float64
+	step    float64
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(rps float64) *Limiter {
+	burst := rps
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		maxRate: rps,
+		minRate: rps / 32,
+		step:    rps / 10,
+		rate:    rps,
+		burst:   burst,
+		tokens:  burst,
+		last:    time.Now(),
+	}
+}
+
+// ждет свободный токен или отмену контекста
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		l.refill()
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (l *Limiter) Increase() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate >= l.maxRate {
+		return
+	}
+
+	l.refill()
+	l.rate += l.step
+	if l.rate > l.maxRate {
+		l.rate = l.maxRate
+	}
+}
+
+func (l *Limiter) Decrease() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	l.rate /= 2
+	if l.rate < l.minRate {
+		l.rate = l.minRate
+	}
+
+	logging.LogWarnF("accrual rate limit decreased to %.2f rps", l.rate)
+}
+
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rate
+}
+
+// вызывается под блокировкой
+func (l *Limiter) refill() {
+	now := time.Now()
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+
+	l.last = now
+}
+
+// клиент системы начислений, ограниченный Limiter
+type LimitedClient struct {
+	client  IClient
+	limiter *Limiter
+}
+
+func NewLimitedClient(client IClient, limiter *Limiter) *LimitedClient {
+	return &LimitedClient{client: client, limiter: limiter}
+}
+
+func (c *LimitedClient) GetBonuses(ctx context.Context, orderNumber string) (*Response, *ClientError) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, &ClientError{error: err}
+	}
+
+	res, err := c.client.GetBonuses(ctx, orderNumber)
+	switch {
+	case err != nil && err.HTTPStatus == http.StatusTooManyRequests:
+		c.limiter.Decrease()
+	case err == nil:
+		c.limiter.Increase()
+	}
+
+	return res, err
+}
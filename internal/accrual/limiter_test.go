@@ -0,0 +1,78 @@
+package accrual_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLimiter_Wait(t *testing.T) {
+	l := accrual.NewLimiter(20)
+
+	// запас токенов расходуется сразу, дальше - не чаще 20 в секунду
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		assert.NoError(t, l.Wait(context.Background()))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestLimiter_Wait_ContextCancelled(t *testing.T) {
+	l := accrual.NewLimiter(1)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestLimiter_AIMD(t *testing.T) {
+	l := accrual.NewLimiter(10)
+
+	l.Decrease()
+	assert.InDelta(t, 5, l.Rate(), 0.001)
+
+	l.Increase()
+	assert.InDelta(t, 6, l.Rate(), 0.001)
+
+	// скорость не превышает настроенную
+	for i := 0; i < 10; i++ {
+		l.Increase()
+	}
+	assert.InDelta(t, 10, l.Rate(), 0.001)
+
+	// и не падает ниже минимальной
+	for i := 0; i < 20; i++ {
+		l.Decrease()
+	}
+	assert.InDelta(t, 10.0/32, l.Rate(), 0.001)
+}
+
+func TestLimitedClient_GetBonuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	limiter := accrual.NewLimiter(100)
+	client := accrual.NewLimitedClient(mockClient, limiter)
+
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "1").Return(nil, accrual.NewClientError(errors.New("too many requests"), http.StatusTooManyRequests))
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "1").Return(&accrual.Response{OrderNumber: "1", Status: accrual.StatusProcessing}, nil)
+
+	_, err := client.GetBonuses(context.Background(), "1")
+	assert.NotNil(t, err)
+	assert.InDelta(t, 50, limiter.Rate(), 0.001)
+
+	_, err = client.GetBonuses(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.InDelta(t, 60, limiter.Rate(), 0.001)
+}
@@ -0,0 +1,64 @@
+package accrual
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+)
+
+// канал NOTIFY, в который триггер на accrual_jobs пишет id заказа новой задачи
+const jobsChannel = "accrual_jobs"
+
+// слушает уведомления о новых задачах и ставит их в очередь без ожидания заправки;
+// при потере соединения переподключается, пропущенные задачи подберет заправка
+func (s *Service) listenJobs(pool storage.IPGXPool) {
+	for {
+		err := storage.Listen(s.ctx, pool, jobsChannel, s.handleJobNotification)
+		if s.ctx.Err() != nil {
+			logging.LogInfo("accrual listening stopped")
+			return
+		}
+
+		logging.LogError(err, "accrual listener failed, reconnecting")
+
+		select {
+		case <-s.ctx.Done():
+			logging.LogInfo("accrual listening stopped")
+			return
+		case <-time.After(s.refillInterval):
+		}
+	}
+}
+
+func (s *Service) handleJobNotification(payload string) {
+	id, err := strconv.ParseInt(payload, 10, 32)
+	if err != nil {
+		logging.LogWarnF("invalid accrual job notification payload: %q", payload)
+		return
+	}
+
+	if err := s.Enqueue(domain.OrderID(id)); err != nil {
+		logging.LogError(err, "error enqueueing notified accrual job")
+	}
+}
+
+// захватывает задачу по заказу и сразу ставит ее в очередь; если задача уже захвачена
+// другим экземпляром или ее время не подошло, ничего не делает
+func (s *Service) Enqueue(orderID domain.OrderID) error {
+	job, err := s.jobRepo.AccrualJobClaim(s.ctx, s.instanceID, s.leaseDuration, orderID)
+	if err != nil {
+		if errors.Is(err, storage.ErrRecordNotFound) {
+			logging.LogDebugF("accrual job for order id %d is not available, skipping", orderID)
+			return nil
+		}
+		return err
+	}
+
+	s.Push(NewTaskFromJob(s, job))
+
+	return nil
+}
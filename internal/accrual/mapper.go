@@ -0,0 +1,102 @@
+package accrual
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/shopspring/decimal"
+)
+
+// преобразует тело успешного ответа провайдера в Response
+type IResponseMapper interface {
+	Map(orderNumber string, body []byte) (*Response, error)
+}
+
+// ответ в формате основной системы начислений
+type JSONMapper struct{}
+
+func (JSONMapper) Map(_ string, body []byte) (*Response, error) {
+	res := &Response{}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ответ с другими именами полей и статусов; вложенные поля задаются через точку
+type FieldMapper struct {
+	mapping config.AccrualMapping
+}
+
+func NewResponseMapper(mapping config.AccrualMapping) IResponseMapper {
+	if mapping.Order == "" && mapping.Status == "" && mapping.Accrual == "" && len(mapping.Statuses) == 0 {
+		return JSONMapper{}
+	}
+
+	return FieldMapper{mapping: mapping}
+}
+
+func (m FieldMapper) Map(orderNumber string, body []byte) (*Response, error) {
+	var doc map[string]any
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	res := &Response{OrderNumber: orderNumber, Amount: decimal.Zero}
+
+	if v, ok := lookupField(doc, fieldOr(m.mapping.Order, "order")); ok {
+		res.OrderNumber = fmt.Sprint(v)
+	}
+
+	status, ok := lookupField(doc, fieldOr(m.mapping.Status, "status"))
+	if !ok {
+		return nil, fmt.Errorf("status field is missing")
+	}
+	res.Status = AccrualStatus(fmt.Sprint(status))
+	if mapped, ok := m.mapping.Statuses[string(res.Status)]; ok {
+		res.Status = AccrualStatus(mapped)
+	}
+	if !res.Status.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAccrualStatus, status)
+	}
+
+	if v, ok := lookupField(doc, fieldOr(m.mapping.Accrual, "accrual")); ok && v != nil {
+		amount, err := decimal.NewFromString(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid accrual: %w", err)
+		}
+		res.Amount = amount
+	}
+
+	return res, nil
+}
+
+func fieldOr(field, def string) string {
+	if field == "" {
+		return def
+	}
+
+	return field
+}
+
+func lookupField(doc map[string]any, path string) (any, bool) {
+	var current any = doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
@@ -3,91 +3,207 @@
 //
 // Generated by this command:
 //
-//      mockgen -source=internal/accrual/service.go
+//	mockgen -source=internal/accrual/service.go
 //
 
 // Package mock_accrual is a generated GoMock package.
 package mock_accrual
 
 import (
-        reflect "reflect"
-        time "time"
+	context "context"
+	reflect "reflect"
+	time "time"
 
-        accrual "github.com/ex0rcist/gophermart/internal/accrual"
-        gomock "go.uber.org/mock/gomock"
+	accrual "github.com/ex0rcist/gophermart/internal/accrual"
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	decimal "github.com/shopspring/decimal"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockIService is a mock of IService interface.
 type MockIService struct {
-        ctrl     *gomock.Controller
-        recorder *MockIServiceMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockIServiceMockRecorder
 }
 
 // MockIServiceMockRecorder is the mock recorder for MockIService.
 type MockIServiceMockRecorder struct {
-        mock *MockIService
+	mock *MockIService
 }
 
 // NewMockIService creates a new mock instance.
 func NewMockIService(ctrl *gomock.Controller) *MockIService {
-        mock := &MockIService{ctrl: ctrl}
-        mock.recorder = &MockIServiceMockRecorder{mock}
-        return mock
+	mock := &MockIService{ctrl: ctrl}
+	mock.recorder = &MockIServiceMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockIService) EXPECT() *MockIServiceMockRecorder {
-        return m.recorder
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockIService) Apply(ctx context.Context, res accrual.Response) (accrual.ApplyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, res)
+	ret0, _ := ret[0].(accrual.ApplyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockIServiceMockRecorder) Apply(ctx, res any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockIService)(nil).Apply), ctx, res)
+}
+
+// Approve mocks base method.
+func (m *MockIService) Approve(ctx context.Context, number string, amount *decimal.Decimal) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, number, amount)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockIServiceMockRecorder) Approve(ctx, number, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockIService)(nil).Approve), ctx, number, amount)
+}
+
+// CircuitState mocks base method.
+func (m *MockIService) CircuitState() accrual.CircuitState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CircuitState")
+	ret0, _ := ret[0].(accrual.CircuitState)
+	return ret0
+}
+
+// CircuitState indicates an expected call of CircuitState.
+func (mr *MockIServiceMockRecorder) CircuitState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CircuitState", reflect.TypeOf((*MockIService)(nil).CircuitState))
 }
 
 // GetLockedUntil mocks base method.
 func (m *MockIService) GetLockedUntil() time.Time {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "GetLockedUntil")
-        ret0, _ := ret[0].(time.Time)
-        return ret0
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLockedUntil")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
 }
 
 // GetLockedUntil indicates an expected call of GetLockedUntil.
 func (mr *MockIServiceMockRecorder) GetLockedUntil() *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockedUntil", reflect.TypeOf((*MockIService)(nil).GetLockedUntil))
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockedUntil", reflect.TypeOf((*MockIService)(nil).GetLockedUntil))
+}
+
+// PoolStats mocks base method.
+func (m *MockIService) PoolStats() accrual.PoolStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PoolStats")
+	ret0, _ := ret[0].(accrual.PoolStats)
+	return ret0
+}
+
+// PoolStats indicates an expected call of PoolStats.
+func (mr *MockIServiceMockRecorder) PoolStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PoolStats", reflect.TypeOf((*MockIService)(nil).PoolStats))
 }
 
 // Push mocks base method.
 func (m *MockIService) Push(t accrual.ITask) {
-        m.ctrl.T.Helper()
-        m.ctrl.Call(m, "Push", t)
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Push", t)
 }
 
 // Push indicates an expected call of Push.
 func (mr *MockIServiceMockRecorder) Push(t any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockIService)(nil).Push), t)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockIService)(nil).Push), t)
+}
+
+// Reject mocks base method.
+func (m *MockIService) Reject(ctx context.Context, number string) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, number)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockIServiceMockRecorder) Reject(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockIService)(nil).Reject), ctx, number)
+}
+
+// Release mocks base method.
+func (m *MockIService) Release(t accrual.ITask) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Release", t)
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIServiceMockRecorder) Release(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIService)(nil).Release), t)
+}
+
+// Resize mocks base method.
+func (m *MockIService) Resize(workers int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resize", workers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resize indicates an expected call of Resize.
+func (mr *MockIServiceMockRecorder) Resize(workers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockIService)(nil).Resize), workers)
 }
 
 // Run mocks base method.
 func (m *MockIService) Run() error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Run")
-        ret0, _ := ret[0].(error)
-        return ret0
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Run indicates an expected call of Run.
 func (mr *MockIServiceMockRecorder) Run() *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockIService)(nil).Run))
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockIService)(nil).Run))
 }
 
 // SetLockedUntil mocks base method.
 func (m *MockIService) SetLockedUntil(lockedUntil time.Time) {
-        m.ctrl.T.Helper()
-        m.ctrl.Call(m, "SetLockedUntil", lockedUntil)
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLockedUntil", lockedUntil)
 }
 
 // SetLockedUntil indicates an expected call of SetLockedUntil.
 func (mr *MockIServiceMockRecorder) SetLockedUntil(lockedUntil any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLockedUntil", reflect.TypeOf((*MockIService)(nil).SetLockedUntil), lockedUntil)
-}
\ No newline at end of file
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLockedUntil", reflect.TypeOf((*MockIService)(nil).SetLockedUntil), lockedUntil)
+}
+
+// Shutdown mocks base method.
+func (m *MockIService) Shutdown(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shutdown", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockIServiceMockRecorder) Shutdown(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockIService)(nil).Shutdown), ctx)
+}
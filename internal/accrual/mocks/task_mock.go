@@ -3,51 +3,65 @@
 //
 // Generated by this command:
 //
-//      mockgen -source=internal/accrual/task.go
+//	mockgen -source=internal/accrual/task.go
 //
 
 // Package mock_accrual is a generated GoMock package.
 package mock_accrual
 
 import (
-        reflect "reflect"
+	reflect "reflect"
 
-        gomock "go.uber.org/mock/gomock"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockITask is a mock of ITask interface.
 type MockITask struct {
-        ctrl     *gomock.Controller
-        recorder *MockITaskMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockITaskMockRecorder
 }
 
 // MockITaskMockRecorder is the mock recorder for MockITask.
 type MockITaskMockRecorder struct {
-        mock *MockITask
+	mock *MockITask
 }
 
 // NewMockITask creates a new mock instance.
 func NewMockITask(ctrl *gomock.Controller) *MockITask {
-        mock := &MockITask{ctrl: ctrl}
-        mock.recorder = &MockITaskMockRecorder{mock}
-        return mock
+	mock := &MockITask{ctrl: ctrl}
+	mock.recorder = &MockITaskMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockITask) EXPECT() *MockITaskMockRecorder {
-        return m.recorder
+	return m.recorder
 }
 
 // Handle mocks base method.
 func (m *MockITask) Handle() error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "Handle")
-        ret0, _ := ret[0].(error)
-        return ret0
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handle")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Handle indicates an expected call of Handle.
 func (mr *MockITaskMockRecorder) Handle() *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockITask)(nil).Handle))
-}
\ No newline at end of file
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockITask)(nil).Handle))
+}
+
+// Key mocks base method.
+func (m *MockITask) Key() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Key")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Key indicates an expected call of Key.
+func (mr *MockITaskMockRecorder) Key() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Key", reflect.TypeOf((*MockITask)(nil).Key))
+}
@@ -0,0 +1,133 @@
+package accrual
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+)
+
+// имя основной системы начислений, заданной ACCRUAL_SYSTEM_ADDRESS
+const DefaultProvider = "default"
+
+// система начислений со своими circuit breaker и ограничением частоты
+type Provider struct {
+	Name    string
+	Client  IClient
+	Breaker *Breaker
+	Limiter *Limiter
+}
+
+// оборачивает клиента провайдера: при недоступности запросы приостанавливаются,
+// ожидание токена происходит до breaker, чтобы отмена контекста не считалась недоступностью
+func NewProvider(name string, client IClient, failureThreshold int, probeInterval time.Duration, rateLimit float64) *Provider {
+	p := &Provider{Name: name}
+
+	if failureThreshold > 0 {
+		p.Breaker = NewBreaker(failureThreshold, probeInterval)
+		client = NewBreakerClient(client, p.Breaker)
+	}
+
+	if rateLimit > 0 {
+		p.Limiter = NewLimiter(rateLimit)
+		client = NewLimitedClient(client, p.Limiter)
+	}
+
+	p.Client = client
+
+	return p
+}
+
+type route struct {
+	prefix   string
+	provider *Provider
+}
+
+// направляет запрос к провайдеру по магазину заказа или по самому длинному совпавшему
+// префиксу номера, остальные заказы - к провайдеру по умолчанию
+type Registry struct {
+	fallback *Provider
+	routes   []route
+	stores   map[string]*Provider
+}
+
+func NewRegistry(fallback *Provider) *Registry {
+	return &Registry{fallback: fallback, stores: make(map[string]*Provider)}
+}
+
+// собирает реестр из основной системы начислений и провайдеров из конфигурации;
+// при заданном audit обращения к провайдерам сохраняются в журнал
+func NewRegistryFromConfig(cfg *config.Accrual, main IClient, audit IAuditLog) *Registry {
+	registry := NewRegistry(NewProvider(DefaultProvider, main, cfg.BreakerFailureThreshold, cfg.BreakerProbeInterval, cfg.RateLimit))
+
+	for _, pc := range cfg.Providers {
+		timeout := pc.Timeout
+		if timeout == 0 {
+			timeout = cfg.Timeout
+		}
+
+		path := pc.Path
+		if path == "" {
+			path = DefaultPath
+		}
+
+		client := NewMappedClient(pc.Address, path, NewResponseMapper(pc.Mapping), timeout)
+		if audit != nil {
+			client.WithAudit(pc.Name, audit)
+		}
+
+		provider := NewProvider(pc.Name, client, cfg.BreakerFailureThreshold, cfg.BreakerProbeInterval, pc.RateLimit)
+		registry.Add(provider, pc.Prefixes, pc.Default)
+		registry.AddStores(provider, pc.Stores)
+	}
+
+	return registry
+}
+
+func (r *Registry) Add(p *Provider, prefixes []string, isDefault bool) {
+	for _, prefix := range prefixes {
+		r.routes = append(r.routes, route{prefix: prefix, provider: p})
+	}
+
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return len(r.routes[i].prefix) > len(r.routes[j].prefix)
+	})
+
+	if isDefault {
+		r.fallback = p
+	}
+}
+
+// заказы этих магазинов направляются к провайдеру независимо от номера
+func (r *Registry) AddStores(p *Provider, stores []string) {
+	for _, store := range stores {
+		r.stores[store] = p
+	}
+}
+
+// провайдер для заказа: сначала по магазину, затем по номеру
+func (r *Registry) RouteOrder(order *domain.Order) *Provider {
+	if p, ok := r.stores[order.Store]; ok && order.Store != "" {
+		return p
+	}
+
+	return r.Route(order.Number)
+}
+
+func (r *Registry) Route(orderNumber string) *Provider {
+	for _, rt := range r.routes {
+		if strings.HasPrefix(orderNumber, rt.prefix) {
+			return rt.provider
+		}
+	}
+
+	return r.fallback
+}
+
+// запрос к провайдеру, выбранному так же, как при опросе: по магазину, затем по номеру
+func (r *Registry) GetBonuses(ctx context.Context, order *domain.Order) (*Response, *ClientError) {
+	return r.RouteOrder(order).Client.GetBonuses(ctx, order.Number)
+}
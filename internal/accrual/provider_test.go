@@ -0,0 +1,163 @@
+package accrual_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegistry_Route(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	main := accrual.NewProvider(accrual.DefaultProvider, mock_accrual.NewMockIClient(ctrl), 0, 0, 0)
+	partner := accrual.NewProvider("partner", mock_accrual.NewMockIClient(ctrl), 0, 0, 0)
+	vip := accrual.NewProvider("vip", mock_accrual.NewMockIClient(ctrl), 0, 0, 0)
+
+	registry := accrual.NewRegistry(main)
+	registry.Add(partner, []string{"77"}, false)
+	registry.Add(vip, []string{"779"}, false)
+
+	assert.Equal(t, "partner", registry.Route("7712345678").Name)
+	// выигрывает самый длинный префикс
+	assert.Equal(t, "vip", registry.Route("7791234567").Name)
+	assert.Equal(t, accrual.DefaultProvider, registry.Route("12345678903").Name)
+
+	// провайдер по умолчанию можно заменить конфигурацией
+	registry.Add(accrual.NewProvider("fallback", mock_accrual.NewMockIClient(ctrl), 0, 0, 0), nil, true)
+	assert.Equal(t, "fallback", registry.Route("12345678903").Name)
+}
+
+func TestRegistry_RouteOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	main := accrual.NewProvider(accrual.DefaultProvider, mock_accrual.NewMockIClient(ctrl), 0, 0, 0)
+	partner := accrual.NewProvider("partner", mock_accrual.NewMockIClient(ctrl), 0, 0, 0)
+	shop := accrual.NewProvider("shop", mock_accrual.NewMockIClient(ctrl), 0, 0, 0)
+
+	registry := accrual.NewRegistry(main)
+	registry.Add(partner, []string{"77"}, false)
+	registry.AddStores(shop, []string{"acme"})
+
+	// магазин важнее префикса номера
+	assert.Equal(t, "shop", registry.RouteOrder(&domain.Order{Number: "7712345678", Store: "acme"}).Name)
+	assert.Equal(t, "shop", registry.RouteOrder(&domain.Order{Number: "12345678903", Store: "acme"}).Name)
+
+	// без магазина или с неизвестным магазином - по номеру
+	assert.Equal(t, "partner", registry.RouteOrder(&domain.Order{Number: "7712345678"}).Name)
+	assert.Equal(t, "partner", registry.RouteOrder(&domain.Order{Number: "7712345678", Store: "other"}).Name)
+	assert.Equal(t, accrual.DefaultProvider, registry.RouteOrder(&domain.Order{Number: "12345678903", Store: "other"}).Name)
+}
+
+// магазин и префикс номера указывают на разных провайдеров: запрос уходит по магазину
+func TestRegistry_GetBonuses_StoreOverridesPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	partnerClient := mock_accrual.NewMockIClient(ctrl)
+	shopClient := mock_accrual.NewMockIClient(ctrl)
+
+	registry := accrual.NewRegistry(accrual.NewProvider(accrual.DefaultProvider, mock_accrual.NewMockIClient(ctrl), 0, 0, 0))
+	registry.Add(accrual.NewProvider("partner", partnerClient, 0, 0, 0), []string{"77"}, false)
+	registry.AddStores(accrual.NewProvider("shop", shopClient, 0, 0, 0), []string{"acme"})
+
+	partnerClient.EXPECT().GetBonuses(gomock.Any(), gomock.Any()).Times(0)
+	shopClient.EXPECT().GetBonuses(gomock.Any(), "7712345678").Return(&accrual.Response{OrderNumber: "7712345678", Status: accrual.StatusProcessing}, nil)
+
+	res, err := registry.GetBonuses(context.Background(), &domain.Order{Number: "7712345678", Store: "acme"})
+	assert.Nil(t, err)
+	assert.Equal(t, accrual.StatusProcessing, res.Status)
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	mainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/orders/12345678903", r.URL.Path)
+		_, _ = w.Write([]byte(`{"order":"12345678903","status":"PROCESSED","accrual":100}`))
+	}))
+	defer mainServer.Close()
+
+	partnerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/orders/7712345678/accrual", r.URL.Path)
+		_, _ = w.Write([]byte(`{"orderId":7712345678,"state":"DONE","result":{"points":"42.5"}}`))
+	}))
+	defer partnerServer.Close()
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	cfg.Accrual.Providers = []config.AccrualProvider{
+		{
+			Name:      "partner",
+			Address:   partnerServer.URL,
+			Path:      "/v2/orders/{number}/accrual",
+			Timeout:   time.Second,
+			RateLimit: 10,
+			Prefixes:  []string{"77"},
+			Stores:    []string{"partner-shop"},
+			Mapping: config.AccrualMapping{
+				Order:    "orderId",
+				Status:   "state",
+				Accrual:  "result.points",
+				Statuses: map[string]string{"DONE": "PROCESSED"},
+			},
+		},
+	}
+
+	registry := accrual.NewRegistryFromConfig(&cfg.Accrual, accrual.NewClient(mainServer.URL, time.Second), nil)
+
+	// у каждого провайдера свои breaker и ограничение частоты
+	partner := registry.Route("7712345678")
+	assert.Equal(t, "partner", partner.Name)
+	assert.NotNil(t, partner.Breaker)
+	assert.Equal(t, 10.0, partner.Limiter.Rate())
+	assert.Nil(t, registry.Route("12345678903").Limiter)
+	assert.Same(t, partner, registry.RouteOrder(&domain.Order{Number: "12345678903", Store: "partner-shop"}))
+
+	res, err := registry.GetBonuses(context.Background(), &domain.Order{Number: "7712345678"})
+	assert.Nil(t, err)
+	assert.Equal(t, "7712345678", res.OrderNumber)
+	assert.Equal(t, accrual.StatusProcessed, res.Status)
+	assert.True(t, decimal.RequireFromString("42.5").Equal(res.Amount))
+
+	res, err = registry.GetBonuses(context.Background(), &domain.Order{Number: "12345678903"})
+	assert.Nil(t, err)
+	assert.Equal(t, accrual.StatusProcessed, res.Status)
+	assert.True(t, decimal.NewFromInt(100).Equal(res.Amount))
+}
+
+func TestFieldMapper_Map(t *testing.T) {
+	mapper := accrual.NewResponseMapper(config.AccrualMapping{
+		Status:   "state",
+		Accrual:  "points",
+		Statuses: map[string]string{"PENDING": "PROCESSING", "REJECTED": "INVALID"},
+	})
+
+	res, err := mapper.Map("7712345678", []byte(`{"state":"PENDING"}`))
+	assert.NoError(t, err)
+	// номер заказа берется из запроса, если его нет в ответе
+	assert.Equal(t, "7712345678", res.OrderNumber)
+	assert.Equal(t, accrual.StatusProcessing, res.Status)
+	assert.True(t, decimal.Zero.Equal(res.Amount))
+
+	_, err = mapper.Map("7712345678", []byte(`{"state":"UNKNOWN"}`))
+	assert.ErrorIs(t, err, accrual.ErrInvalidAccrualStatus)
+
+	_, err = mapper.Map("7712345678", []byte(`{"points":1}`))
+	assert.Error(t, err)
+
+	_, err = mapper.Map("7712345678", []byte(`{"state":"REJECTED","points":"abc"}`))
+	assert.Error(t, err)
+
+	// без сопоставления полей используется формат основной системы начислений
+	_, ok := accrual.NewResponseMapper(config.AccrualMapping{}).(accrual.JSONMapper)
+	assert.True(t, ok)
+}
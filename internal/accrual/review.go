@@ -0,0 +1,120 @@
+package accrual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+var ErrOrderNotInReview = errors.New("order is not awaiting review")
+var ErrInvalidReviewAmount = errors.New("accrual amount must not be negative")
+
+// причина отправить начисление на ручную проверку; пустая - начисление выглядит корректным
+func (s *Service) checkAmount(ctx context.Context, order *domain.Order, amount decimal.Decimal) (string, error) {
+	if amount.IsNegative() {
+		return fmt.Sprintf("negative accrual %s", amount), nil
+	}
+
+	if s.reviewMaxAmount.IsPositive() && amount.GreaterThan(s.reviewMaxAmount) {
+		return fmt.Sprintf("accrual %s exceeds max %s per order", amount, s.reviewMaxAmount), nil
+	}
+
+	// сравнение со стоимостью возможно, только если состав покупки передан с заказом
+	if s.reviewMaxRatio.IsPositive() && amount.IsPositive() {
+		purchase, err := s.orderRepo.OrderPurchaseFindByNumber(ctx, order.Number)
+		if err != nil {
+			return "", err
+		}
+
+		total := purchase.Total()
+		if total.IsPositive() && amount.GreaterThan(total.Mul(s.reviewMaxRatio)) {
+			return fmt.Sprintf("accrual %s exceeds %s of purchase amount %s", amount, s.reviewMaxRatio, total), nil
+		}
+	}
+
+	return "", nil
+}
+
+// откладывает начисление до решения администратора; заказ больше не опрашивается
+func (t Task) holdForReview(ctx context.Context, amount decimal.Decimal, reason string) error {
+	logging.LogWarnCtx(ctx, fmt.Sprintf("%s held for review: %s", t.order, reason))
+
+	return t.service.storage.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		order := domain.Order{ID: t.order.ID, ReviewAccrual: amount, ReviewReason: reason}
+		if err := t.service.orderRepo.OrderMarkForReview(ctx, order); err != nil {
+			return err
+		}
+
+		return t.service.jobRepo.AccrualJobDelete(ctx, t.order.ID)
+	})
+}
+
+// начисляет отложенную сумму или amount, если администратор ее исправил;
+// статус проверяется в той же транзакции, что и обновление, поэтому из параллельных решений
+// по заказу применяется только одно
+func (s *Service) Approve(ctx context.Context, number string, amount *decimal.Decimal) (*domain.Order, error) {
+	err := s.storage.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		order, err := s.findInReview(ctx, number)
+		if err != nil {
+			return err
+		}
+
+		accrual := order.ReviewAccrual
+		if amount != nil {
+			accrual = *amount
+		}
+
+		if accrual.IsNegative() {
+			return ErrInvalidReviewAmount
+		}
+
+		logging.LogInfoCtx(ctx, fmt.Sprintf("%s approved after review, accrual=%s", order, accrual))
+
+		return NewTask(s, order).updateOrder(ctx, domain.OrderStatusProcessed, accrual)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orderRepo.OrderFindByNumber(ctx, number)
+}
+
+// отклоняет начисление: заказ становится INVALID
+func (s *Service) Reject(ctx context.Context, number string) (*domain.Order, error) {
+	err := s.storage.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		order, err := s.findInReview(ctx, number)
+		if err != nil {
+			return err
+		}
+
+		logging.LogInfoCtx(ctx, fmt.Sprintf("%s rejected after review", order))
+
+		return NewTask(s, order).updateOrder(ctx, domain.OrderStatusInvalid, decimal.Zero)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orderRepo.OrderFindByNumber(ctx, number)
+}
+
+func (s *Service) findInReview(ctx context.Context, number string) (*domain.Order, error) {
+	order, err := s.orderRepo.OrderFindByNumber(ctx, number)
+	if err != nil {
+		if errors.Is(err, storage.ErrRecordNotFound) {
+			return nil, ErrUnknownOrder
+		}
+		return nil, err
+	}
+
+	if !order.AwaitsReview() {
+		return nil, ErrOrderNotInReview
+	}
+
+	return order, nil
+}
@@ -0,0 +1,294 @@
+package accrual_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/memory"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTask_Handle_HoldsSuspiciousAccrual(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   decimal.Decimal
+		maxSum   float64
+		maxRatio float64
+		purchase *domain.Purchase
+		reason   string
+	}{
+		{"negative", decimal.NewFromInt(-10), 0, 0, nil, "negative accrual"},
+		{"above max amount", decimal.NewFromInt(1500), 1000, 0, nil, "exceeds max 1000"},
+		{
+			"above purchase ratio", decimal.NewFromInt(60), 0, 0.5,
+			&domain.Purchase{Items: []domain.PurchaseItem{{Price: decimal.NewFromInt(100), Quantity: 1}}},
+			"of purchase amount 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_accrual.NewMockIClient(ctrl)
+			mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+			mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+
+			order := &domain.Order{ID: 1, UserID: 2, Number: "12345", Status: domain.OrderStatusProcessing}
+
+			mockClient.EXPECT().GetBonuses(gomock.Any(), "12345").Return(&accrual.Response{
+				OrderNumber: "12345",
+				Status:      accrual.StatusProcessed,
+				Amount:      tt.amount,
+			}, nil)
+
+			if tt.purchase != nil {
+				mockOrderRepo.EXPECT().OrderPurchaseFindByNumber(gomock.Any(), "12345").Return(tt.purchase, nil)
+			}
+
+			// баланс не меняется, заказ уходит в очередь проверки
+			mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Times(0)
+			mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any()).Times(0)
+			mockOrderRepo.EXPECT().OrderMarkForReview(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, o domain.Order) error {
+					assert.Equal(t, order.ID, o.ID)
+					assert.True(t, tt.amount.Equal(o.ReviewAccrual))
+					assert.Contains(t, o.ReviewReason, tt.reason)
+					return nil
+				},
+			)
+			mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
+
+			cfg, _ := config.NewDefault(&config.Config{})
+			cfg.Accrual.ReviewMaxAmount = tt.maxSum
+			cfg.Accrual.ReviewMaxPurchaseRatio = tt.maxRatio
+
+			service := accrual.NewService(
+				context.Background(), &cfg.Accrual, mockClient,
+				mockStorage, &repository.Repositories{
+					User:       mockUserRepo,
+					Order:      mockOrderRepo,
+					AccrualJob: mockJobRepo,
+				}, nil,
+			)
+
+			err := accrual.NewTask(service, order).Handle()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Approve(t *testing.T) {
+	override := decimal.NewFromInt(300)
+
+	tests := []struct {
+		name     string
+		amount   *decimal.Decimal
+		expected decimal.Decimal
+	}{
+		{"held amount", nil, decimal.NewFromInt(1500)},
+		{"corrected amount", &override, override},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+			mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx).Times(2)
+
+			order := &domain.Order{
+				ID: 1, UserID: 2, Number: "12345678903",
+				Status: domain.OrderStatusNeedsReview, ReviewAccrual: decimal.NewFromInt(1500),
+			}
+			processed := &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusProcessed, Accrual: tt.expected}
+
+			gomock.InOrder(
+				mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(order, nil),
+				mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, o domain.Order) error {
+						assert.Equal(t, domain.OrderStatusProcessed, o.Status)
+						assert.True(t, tt.expected.Equal(o.Accrual))
+						return nil
+					},
+				),
+				mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(processed, nil),
+			)
+			mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), order.UserID).Return(nil)
+			mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
+
+			cfg, _ := config.NewDefault(&config.Config{})
+			service := accrual.NewService(
+				context.Background(), &cfg.Accrual, mock_accrual.NewMockIClient(ctrl),
+				mockStorage, &repository.Repositories{
+					User:       mockUserRepo,
+					Order:      mockOrderRepo,
+					AccrualJob: mockJobRepo,
+				}, nil,
+			)
+
+			result, err := service.Approve(context.Background(), "12345678903", tt.amount)
+
+			assert.NoError(t, err)
+			assert.Equal(t, processed, result)
+		})
+	}
+}
+
+func TestService_Reject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx).Times(2)
+
+	order := &domain.Order{ID: 1, UserID: 2, Number: "12345678903", Status: domain.OrderStatusNeedsReview, ReviewAccrual: decimal.NewFromInt(-10)}
+	invalid := &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusInvalid}
+
+	gomock.InOrder(
+		mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(order, nil),
+		mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, o domain.Order) error {
+				assert.Equal(t, domain.OrderStatusInvalid, o.Status)
+				assert.True(t, o.Accrual.IsZero())
+				return nil
+			},
+		),
+		mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(invalid, nil),
+	)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), order.UserID).Return(nil).AnyTimes()
+	mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(), &cfg.Accrual, mock_accrual.NewMockIClient(ctrl),
+		mockStorage, &repository.Repositories{
+			User:       mockUserRepo,
+			Order:      mockOrderRepo,
+			AccrualJob: mockJobRepo,
+		}, nil,
+	)
+
+	result, err := service.Reject(context.Background(), "12345678903")
+
+	assert.NoError(t, err)
+	assert.Equal(t, invalid, result)
+}
+
+func TestService_Approve_Errors(t *testing.T) {
+	negative := decimal.NewFromInt(-1)
+
+	tests := []struct {
+		name     string
+		order    *domain.Order
+		findErr  error
+		amount   *decimal.Decimal
+		expected error
+	}{
+		{"unknown order", nil, storage.ErrRecordNotFound, nil, accrual.ErrUnknownOrder},
+		{"not in review", &domain.Order{Status: domain.OrderStatusProcessed}, nil, nil, accrual.ErrOrderNotInReview},
+		{"negative amount", &domain.Order{Status: domain.OrderStatusNeedsReview}, nil, &negative, accrual.ErrInvalidReviewAmount},
+		{"repo error", nil, errors.New("database error"), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+			mockOrderRepo.EXPECT().OrderFindByNumber(gomock.Any(), "12345678903").Return(tt.order, tt.findErr)
+			mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Times(0)
+
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+			mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+
+			cfg, _ := config.NewDefault(&config.Config{})
+			service := accrual.NewService(
+				context.Background(), &cfg.Accrual, mock_accrual.NewMockIClient(ctrl),
+				mockStorage, &repository.Repositories{
+					User:       mock_repository.NewMockIUserRepository(ctrl),
+					Order:      mockOrderRepo,
+					AccrualJob: mock_repository.NewMockIAccrualJobRepository(ctrl),
+				}, nil,
+			)
+
+			result, err := service.Approve(context.Background(), "12345678903", tt.amount)
+
+			assert.Error(t, err)
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, err)
+			}
+			assert.Nil(t, result)
+		})
+	}
+}
+
+// одновременные одобрение и отклонение: применяется только одно решение
+func TestService_ApproveAndReject_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	repos := memory.NewRepositories(s)
+
+	user, err := repos.User.UserCreate(ctx, "me", "hash")
+	require.NoError(t, err)
+	order, err := repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew})
+	require.NoError(t, err)
+	require.NoError(t, repos.Order.OrderMarkForReview(ctx, domain.Order{ID: order.ID, ReviewAccrual: decimal.NewFromInt(1500), ReviewReason: "test"}))
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	ctrl := gomock.NewController(t)
+	service := accrual.NewService(ctx, &cfg.Accrual, mock_accrual.NewMockIClient(ctrl), s, repos, nil)
+
+	var wg sync.WaitGroup
+	var approveErr, rejectErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, approveErr = service.Approve(ctx, "12345678903", nil)
+	}()
+	go func() {
+		defer wg.Done()
+		_, rejectErr = service.Reject(ctx, "12345678903")
+	}()
+	wg.Wait()
+
+	found, err := repos.Order.OrderFindByNumber(ctx, "12345678903")
+	require.NoError(t, err)
+
+	if approveErr == nil {
+		assert.ErrorIs(t, rejectErr, accrual.ErrOrderNotInReview)
+		assert.Equal(t, domain.OrderStatusProcessed, found.Status)
+	} else {
+		assert.ErrorIs(t, approveErr, accrual.ErrOrderNotInReview)
+		assert.NoError(t, rejectErr)
+		assert.Equal(t, domain.OrderStatusInvalid, found.Status)
+	}
+}
@@ -2,35 +2,104 @@ package accrual
 
 import (
 	"context"
-	"runtime"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
 	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/loyalty"
 	"github.com/ex0rcist/gophermart/internal/storage"
 	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/shopspring/decimal"
 )
 
+var ErrInvalidWorkerCount = errors.New("worker count must be positive")
+var ErrServiceStopped = errors.New("accrual service is stopped")
+var ErrShutdownTimeout = errors.New("accrual tasks abandoned on shutdown")
+
 type IService interface {
 	Push(t ITask)
+	Release(t ITask)
+	Apply(ctx context.Context, res Response) (ApplyResult, error)
+	Approve(ctx context.Context, number string, amount *decimal.Decimal) (*domain.Order, error)
+	Reject(ctx context.Context, number string) (*domain.Order, error)
 	Run() error
+	Shutdown(ctx context.Context) error
+	Resize(workers int) error
+	PoolStats() PoolStats
 	SetLockedUntil(lockedUntil time.Time)
 	GetLockedUntil() time.Time
+	CircuitState() CircuitState
+}
+
+// состояние пула воркеров и очереди задач
+type PoolStats struct {
+	Workers       int `json:"workers"`
+	QueueLength   int `json:"queue_length"`
+	QueueCapacity int `json:"queue_capacity"`
+	InFlight      int `json:"in_flight"`
 }
 
 type Service struct {
-	ctx context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
 
-	client    IClient
-	storage   storage.IPGXStorage
+	registry  *Registry
+	engine    IClient
+	storage   storage.IStorage
 	userRepo  repository.IUserRepository
 	orderRepo repository.IOrderRepository
+	jobRepo   repository.IAccrualJobRepository
+	callRepo  repository.IAccrualCallRepository
+	program   *loyalty.Program
 
 	taskCh chan ITask
 
+	// номера заказов, задачи по которым ждут в очереди или выполняются
+	inFlightMu sync.Mutex
+	inFlight   map[string]struct{}
+
+	// функции остановки запущенных воркеров, по одной на воркер
+	workersMu     sync.Mutex
+	workers       []context.CancelFunc
+	workersWg     sync.WaitGroup
+	workerCount   int
+	queueCapacity int
+
 	contextTimeout time.Duration
 	refillInterval time.Duration
+	listen         bool
+	callsRetention time.Duration
+
+	// способ расчета заказов, для которых он не указан при создании
+	defaultEngine domain.AccrualEngine
+
+	// пороги, выше которых начисление уходит на ручную проверку, 0 - без ограничения
+	reviewMaxAmount decimal.Decimal
+	reviewMaxRatio  decimal.Decimal
 
+	// окно и интервал опроса обработанных заказов для учета исправлений начисления
+	correctionWindow        time.Duration
+	correctionInterval      time.Duration
+	correctionAllowNegative bool
+
+	// аренда заказов позволяет нескольким экземплярам делить работу без дублей
+	instanceID    string
+	leaseDuration time.Duration
+	batchSize     int
+
+	retryBaseDelay    time.Duration
+	retryMaxDelay     time.Duration
+	maxFailedAttempts int
+
+	lockedMu    sync.RWMutex
 	lockedUntil time.Time
 }
 
@@ -38,40 +107,84 @@ func NewService(
 	ctx context.Context,
 	config *config.Accrual,
 	client IClient,
-	storage storage.IPGXStorage,
-	userRepo repository.IUserRepository,
-	orderRepo repository.IOrderRepository,
+	storage storage.IStorage,
+	repos *repository.Repositories,
+	program *loyalty.Program,
 ) *Service {
+	userRepo, orderRepo, jobRepo := repos.User, repos.Order, repos.AccrualJob
+
+	// обращения реальных клиентов сохраняются для разбора спорных начислений
+	var audit IAuditLog
+	var callRepo repository.IAccrualCallRepository
 	if client == nil {
-		client = NewClient(config.Address, config.Timeout)
+		callRepo = repos.AccrualCall
+		audit = NewAuditLog(callRepo, config.Timeout)
+		client = NewClient(config.Address, config.Timeout).WithAudit(DefaultProvider, audit)
 	}
 
-	if userRepo == nil {
-		userRepo = repository.NewUserRepository(storage.GetPool())
-	}
+	// у каждой системы начислений свои circuit breaker и ограничение частоты,
+	// общие для всех воркеров; заказы распределяются по магазину или префиксу номера
+	registry := NewRegistryFromConfig(config, client, audit)
 
-	if orderRepo == nil {
-		orderRepo = repository.NewOrderRepository(storage.GetPool())
+	instanceID := config.InstanceID
+	if instanceID == "" {
+		instanceID = generateInstanceID()
 	}
 
+	workerCount := max(config.Workers, 1)
+	queueCapacity := max(config.QueueCapacity, 1)
+
+	// остановка приложения или Shutdown прекращают прием задач
+	ctx, cancel := context.WithCancel(ctx)
+
 	return &Service{
-		ctx: ctx,
+		ctx:    ctx,
+		cancel: cancel,
 
-		client:    client,
+		registry:  registry,
+		engine:    NewEngine(nil, orderRepo),
 		storage:   storage,
 		userRepo:  userRepo,
 		orderRepo: orderRepo,
+		jobRepo:   jobRepo,
+		callRepo:  callRepo,
+		program:   program,
+
+		taskCh:   make(chan ITask, queueCapacity),
+		inFlight: make(map[string]struct{}),
 
-		taskCh: make(chan ITask),
+		workerCount:   workerCount,
+		queueCapacity: queueCapacity,
 
 		contextTimeout: config.Timeout,
 		refillInterval: config.RefillInterval,
+		listen:         config.Listen,
+		callsRetention: config.CallsRetention,
+
+		defaultEngine: domain.AccrualEngine(config.Engine),
+
+		reviewMaxAmount: decimal.NewFromFloat(config.ReviewMaxAmount),
+		reviewMaxRatio:  decimal.NewFromFloat(config.ReviewMaxPurchaseRatio),
+
+		correctionWindow:        config.CorrectionWindow,
+		correctionInterval:      config.CorrectionInterval,
+		correctionAllowNegative: config.CorrectionNegativeBalance == "allow",
+
+		instanceID:    instanceID,
+		leaseDuration: config.LeaseDuration,
+		batchSize:     config.BatchSize,
+
+		retryBaseDelay:    config.RetryBaseDelay,
+		retryMaxDelay:     config.RetryMaxDelay,
+		maxFailedAttempts: config.MaxFailedAttempts,
 	}
 }
 
 func (s *Service) Run() error {
-	logging.LogInfoF("starting accrual service, spawning %d workers", runtime.NumCPU())
-	s.spawnWorkers()
+	logging.LogInfoF("starting accrual service as %s, spawning %d workers", s.instanceID, s.workerCount)
+	if err := s.Resize(s.workerCount); err != nil {
+		return err
+	}
 
 	err := s.refillChannel()
 	if err != nil {
@@ -79,6 +192,16 @@ func (s *Service) Run() error {
 		return err
 	}
 
+	// новые заказы опрашиваются сразу по уведомлению, заправка остается страховкой;
+	// LISTEN/NOTIFY есть только у PostgreSQL, в остальных хранилищах задачи подбирает только заправка
+	if pgxStorage, ok := s.storage.(storage.IPGXStorage); s.listen && ok {
+		go s.listenJobs(pgxStorage.GetPool())
+	}
+
+	if s.callRepo != nil && s.callsRetention > 0 {
+		go s.cleanupCalls()
+	}
+
 	go func() {
 		for {
 			select {
@@ -97,47 +220,246 @@ func (s *Service) Run() error {
 	return nil
 }
 
+// ставит задачу в очередь, если по этому заказу нет задачи в очереди или в работе;
+// при переполненной очереди задача отбрасывается и будет захвачена повторно
+// после истечения аренды
 func (s *Service) Push(t ITask) {
-	s.taskCh <- t
+	key := t.Key()
+
+	s.inFlightMu.Lock()
+	if _, ok := s.inFlight[key]; ok {
+		s.inFlightMu.Unlock()
+		logging.LogDebugF("accrual task for order %s is already in flight, skipping", key)
+		return
+	}
+	s.inFlight[key] = struct{}{}
+	s.inFlightMu.Unlock()
+
+	select {
+	case s.taskCh <- t:
+	default:
+		s.Release(t)
+		logging.LogWarnF("accrual queue is full, task for order %s dropped", key)
+	}
+}
+
+// снимает отметку о задаче в работе, после чего заказ снова можно поставить в очередь
+func (s *Service) Release(t ITask) {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+
+	delete(s.inFlight, t.Key())
+}
+
+// изменяет число воркеров без перезапуска: недостающие запускаются,
+// лишние останавливаются после завершения текущей задачи
+func (s *Service) Resize(workers int) error {
+	if workers < 1 {
+		return ErrInvalidWorkerCount
+	}
+
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return ErrServiceStopped
+	}
+
+	for len(s.workers) < workers {
+		ctx, cancel := context.WithCancel(s.ctx)
+		s.workers = append(s.workers, cancel)
+
+		worker := NewWorker(s)
+		s.workersWg.Add(1)
+		go func() {
+			defer s.workersWg.Done()
+			worker.Work(ctx, s.taskCh)
+		}()
+	}
+
+	for len(s.workers) > workers {
+		last := len(s.workers) - 1
+		s.workers[last]()
+		s.workers = s.workers[:last]
+	}
+
+	if s.workerCount != workers {
+		logging.LogInfoF("accrual worker pool resized from %d to %d", s.workerCount, workers)
+	}
+	s.workerCount = workers
+
+	return nil
 }
 
+// прекращает прием задач и ждет завершения выполняемых до дедлайна ctx;
+// аренда задач, оставшихся в очереди, снимается, чтобы их сразу подхватили другие экземпляры.
+// Возвращает ErrShutdownTimeout, если к дедлайну часть задач еще выполнялась
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.workersMu.Lock()
+	s.cancel()
+	s.workers = nil
+	s.workersMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.workersWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	// задачи в очереди не начинали выполняться
+	queued := make([]string, 0, len(s.taskCh))
+	for drained := false; !drained; {
+		select {
+		case t := <-s.taskCh:
+			queued = append(queued, t.Key())
+			s.Release(t)
+		default:
+			drained = true
+		}
+	}
+
+	if len(queued) > 0 {
+		// ctx может быть уже истекшим, снятие аренды не должно от него зависеть
+		rCtx, cancel := context.WithTimeout(context.Background(), s.contextTimeout)
+		defer cancel()
+
+		if err := s.jobRepo.AccrualJobReleaseLeases(rCtx, s.instanceID, queued); err != nil {
+			logging.LogError(err, "error releasing accrual job leases")
+		}
+	}
+
+	s.inFlightMu.Lock()
+	running := make([]string, 0, len(s.inFlight))
+	for key := range s.inFlight {
+		running = append(running, key)
+	}
+	s.inFlightMu.Unlock()
+
+	logging.LogInfoF("accrual service stopped: %d queued tasks released, %d running tasks abandoned", len(queued), len(running))
+
+	if len(running) > 0 {
+		sort.Strings(running)
+		return fmt.Errorf("%w: %s", ErrShutdownTimeout, strings.Join(running, ", "))
+	}
+
+	return nil
+}
+
+func (s *Service) PoolStats() PoolStats {
+	s.workersMu.Lock()
+	workers := len(s.workers)
+	s.workersMu.Unlock()
+
+	s.inFlightMu.Lock()
+	inFlight := len(s.inFlight)
+	s.inFlightMu.Unlock()
+
+	return PoolStats{
+		Workers:       workers,
+		QueueLength:   len(s.taskCh),
+		QueueCapacity: s.queueCapacity,
+		InFlight:      inFlight,
+	}
+}
+
+// блокировка только продлевается: параллельные 429 не сокращают уже выставленную паузу
 func (s *Service) SetLockedUntil(lockedUntil time.Time) {
 	if time.Now().After(lockedUntil) {
 		return
 	}
 
-	s.lockedUntil = lockedUntil
+	s.lockedMu.Lock()
+	defer s.lockedMu.Unlock()
+
+	if lockedUntil.After(s.lockedUntil) {
+		s.lockedUntil = lockedUntil
+	}
 }
 
+// воркеры ждут до снятия блокировки по 429 или до пробного запроса разомкнутой цепи
+// провайдера по умолчанию; цепи остальных провайдеров откладывают только их заказы
 func (s *Service) GetLockedUntil() time.Time {
-	return s.lockedUntil
+	s.lockedMu.RLock()
+	lockedUntil := s.lockedUntil
+	s.lockedMu.RUnlock()
+
+	if breaker := s.registry.fallback.Breaker; breaker != nil {
+		if probeAt := breaker.ProbeAt(); probeAt.After(lockedUntil) {
+			return probeAt
+		}
+	}
+
+	return lockedUntil
 }
 
-func (s *Service) spawnWorkers() {
-	for i := 0; i < runtime.NumCPU(); i++ {
-		worker := NewWorker(s)
-		go worker.Work(s.ctx, s.taskCh)
+// время пробного запроса к системе начислений, обслуживающей заказ
+func (s *Service) probeAt(order *domain.Order) time.Time {
+	breaker := s.registry.RouteOrder(order).Breaker
+	if breaker == nil {
+		return time.Time{}
+	}
+
+	return breaker.ProbeAt()
+}
+
+func (s *Service) CircuitState() CircuitState {
+	breaker := s.registry.fallback.Breaker
+	if breaker == nil {
+		return CircuitClosed
 	}
+
+	return breaker.State()
 }
 
 func (s *Service) refillChannel() error {
 	logging.LogDebug("refilling channel...")
 
-	// частично исключаем дублирование задач
-	if len(s.taskCh) > 0 {
-		logging.LogDebug("channel still has unread tasks, skipping")
+	// захватываем не больше, чем помещается в очередь, чтобы не держать аренду
+	// заказов, которые все равно будут отброшены
+	free := s.queueCapacity - len(s.taskCh)
+	if free <= 0 {
+		logging.LogDebug("accrual queue is full, skipping")
 		return nil
 	}
 
-	orders, err := s.orderRepo.OrderListForUpdate(s.ctx)
+	jobs, err := s.jobRepo.AccrualJobClaimDue(s.ctx, s.instanceID, s.leaseDuration, min(s.batchSize, free))
 	if err != nil {
 		return err
 	}
 
-	for _, o := range orders {
-		t := Task{service: s, order: o}
-		s.Push(t)
+	logging.LogDebugF("claimed %d accrual jobs", len(jobs))
+
+	for _, j := range jobs {
+		s.Push(NewTaskFromJob(s, j))
 	}
 
 	return nil
 }
+
+// задержка перед следующим опросом заказа после attempts безрезультатных попыток подряд
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.retryBaseDelay
+	for i := 1; i < attempts && delay < s.retryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > s.retryMaxDelay {
+		delay = s.retryMaxDelay
+	}
+
+	return delay
+}
+
+func generateInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "gophermart"
+	}
+
+	return fmt.Sprintf("%s-%s", host, utils.GenerateRequestID()[:8])
+}
@@ -0,0 +1,230 @@
+package accrual_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_SetLockedUntil(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mock_accrual.NewMockIClient(ctrl),
+		mock_storage.NewMockIPGXStorage(ctrl),
+		&repository.Repositories{User: mock_repository.NewMockIUserRepository(ctrl), Order: mock_repository.NewMockIOrderRepository(ctrl), AccrualJob: mock_repository.NewMockIAccrualJobRepository(ctrl)},
+		nil,
+	)
+
+	later := time.Now().Add(time.Minute)
+	sooner := time.Now().Add(time.Second)
+
+	// параллельные 429 не сокращают уже выставленную паузу
+	var wg sync.WaitGroup
+	for _, tm := range []time.Time{later, sooner, time.Now().Add(-time.Second)} {
+		wg.Add(1)
+		go func(tm time.Time) {
+			defer wg.Done()
+			service.SetLockedUntil(tm)
+		}(tm)
+	}
+	wg.Wait()
+
+	assert.Equal(t, later, service.GetLockedUntil())
+}
+
+func newPoolTestService(ctx context.Context, ctrl *gomock.Controller, workers, queueCapacity int) *accrual.Service {
+	return newPoolTestServiceWithJobs(ctx, ctrl, workers, queueCapacity, mock_repository.NewMockIAccrualJobRepository(ctrl))
+}
+
+func newPoolTestServiceWithJobs(
+	ctx context.Context,
+	ctrl *gomock.Controller,
+	workers, queueCapacity int,
+	jobRepo *mock_repository.MockIAccrualJobRepository,
+) *accrual.Service {
+	cfg, _ := config.NewDefault(&config.Config{})
+	cfg.Accrual.Workers = workers
+	cfg.Accrual.QueueCapacity = queueCapacity
+
+	return accrual.NewService(
+		ctx,
+		&cfg.Accrual,
+		mock_accrual.NewMockIClient(ctrl),
+		mock_storage.NewMockIPGXStorage(ctrl),
+		&repository.Repositories{User: mock_repository.NewMockIUserRepository(ctrl), Order: mock_repository.NewMockIOrderRepository(ctrl), AccrualJob: jobRepo},
+		nil,
+	)
+}
+
+func newKeyedTask(ctrl *gomock.Controller, key string) *mock_accrual.MockITask {
+	task := mock_accrual.NewMockITask(ctrl)
+	task.EXPECT().Key().Return(key).AnyTimes()
+
+	return task
+}
+
+func TestService_Push_DeduplicatesInFlightOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := newPoolTestService(context.Background(), ctrl, 1, 10)
+
+	first := newKeyedTask(ctrl, "12345678903")
+	service.Push(first)
+	service.Push(newKeyedTask(ctrl, "12345678903"))
+	service.Push(newKeyedTask(ctrl, "9278923470"))
+
+	stats := service.PoolStats()
+	assert.Equal(t, 2, stats.QueueLength)
+	assert.Equal(t, 2, stats.InFlight)
+
+	// после обработки заказ снова можно поставить в очередь
+	service.Release(first)
+	service.Push(newKeyedTask(ctrl, "12345678903"))
+
+	stats = service.PoolStats()
+	assert.Equal(t, 3, stats.QueueLength)
+	assert.Equal(t, 2, stats.InFlight)
+}
+
+func TestService_Push_DropsWhenQueueIsFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := newPoolTestService(context.Background(), ctrl, 1, 1)
+
+	service.Push(newKeyedTask(ctrl, "12345678903"))
+	service.Push(newKeyedTask(ctrl, "9278923470"))
+
+	stats := service.PoolStats()
+	assert.Equal(t, 1, stats.QueueLength)
+	assert.Equal(t, 1, stats.QueueCapacity)
+	// отброшенная задача не считается в работе и будет захвачена повторно
+	assert.Equal(t, 1, stats.InFlight)
+}
+
+func TestService_Resize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service := newPoolTestService(ctx, ctrl, 2, 10)
+
+	assert.NoError(t, service.Resize(4))
+	assert.Equal(t, 4, service.PoolStats().Workers)
+
+	assert.NoError(t, service.Resize(1))
+	assert.Equal(t, 1, service.PoolStats().Workers)
+
+	assert.ErrorIs(t, service.Resize(0), accrual.ErrInvalidWorkerCount)
+	assert.Equal(t, 1, service.PoolStats().Workers)
+}
+
+func TestService_Enqueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	service := newPoolTestServiceWithJobs(context.Background(), ctrl, 1, 10, jobRepo)
+
+	job := &domain.AccrualJob{
+		OrderID: 1,
+		Order:   &domain.Order{ID: 1, Number: "12345678903", Status: domain.OrderStatusNew},
+	}
+
+	jobRepo.EXPECT().AccrualJobClaim(gomock.Any(), gomock.Any(), gomock.Any(), domain.OrderID(1)).Return(job, nil)
+	// задача уже захвачена другим экземпляром
+	jobRepo.EXPECT().AccrualJobClaim(gomock.Any(), gomock.Any(), gomock.Any(), domain.OrderID(2)).Return(nil, storage.ErrRecordNotFound)
+	jobRepo.EXPECT().AccrualJobClaim(gomock.Any(), gomock.Any(), gomock.Any(), domain.OrderID(3)).Return(nil, errors.New("database error"))
+
+	assert.NoError(t, service.Enqueue(1))
+	assert.NoError(t, service.Enqueue(2))
+	assert.Error(t, service.Enqueue(3))
+
+	stats := service.PoolStats()
+	assert.Equal(t, 1, stats.QueueLength)
+	assert.Equal(t, 1, stats.InFlight)
+}
+
+func TestService_Shutdown_WaitsForRunningTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := newPoolTestService(context.Background(), ctrl, 1, 10)
+
+	started := make(chan struct{})
+	task := newKeyedTask(ctrl, "12345678903")
+	task.EXPECT().Handle().DoAndReturn(func() error {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	service.Push(task)
+	assert.NoError(t, service.Resize(1))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, service.Shutdown(ctx))
+	assert.Equal(t, 0, service.PoolStats().InFlight)
+
+	// после остановки воркеры не запускаются
+	assert.ErrorIs(t, service.Resize(1), accrual.ErrServiceStopped)
+}
+
+func TestService_Shutdown_ReportsAbandonedTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	service := newPoolTestServiceWithJobs(context.Background(), ctrl, 1, 10, jobRepo)
+
+	started := make(chan struct{})
+	running := newKeyedTask(ctrl, "12345678903")
+	running.EXPECT().Handle().DoAndReturn(func() error {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		return nil
+	})
+
+	service.Push(running)
+	assert.NoError(t, service.Resize(1))
+	<-started
+
+	// задача в очереди не начата: аренда снимается, чтобы ее подхватили другие экземпляры
+	service.Push(newKeyedTask(ctrl, "9278923470"))
+	jobRepo.EXPECT().AccrualJobReleaseLeases(gomock.Any(), gomock.Any(), []string{"9278923470"}).Return(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := service.Shutdown(ctx)
+	assert.ErrorIs(t, err, accrual.ErrShutdownTimeout)
+	assert.Contains(t, err.Error(), "12345678903")
+	assert.Equal(t, 0, service.PoolStats().QueueLength)
+
+	// даем воркеру завершиться до проверки ожиданий мока
+	time.Sleep(500 * time.Millisecond)
+}
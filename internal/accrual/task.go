@@ -4,22 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/ex0rcist/gophermart/internal/domain"
 	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
 	"github.com/ex0rcist/gophermart/internal/utils"
-	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 	"github.com/shopspring/decimal"
 )
 
+// в задаче хранится только начало ответа и ошибки, чтобы большие ответы не раздували таблицу
+const maxJobTextLen = 4096
+
 type ITask interface {
 	Handle() error
+	Key() string
 }
 
 type Task struct {
-	service *Service
-	order   *domain.Order
+	service   *Service
+	order     *domain.Order
+	attempts  int
+	failures  int
+	requestID string
+
+	// окончание окна исправлений, если заказ уже обработан
+	correctUntil *time.Time
 }
 
 func NewTask(service *Service, order *domain.Order) Task {
@@ -29,89 +43,271 @@ func NewTask(service *Service, order *domain.Order) Task {
 	}
 }
 
+// задача по захваченной из очереди записи, с учетом предыдущих попыток
+func NewTaskFromJob(service *Service, job *domain.AccrualJob) Task {
+	return Task{
+		service:   service,
+		order:     job.Order,
+		attempts:  job.Attempts,
+		failures:  job.Failures,
+		requestID: job.RequestID,
+
+		correctUntil: job.CorrectUntil,
+	}
+}
+
+// задачи с одинаковым ключом не выполняются параллельно
+func (t Task) Key() string {
+	return t.order.Number
+}
+
 func (t Task) Handle() error {
 	tCtx, cancel := context.WithTimeout(context.Background(), t.service.contextTimeout)
 	defer cancel()
 
-	// внедряем общую метку в логи запросов и логи сервиса, для облегчения чтения
-	ctx := setupCtxWithRID(tCtx)
+	// внедряем метку запроса, создавшего заказ, в логи сервиса и запросы к accrual
+	ctx := setupCtxWithRID(tCtx, t.requestID, t.order.Number)
+
+	err := t.lookup(ctx)
+	if err == nil {
+		return nil
+	}
+
+	// цепь разомкнута: откладываем задачу до пробного запроса, попытка не засчитывается
+	if errors.Is(err, ErrCircuitOpen) {
+		if pErr := t.postpone(ctx, err); pErr != nil {
+			logging.LogErrorCtx(ctx, pErr, "Task: Handle(): error postponing job")
+		}
+		return err
+	}
+
+	// при 429 с Retry-After воркер вернет задачу в канал, аренда сохраняется
+	var cErr *ClientError
+	if errors.As(err, &cErr) && cErr.HTTPStatus == http.StatusTooManyRequests && cErr.RetryAfter > 0 {
+		return err
+	}
+
+	if fErr := t.fail(ctx, err); fErr != nil {
+		logging.LogErrorCtx(ctx, fErr, "Task: Handle(): error rescheduling job")
+	}
+
+	return err
+}
+
+// откладывает следующую попытку с увеличенной задержкой, а после исчерпания
+// попыток с некорректными ответами переводит задачу в dead-letter
+func (t Task) fail(ctx context.Context, cause error) error {
+	job := domain.AccrualJob{
+		OrderID:    t.order.ID,
+		Attempts:   t.attempts + 1,
+		Failures:   t.failures,
+		LastError:  storableText(cause.Error(), maxJobTextLen),
+		LeaseOwner: t.service.instanceID,
+	}
+
+	var cErr *ClientError
+	if errors.As(cause, &cErr) && cErr.IsBadResponse() {
+		job.Failures++
+		job.LastHTTPStatus = cErr.HTTPStatus
+		job.LastResponse = storableText(string(cErr.Body), maxJobTextLen)
+	}
+
+	if t.service.maxFailedAttempts > 0 && job.Failures >= t.service.maxFailedAttempts {
+		// начисление по обработанному заказу уже проведено, разбирать нечего
+		if t.inCorrection() {
+			logging.LogWarnCtx(ctx, fmt.Sprintf("%s correction polling stopped after %d failed attempts", t.order, job.Failures))
+			return t.service.jobRepo.AccrualJobDelete(ctx, t.order.ID)
+		}
+
+		logging.LogWarnCtx(ctx, fmt.Sprintf("%s moved to dead-letter after %d failed attempts", t.order, job.Failures))
+		return t.service.jobRepo.AccrualJobMarkDead(ctx, job)
+	}
+
+	delay := t.service.backoff(job.Attempts)
+	if t.inCorrection() {
+		delay = max(delay, t.service.correctionInterval)
+	}
+
+	return t.service.jobRepo.AccrualJobReschedule(ctx, job, delay)
+}
+
+func (t Task) lookup(ctx context.Context) error {
+	if t.correctionExpired() {
+		logging.LogDebugCtx(ctx, fmt.Sprintf("%s correction window is over", t.order))
+		return t.service.jobRepo.AccrualJobDelete(ctx, t.order.ID)
+	}
 
 	// получаем статус и баланс из accrual
-	res, err := t.service.client.GetBonuses(ctx, t.order.Number)
+	res, err := t.service.clientFor(t.order).GetBonuses(ctx, t.order.Number)
 	if err != nil {
 		return err
 	}
 
-	// проверяем
-	switch res.Status {
-	case StatusRegistered:
-		// только что создан
-		logging.LogInfoCtx(ctx, fmt.Sprintf("%s is just created, do nothing", t.order))
+	if t.inCorrection() {
+		return t.correct(ctx, res)
+	}
+
+	return t.apply(ctx, res)
+}
 
+// применяет ответ системы начислений к заказу
+func (t Task) apply(ctx context.Context, res *Response) error {
+	switch res.Status {
 	case StatusProcessing:
 		// в обработке; если статус в базе не совпадает, обновляем
 		logging.LogInfoCtx(ctx, fmt.Sprintf("%s is still in processing", t.order))
 		if t.order.Status != domain.OrderStatusProcessing {
-			err := t.updateOrder(ctx, domain.OrderStatusProcessing, decimal.NewFromInt(0))
-			if err != nil {
-				return err
-			}
+			return t.updateOrder(ctx, domain.OrderStatusProcessing, decimal.NewFromInt(0))
 		}
 
 	case StatusInvalid:
 		// invalid; обновляем статус
 		logging.LogInfoCtx(ctx, fmt.Sprintf("%s is invalid", t.order))
-		err := t.updateOrder(ctx, domain.OrderStatusInvalid, decimal.NewFromInt(0))
+		return t.updateOrder(ctx, domain.OrderStatusInvalid, decimal.NewFromInt(0))
+
+	case StatusProcessed:
+		// подозрительное начисление не зачисляется до ручной проверки
+		reason, err := t.service.checkAmount(ctx, t.order, res.Amount)
 		if err != nil {
 			return err
 		}
+		if reason != "" {
+			return t.holdForReview(ctx, res.Amount, reason)
+		}
 
-	case StatusProcessed:
 		// обработан; обновляем статус и сумму накоплений
 		logging.LogInfoCtx(ctx, fmt.Sprintf("%s processed, accrual=%s", t.order, res.Amount))
-		err := t.updateOrder(ctx, domain.OrderStatusProcessed, res.Amount)
-		if err != nil {
-			return err
-		}
+		return t.updateOrder(ctx, domain.OrderStatusProcessed, res.Amount)
+
+	default:
+		// только что создан
+		logging.LogInfoCtx(ctx, fmt.Sprintf("%s is just created, do nothing", t.order))
 	}
 
-	return nil
+	// статус не изменился - увеличиваем задержку до следующего опроса
+	return t.reschedule(ctx, t.attempts+1, nil)
 }
 
-func (t Task) updateOrder(ctx context.Context, status domain.OrderStatus, amount decimal.Decimal) error {
-	tx, err := t.service.storage.GetPool().Begin(ctx)
-	if err != nil {
-		return err
+func (t Task) postpone(ctx context.Context, cause error) error {
+	job := domain.AccrualJob{
+		OrderID:    t.order.ID,
+		Attempts:   t.attempts,
+		Failures:   t.failures,
+		LastError:  storableText(cause.Error(), maxJobTextLen),
+		LeaseOwner: t.service.instanceID,
 	}
-	defer func() {
-		err := tx.Rollback(ctx)
-		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			logging.LogErrorCtx(ctx, err, "Task: updateOrder(): error rolling tx back")
-		}
-	}()
 
-	err = t.service.orderRepo.OrderUpdate(ctx, tx, domain.Order{ID: t.order.ID, Status: status, Accrual: amount})
-	if err != nil {
-		return err
+	delay := t.service.retryBaseDelay
+	if probeAt := t.service.probeAt(t.order); time.Until(probeAt) > delay {
+		delay = time.Until(probeAt)
+	}
+
+	return t.service.jobRepo.AccrualJobReschedule(ctx, job, delay)
+}
+
+// откладывает следующий опрос заказа с учетом числа безрезультатных попыток
+func (t Task) reschedule(ctx context.Context, attempts int, cause error) error {
+	job := domain.AccrualJob{OrderID: t.order.ID, Attempts: attempts, LeaseOwner: t.service.instanceID}
+	if cause != nil {
+		job.LastError = storableText(cause.Error(), maxJobTextLen)
 	}
 
-	if status == domain.OrderStatusProcessed {
-		err = t.service.userRepo.UserUpdateBalanceAndWithdrawals(ctx, tx, t.order.UserID)
+	return t.service.jobRepo.AccrualJobReschedule(ctx, job, t.service.backoff(attempts))
+}
+
+func (t Task) updateOrder(ctx context.Context, status domain.OrderStatus, amount decimal.Decimal) error {
+	return t.service.storage.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		order := domain.Order{ID: t.order.ID, Status: status, Accrual: amount, BaseAccrual: amount}
+
+		// применяем множитель текущего уровня пользователя
+		var tier domain.LoyaltyTier
+		var err error
+		if status == domain.OrderStatusProcessed && t.service.program != nil {
+			tier, err = t.service.userRepo.UserGetTier(ctx, t.order.UserID)
+			if err != nil {
+				return err
+			}
+
+			order.Accrual = t.service.program.Apply(tier, amount)
+		}
+
+		err = t.service.orderRepo.OrderUpdate(ctx, order)
 		if err != nil {
 			return err
 		}
-	}
 
-	err = tx.Commit(ctx)
+		// заказ в конечном статусе больше не опрашивается, кроме окна исправлений
+		// обработанного заказа, а при смене статуса задержка сбрасывается
+		switch {
+		case status == domain.OrderStatusProcessed && t.service.correctsOrder(t.order):
+			err = t.service.jobRepo.AccrualJobStartCorrection(ctx, t.order.ID, t.service.correctionWindow, t.service.correctionInterval)
+		case status == domain.OrderStatusProcessed || status == domain.OrderStatusInvalid:
+			err = t.service.jobRepo.AccrualJobDelete(ctx, t.order.ID)
+		default:
+			err = t.reschedule(ctx, 0, nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		if status != domain.OrderStatusProcessed {
+			return nil
+		}
+
+		err = t.service.userRepo.UserUpdateBalanceAndWithdrawals(ctx, t.order.UserID)
+		if err != nil {
+			return err
+		}
+
+		if t.service.program != nil {
+			return t.updateTier(ctx, tier)
+		}
+
+		return nil
+	})
+}
+
+// пересчитывает уровень пользователя по начислениям за скользящее окно
+func (t Task) updateTier(ctx context.Context, current domain.LoyaltyTier) error {
+	since := t.service.program.WindowStart(time.Now())
+
+	earned, err := t.service.orderRepo.OrderAccrualSumSince(ctx, t.order.UserID, since)
 	if err != nil {
-		logging.LogErrorCtx(ctx, err, "Task: updateOrder(): error commiting tx")
 		return err
 	}
 
-	return nil
+	tier := t.service.program.TierFor(earned)
+	if tier == current {
+		return nil
+	}
+
+	logging.LogInfoCtx(ctx, fmt.Sprintf("user(id=%d) tier changed: %q -> %q, earned=%s", t.order.UserID, current, tier, earned))
+
+	return t.service.userRepo.UserUpdateTier(ctx, t.order.UserID, tier)
 }
 
-func setupCtxWithRID(ctx context.Context) context.Context {
-	logger := log.Logger.With().Ctx(ctx).Str("rid", utils.GenerateRequestID()).Logger()
-	return logger.WithContext(ctx)
+// начало текста не длиннее maxLen байт, пригодное для текстового столбца: некорректные
+// последовательности UTF-8 заменяются, нулевые байты удаляются, обрезка не разрывает символ
+func storableText(text string, maxLen int) string {
+	text = strings.ReplaceAll(strings.ToValidUTF8(text, "\uFFFD"), "\x00", "")
+	if len(text) <= maxLen {
+		return text
+	}
+
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+
+	return text[:cut]
+}
+
+// для заказов без сохраненного запроса метка генерируется
+func setupCtxWithRID(ctx context.Context, requestID, orderNumber string) context.Context {
+	if requestID == "" {
+		requestID = utils.GenerateRequestID()
+	}
+
+	logger := log.Logger.With().Ctx(ctx).Str("rid", requestID).Str("order", orderNumber).Logger()
+	return utils.ContextWithRequestID(logger.WithContext(ctx), requestID)
 }
@@ -2,23 +2,37 @@ package accrual_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/ex0rcist/gophermart/internal/accrual"
 	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
 	"github.com/ex0rcist/gophermart/internal/config"
 	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/loyalty"
 	"github.com/ex0rcist/gophermart/internal/storage"
-	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ex0rcist/gophermart/internal/storage/memory"
 	"go.uber.org/mock/gomock"
 
 	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
 	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/ex0rcist/gophermart/internal/utils"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// выполняет функцию транзакции без обращения к БД
+func passTx(ctx context.Context, _ storage.TxOptions, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 func TestTask_Handle_StatusRegistered(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -28,10 +42,8 @@ func TestTask_Handle_StatusRegistered(t *testing.T) {
 	mockClient := mock_accrual.NewMockIClient(ctrl)
 	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
-
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
 
 	mockClient.EXPECT().
 		GetBonuses(gomock.Any(), "12345").
@@ -41,8 +53,16 @@ func TestTask_Handle_StatusRegistered(t *testing.T) {
 			Amount:      decimal.Zero,
 		}, nil)
 
-	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Times(0)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any()).Times(0)
+
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), 5*time.Second).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob, _ time.Duration) error {
+			assert.Equal(t, order.ID, job.OrderID)
+			assert.Equal(t, 1, job.Attempts)
+			return nil
+		},
+	)
 
 	cfg, _ := config.NewDefault(&config.Config{})
 	service := accrual.NewService(
@@ -50,8 +70,8 @@ func TestTask_Handle_StatusRegistered(t *testing.T) {
 		&cfg.Accrual,
 		mockClient,
 		mockStorage,
-		mockUserRepo,
-		mockOrderRepo,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
 	)
 
 	task := accrual.NewTask(service, order)
@@ -67,11 +87,8 @@ func TestTask_Handle_StatusProcessing(t *testing.T) {
 	mockClient := mock_accrual.NewMockIClient(ctrl)
 	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
-	mockTx := new(storage.PGXTxMock)
-
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
 
 	// Тестовый заказ
 	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
@@ -86,15 +103,17 @@ func TestTask_Handle_StatusProcessing(t *testing.T) {
 		}, nil)
 
 	// Настройка транзакции
-	mockPool.EXPECT().Begin(gomock.Any()).Return(mockTx, nil)
-	mockTx.On("Commit", mock.Anything).Return(nil)
-	mockTx.On("Rollback", mock.Anything).Return(nil)
-	mockTx.
-		On("Exec", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Return(pgconn.CommandTag{}, nil)
-
-	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
-	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Times(1)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any()).Times(0)
+
+	// смена статуса сбрасывает счетчик попыток
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), 5*time.Second).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob, _ time.Duration) error {
+			assert.Equal(t, 0, job.Attempts)
+			return nil
+		},
+	)
 
 	cfg, _ := config.NewDefault(&config.Config{})
 	service := accrual.NewService(
@@ -102,8 +121,8 @@ func TestTask_Handle_StatusProcessing(t *testing.T) {
 		&cfg.Accrual,
 		mockClient,
 		mockStorage,
-		mockUserRepo,
-		mockOrderRepo,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
 	)
 
 	task := accrual.NewTask(service, order)
@@ -119,14 +138,12 @@ func TestTask_Handle_StatusInvalid(t *testing.T) {
 	mockClient := mock_accrual.NewMockIClient(ctrl)
 	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
-	mockTx := new(storage.PGXTxMock)
 
 	// Тестовый заказ
 	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
 
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
 	mockClient.EXPECT().
 		GetBonuses(gomock.Any(), "12345").
 		Return(&accrual.Response{
@@ -135,16 +152,12 @@ func TestTask_Handle_StatusInvalid(t *testing.T) {
 			Amount:      decimal.Zero,
 		}, nil)
 
-	mockPool.EXPECT().Begin(gomock.Any()).Return(mockTx, nil)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
 
-	mockTx.On("Commit", mock.Anything).Return(nil)
-	mockTx.On("Rollback", mock.Anything).Return(nil)
-	mockTx.
-		On("Exec", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Return(pgconn.CommandTag{}, nil)
+	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Times(1)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any()).Times(0)
 
-	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
-	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
 
 	cfg, _ := config.NewDefault(&config.Config{})
 
@@ -153,8 +166,8 @@ func TestTask_Handle_StatusInvalid(t *testing.T) {
 		&cfg.Accrual,
 		mockClient,
 		mockStorage,
-		mockUserRepo,
-		mockOrderRepo,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
 	)
 
 	task := accrual.NewTask(service, order)
@@ -170,12 +183,10 @@ func TestTask_Handle_StatusProcessed(t *testing.T) {
 	mockClient := mock_accrual.NewMockIClient(ctrl)
 	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
-	mockTx := new(storage.PGXTxMock)
 
-	mockPool.EXPECT().Begin(gomock.Any()).Return(mockTx, nil)
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
 
 	// Тестовый заказ
 	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
@@ -188,14 +199,10 @@ func TestTask_Handle_StatusProcessed(t *testing.T) {
 			Amount:      decimal.NewFromFloat(150.50),
 		}, nil)
 
-	mockTx.On("Commit", mock.Anything).Return(nil)
-	mockTx.On("Rollback", mock.Anything).Return(nil)
-	mockTx.
-		On("Exec", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Return(pgconn.CommandTag{}, nil)
+	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
-	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
 
 	cfg, _ := config.NewDefault(&config.Config{})
 
@@ -204,8 +211,8 @@ func TestTask_Handle_StatusProcessed(t *testing.T) {
 		&cfg.Accrual,
 		mockClient,
 		mockStorage,
-		mockUserRepo,
-		mockOrderRepo,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
 	)
 
 	task := accrual.NewTask(service, order)
@@ -213,3 +220,434 @@ func TestTask_Handle_StatusProcessed(t *testing.T) {
 	err := task.Handle()
 	assert.NoError(t, err)
 }
+
+func TestTask_Handle_StatusProcessed_WithLoyaltyProgram(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+
+	order := &domain.Order{ID: 1, UserID: 7, Number: "12345", Status: domain.OrderStatusProcessing}
+
+	program, err := loyalty.ParseProgram([]byte("window: 720h\ntiers:\n  - {name: SILVER, threshold: 100, multiplier: 1.5}\n"))
+	assert.NoError(t, err)
+
+	mockClient.EXPECT().
+		GetBonuses(gomock.Any(), "12345").
+		Return(&accrual.Response{
+			OrderNumber: "12345",
+			Status:      accrual.StatusProcessed,
+			Amount:      decimal.NewFromInt(100),
+		}, nil)
+
+	// начисление умножается на множитель текущего уровня
+	mockUserRepo.EXPECT().UserGetTier(gomock.Any(), order.UserID).Return(domain.LoyaltyTier("SILVER"), nil)
+	mockOrderRepo.EXPECT().OrderUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o domain.Order) error {
+			assert.Equal(t, domain.OrderStatusProcessed, o.Status)
+			assert.True(t, o.Accrual.Equal(decimal.NewFromInt(150)))
+			assert.True(t, o.BaseAccrual.Equal(decimal.NewFromInt(100)))
+			return nil
+		},
+	)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), order.UserID).Return(nil)
+
+	// пользователь потерял уровень: за окно начислено меньше порога
+	mockOrderRepo.EXPECT().OrderAccrualSumSince(gomock.Any(), order.UserID, gomock.Any()).Return(decimal.NewFromInt(50), nil)
+	mockUserRepo.EXPECT().UserUpdateTier(gomock.Any(), order.UserID, domain.LoyaltyTier("")).Return(nil)
+
+	mockJobRepo.EXPECT().AccrualJobDelete(gomock.Any(), order.ID).Return(nil)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		program,
+	)
+
+	task := accrual.NewTask(service, order)
+
+	err = task.Handle()
+	assert.NoError(t, err)
+}
+
+// уровень зависит от начислений системы: множитель текущего уровня не поднимает пользователя выше
+func TestTask_Handle_MultiplierDoesNotRaiseTier(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	repos := memory.NewRepositories(s)
+
+	program, err := loyalty.ParseProgram([]byte("window: 720h\ntiers:\n" +
+		"  - {name: SILVER, threshold: 100, multiplier: 1.5}\n" +
+		"  - {name: GOLD, threshold: 200, multiplier: 2}\n"))
+	require.NoError(t, err)
+
+	user, err := repos.User.UserCreate(ctx, "me", "hash")
+	require.NoError(t, err)
+	require.NoError(t, repos.User.UserUpdateTier(ctx, user.ID, "SILVER"))
+
+	first, err := repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew})
+	require.NoError(t, err)
+	require.NoError(t, repos.Order.OrderUpdate(ctx, domain.Order{
+		ID: first.ID, Status: domain.OrderStatusProcessed, Accrual: decimal.NewFromInt(150), BaseAccrual: decimal.NewFromInt(100),
+	}))
+
+	order, err := repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "2377225624", Status: domain.OrderStatusNew})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "2377225624").Return(&accrual.Response{
+		OrderNumber: "2377225624",
+		Status:      accrual.StatusProcessed,
+		Amount:      decimal.NewFromInt(90),
+	}, nil)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(ctx, &cfg.Accrual, mockClient, s, repos, program)
+
+	require.NoError(t, accrual.NewTask(service, order).Handle())
+
+	// с множителем начислено 150 + 135, но система начислила 100 + 90 - меньше порога GOLD
+	processed, err := repos.Order.OrderFindByNumber(ctx, "2377225624")
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(135).Equal(processed.Accrual))
+
+	tier, err := repos.User.UserGetTier(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LoyaltyTier("SILVER"), tier)
+}
+
+func TestTask_Handle_ClientError_Reschedules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockClient.EXPECT().
+		GetBonuses(gomock.Any(), "12345").
+		Return(nil, accrual.NewClientError(errors.New("internal server error"), http.StatusInternalServerError))
+
+	// третья неудачная попытка подряд: задержка 5s * 2^2
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), 20*time.Second).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob, _ time.Duration) error {
+			assert.Equal(t, 3, job.Attempts)
+			assert.Equal(t, 1, job.Failures)
+			assert.Equal(t, http.StatusInternalServerError, job.LastHTTPStatus)
+			assert.NotEmpty(t, job.LastError)
+			return nil
+		},
+	)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
+	)
+
+	task := accrual.NewTaskFromJob(service, &domain.AccrualJob{OrderID: order.ID, Order: order, Attempts: 2})
+	err := task.Handle()
+	assert.Error(t, err)
+}
+
+func TestTask_Handle_TooManyRequests_KeepsJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockClient.EXPECT().
+		GetBonuses(gomock.Any(), "12345").
+		Return(nil, &accrual.ClientError{HTTPStatus: http.StatusTooManyRequests, RetryAfter: time.Second})
+
+	// задачу вернет в канал воркер, попытка не засчитывается
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
+	)
+
+	task := accrual.NewTask(service, order)
+	err := task.Handle()
+	assert.Error(t, err)
+}
+
+func TestTask_Handle_BadResponse_MovesToDeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	cErr := accrual.NewClientError(errors.New("malformed response"), http.StatusOK)
+	cErr.Body = []byte(`{"order":`)
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "12345").Return(nil, cErr)
+
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockJobRepo.EXPECT().AccrualJobMarkDead(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob) error {
+			assert.Equal(t, 10, job.Failures)
+			assert.Equal(t, `{"order":`, job.LastResponse)
+			return nil
+		},
+	)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
+	)
+
+	task := accrual.NewTaskFromJob(service, &domain.AccrualJob{OrderID: order.ID, Order: order, Attempts: 12, Failures: 9})
+	err := task.Handle()
+	assert.Error(t, err)
+}
+
+// ответ не в UTF-8 и обрезка посреди символа не должны мешать сохранить задачу в текстовый столбец
+func TestTask_Handle_BadResponse_NotUTF8(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	body := append([]byte("\xff\xfe{\"order\":\x00"), []byte(strings.Repeat("я", 3000))...)
+	cErr := accrual.NewClientError(errors.New("malformed response"), http.StatusOK)
+	cErr.Body = body
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "12345").Return(nil, cErr)
+
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob, _ time.Duration) error {
+			assert.Equal(t, 1, job.Failures)
+			assert.True(t, utf8.ValidString(job.LastResponse))
+			assert.NotContains(t, job.LastResponse, "\x00")
+			assert.LessOrEqual(t, len(job.LastResponse), 4096)
+			assert.True(t, strings.HasPrefix(job.LastResponse, "\uFFFD{\"order\":я"), job.LastResponse[:20])
+			return nil
+		},
+	)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
+	)
+
+	task := accrual.NewTaskFromJob(service, &domain.AccrualJob{OrderID: order.ID, Order: order})
+	err := task.Handle()
+	assert.Error(t, err)
+}
+
+func TestTask_Handle_NetworkError_NotCountedAsFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockClient.EXPECT().
+		GetBonuses(gomock.Any(), "12345").
+		Return(nil, accrual.NewClientError(errors.New("connection refused"), 0))
+
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob, _ time.Duration) error {
+			assert.Equal(t, 9, job.Failures)
+			return nil
+		},
+	)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
+	)
+
+	task := accrual.NewTaskFromJob(service, &domain.AccrualJob{OrderID: order.ID, Order: order, Failures: 9})
+	err := task.Handle()
+	assert.Error(t, err)
+}
+
+func TestTask_Handle_CircuitOpen_Postpones(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockClient.EXPECT().
+		GetBonuses(gomock.Any(), "12345").
+		Return(nil, accrual.NewClientError(errors.New("connection refused"), 0))
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	cfg.Accrual.BreakerFailureThreshold = 1
+	cfg.Accrual.BreakerProbeInterval = time.Minute
+
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
+	)
+
+	// первая сетевая ошибка размыкает цепь и обрабатывается как обычная неудача
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), 5*time.Second).Return(nil)
+	err := accrual.NewTask(service, order).Handle()
+	assert.Error(t, err)
+	assert.Equal(t, accrual.CircuitOpen, service.CircuitState())
+	assert.WithinDuration(t, time.Now().Add(time.Minute), service.GetLockedUntil(), time.Second)
+
+	// задача откладывается до пробного запроса, счетчики не меняются
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob, delay time.Duration) error {
+			assert.Equal(t, 2, job.Attempts)
+			assert.InDelta(t, float64(time.Minute), float64(delay), float64(time.Second))
+			return nil
+		},
+	)
+
+	err = accrual.NewTaskFromJob(service, &domain.AccrualJob{OrderID: order.ID, Order: order, Attempts: 2}).Handle()
+	assert.True(t, errors.Is(err, accrual.ErrCircuitOpen))
+}
+
+// текст ошибки отложенной задачи сохраняется так же, как и при неудачной попытке
+func TestTask_Handle_CircuitOpen_NotUTF8Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+
+	cause := fmt.Errorf("partner \xff\x00%s: %w", strings.Repeat("я", 3000), accrual.ErrCircuitOpen)
+	mockClient.EXPECT().GetBonuses(gomock.Any(), "12345").Return(nil, accrual.NewClientError(cause, 0))
+
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, job domain.AccrualJob, _ time.Duration) error {
+			assert.True(t, utf8.ValidString(job.LastError))
+			assert.NotContains(t, job.LastError, "\x00")
+			assert.LessOrEqual(t, len(job.LastError), 4096)
+			assert.True(t, strings.HasPrefix(job.LastError, "partner \uFFFDя"), job.LastError[:20])
+			return nil
+		},
+	)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mock_storage.NewMockIPGXStorage(ctrl),
+		&repository.Repositories{
+			User:       mock_repository.NewMockIUserRepository(ctrl),
+			Order:      mock_repository.NewMockIOrderRepository(ctrl),
+			AccrualJob: mockJobRepo,
+		},
+		nil,
+	)
+
+	err := accrual.NewTask(service, order).Handle()
+	assert.True(t, errors.Is(err, accrual.ErrCircuitOpen))
+}
+
+// запрос к accrual и логи задачи помечаются запросом, создавшим заказ
+func TestTask_Handle_PropagatesRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	order := &domain.Order{ID: 1, Number: "12345", Status: domain.OrderStatusNew}
+
+	mockClient := mock_accrual.NewMockIClient(ctrl)
+	mockOrderRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockJobRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockClient.EXPECT().
+		GetBonuses(gomock.Any(), "12345").
+		DoAndReturn(func(ctx context.Context, _ string) (*accrual.Response, *accrual.ClientError) {
+			assert.Equal(t, "order-request-id", utils.RequestIDFromContext(ctx))
+			return &accrual.Response{OrderNumber: "12345", Status: accrual.StatusRegistered}, nil
+		})
+
+	mockJobRepo.EXPECT().AccrualJobReschedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	cfg, _ := config.NewDefault(&config.Config{})
+	service := accrual.NewService(
+		context.Background(),
+		&cfg.Accrual,
+		mockClient,
+		mockStorage,
+		&repository.Repositories{User: mockUserRepo, Order: mockOrderRepo, AccrualJob: mockJobRepo},
+		nil,
+	)
+
+	task := accrual.NewTaskFromJob(service, &domain.AccrualJob{OrderID: order.ID, Order: order, RequestID: "order-request-id"})
+	assert.NoError(t, task.Handle())
+}
@@ -29,9 +29,19 @@ func (w Worker) Work(ctx context.Context, taskCh chan ITask) {
 			// т.е. продолжит выполнение итерации
 		}
 
-		task := <-taskCh
+		var task ITask
+		select {
+		case <-ctx.Done():
+			logging.LogDebug("accrual worker stopping")
+			return
+		case task = <-taskCh:
+		}
+
 		err := task.Handle()
 
+		// заказ снова может быть поставлен в очередь, в том числе этим воркером
+		w.service.Release(task)
+
 		var cErr *ClientError
 		if errors.As(err, &cErr) {
 			// если получили 429 от клиента
@@ -48,6 +48,7 @@ func TestWorker_Work_TaskSuccess(t *testing.T) {
 	mockService.EXPECT().GetLockedUntil().Return(time.Now()).AnyTimes()
 
 	mockTask.EXPECT().Handle().Return(nil).Times(1)
+	mockService.EXPECT().Release(mockTask)
 
 	taskCh <- mockTask
 
@@ -76,6 +77,7 @@ func TestWorker_Work_ClientError429(t *testing.T) {
 		RetryAfter: 5 * time.Second,
 	}
 	mockTask.EXPECT().Handle().Return(clientError).Times(1)
+	mockService.EXPECT().Release(mockTask)
 
 	mockService.EXPECT().SetLockedUntil(gomock.Any()).Do(func(tm time.Time) {
 		// т.к. точно поймать 5 секунд невозможно, используем indelta
@@ -108,6 +110,7 @@ func TestWorker_Work_NonClientError(t *testing.T) {
 	mockService.EXPECT().GetLockedUntil().Return(time.Now()).AnyTimes()
 
 	mockTask.EXPECT().Handle().Return(errors.New("some error")).Times(1)
+	mockService.EXPECT().Release(mockTask)
 
 	taskCh <- mockTask
 
@@ -119,3 +122,33 @@ func TestWorker_Work_NonClientError(t *testing.T) {
 	go worker.Work(ctx, taskCh)
 	time.Sleep(100 * time.Millisecond)
 }
+
+func TestWorker_Work_StopsWhileWaitingForTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_accrual.NewMockIService(ctrl)
+	taskCh := make(chan accrual.ITask, 1)
+
+	mockService.EXPECT().GetLockedUntil().Return(time.Now()).AnyTimes()
+
+	worker := accrual.NewWorker(mockService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		worker.Work(ctx, taskCh)
+		close(done)
+	}()
+
+	// воркер ждет задачу в пустой очереди и должен выйти по отмене контекста
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after context cancel")
+	}
+}
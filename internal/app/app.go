@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os/signal"
@@ -13,13 +14,21 @@ import (
 	"github.com/ex0rcist/gophermart/internal/config"
 	httpbackend "github.com/ex0rcist/gophermart/internal/http_backend"
 	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/loyalty"
+	"github.com/ex0rcist/gophermart/internal/rules"
 	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/memory"
+	"github.com/ex0rcist/gophermart/internal/storage/migrator"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/storage/sqlite"
 )
 
+var ErrStorageWithoutRepositories = errors.New("storage does not provide a pgx pool to build repositories")
+
 type App struct {
 	ctx         context.Context
 	config      *config.Config
-	storage     storage.IPGXStorage
+	storage     storage.IStorage
 	httpBackend httpbackend.IHTTPBackend
 	accrService accrual.IService
 	cancelFunc  context.CancelFunc
@@ -27,38 +36,114 @@ type App struct {
 
 func New(
 	config *config.Config,
-	pgxStorage storage.IPGXStorage,
+	appStorage storage.IStorage,
 	accrService accrual.IService,
 	httpBackend httpbackend.IHTTPBackend,
 ) (*App, error) {
 	var err error
+	var repos *repository.Repositories
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 
-	if pgxStorage == nil {
-		pgxStorage, err = storage.NewPGXStorage(config.DB, nil, true)
+	if appStorage == nil {
+		appStorage, repos, err = newStorage(config.DB)
 		if err != nil {
-			return nil, fmt.Errorf("NewPGXStorage() failed: %w", err)
+			return nil, err
 		}
 	}
 
+	// для переданного хранилища без репозиториев подходят только репозитории pgx
+	if repos == nil && (accrService == nil || httpBackend == nil) {
+		pgxStorage, ok := appStorage.(storage.IPGXStorage)
+		if !ok {
+			cancel()
+			return nil, ErrStorageWithoutRepositories
+		}
+
+		repos = repository.NewPGXRepositories(pgxStorage.GetPool())
+	}
+
 	if accrService == nil {
-		accrService = accrual.NewService(ctx, &config.Accrual, nil, pgxStorage, nil, nil)
+		program, err := loyalty.LoadProgram(config.Accrual.TiersFile)
+		if err != nil {
+			return nil, fmt.Errorf("LoadProgram() failed: %w", err)
+		}
+
+		ruleSet, err := rules.LoadRuleSet(config.Accrual.RulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("LoadRuleSet() failed: %w", err)
+		}
+
+		service := accrual.NewService(ctx, &config.Accrual, nil, appStorage, repos, program)
+		service.SetRules(ruleSet)
+
+		accrService = service
 	}
 
 	if httpBackend == nil {
-		httpBackend = httpbackend.NewHTTPBackend(ctx, &config.Server, pgxStorage)
+		httpBackend = httpbackend.NewHTTPBackend(ctx, config, appStorage, repos, accrService)
 	}
 
 	return &App{
 		ctx:         ctx,
 		config:      config,
-		storage:     pgxStorage,
+		storage:     appStorage,
 		httpBackend: httpBackend,
 		accrService: accrService,
 		cancelFunc:  cancel,
 	}, nil
 }
 
+// хранилище и его репозитории выбираются по схеме DSN
+func newStorage(dbConfig config.DB) (storage.IStorage, *repository.Repositories, error) {
+	if !dbConfig.AutoMigrate && dbConfig.Driver() != config.DBDriverMemory {
+		checkSchema(dbConfig)
+	}
+
+	switch dbConfig.Driver() {
+	case config.DBDriverMemory:
+		logging.LogWarn("using in-memory storage, data will be lost on shutdown")
+
+		memStorage := memory.NewStorage()
+		return memStorage, memory.NewRepositories(memStorage), nil
+	case config.DBDriverSQLite:
+		sqliteStorage, err := sqlite.NewStorage(dbConfig, dbConfig.AutoMigrate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sqlite.NewStorage() failed: %w", err)
+		}
+
+		return sqliteStorage, sqlite.NewRepositories(sqliteStorage), nil
+	}
+
+	pgxStorage, err := storage.NewPGXStorage(dbConfig, nil, dbConfig.AutoMigrate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewPGXStorage() failed: %w", err)
+	}
+
+	return pgxStorage, repository.NewPGXRepositories(pgxStorage.GetPool()), nil
+}
+
+// без автоматических миграций сервер стартует на текущей схеме, но предупреждает, если она устарела
+func checkSchema(dbConfig config.DB) {
+	m, err := migrator.New(dbConfig)
+	if err != nil {
+		logging.LogError(err, "failed to check database schema")
+		return
+	}
+	defer m.Close()
+
+	status, err := m.Status()
+	if err != nil {
+		logging.LogError(err, "failed to check database schema")
+		return
+	}
+
+	if status.Dirty {
+		logging.LogWarnF("database schema version %d is dirty, fix it and run `gophermart migrate force`", status.Version)
+	} else if status.Pending() {
+		logging.LogWarnF("database schema version %d is behind %d, run `gophermart migrate up`", status.Version, status.Latest)
+	}
+}
+
 func (a *App) Run() error {
 	logging.LogInfo(a.String())
 	logging.LogInfo("app ready")
@@ -73,7 +158,6 @@ func (a *App) Run() error {
 	}()
 
 	// стартуем интеграцию с accrual
-	// NB: остановка a.accrService не требуется, т.к. он слушает a.ctx
 	go func() {
 		err := a.accrService.Run()
 		if err != nil {
@@ -96,6 +180,14 @@ func (a *App) Run() error {
 	<-ctx.Done()
 	logging.LogInfo("server stopped")
 
+	// дожидаемся выполняемых задач accrual, пока соединения с БД открыты
+	logging.LogInfo("stopping accrual service... ")
+	accrCtx, accrCancel := context.WithTimeout(context.Background(), a.config.Accrual.ShutdownTimeout)
+	defer accrCancel()
+	if err := a.accrService.Shutdown(accrCtx); err != nil {
+		logging.LogError(err, "err stopping accrual service")
+	}
+
 	// закрываем коннекты к БД
 	a.storage.Close()
 	logging.LogInfo("storage closed")
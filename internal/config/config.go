@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"runtime"
 	"strings"
 	"time"
 
@@ -16,26 +17,131 @@ import (
 )
 
 type DB struct {
-	DSN              string `env:"DATABASE_URI"`
-	MigrationsSource string
+	DSN string `env:"DATABASE_URI"`
+
+	// применять новые миграции при старте сервера; при отключении схема обновляется командой migrate
+	AutoMigrate bool `env:"DATABASE_AUTO_MIGRATE"`
+}
+
+const (
+	DBDriverPostgres = "postgres"
+	DBDriverMemory   = "memory"
+	DBDriverSQLite   = "sqlite"
+)
+
+// хранилище выбирается по схеме DSN: memory:// - данные в памяти процесса, sqlite://путь - файл SQLite,
+// без схемы или postgres:// - PostgreSQL; пустая строка для неизвестной схемы
+func (db DB) Driver() string {
+	scheme, _, found := strings.Cut(db.DSN, "://")
+	if !found {
+		return DBDriverPostgres
+	}
+
+	switch scheme {
+	case "postgres", "postgresql":
+		return DBDriverPostgres
+	case "memory":
+		return DBDriverMemory
+	case "sqlite":
+		return DBDriverSQLite
+	default:
+		return ""
+	}
 }
 
 type Server struct {
-	Address string `env:"RUN_ADDRESS"`
-	Timeout time.Duration
-	Secret  entities.Secret `env:"APP_KEY"`
+	Address    string `env:"RUN_ADDRESS"`
+	Timeout    time.Duration
+	Secret     entities.Secret `env:"APP_KEY"`
+	AdminToken entities.Secret `env:"ADMIN_TOKEN"`
 }
 
 type Accrual struct {
 	Address        string `env:"ACCRUAL_SYSTEM_ADDRESS"`
 	Timeout        time.Duration
 	RefillInterval time.Duration
+	TiersFile      string `env:"LOYALTY_TIERS_FILE"`
+
+	// идентификатор экземпляра для аренды заказов, по умолчанию генерируется из имени хоста
+	InstanceID    string        `env:"ACCRUAL_INSTANCE_ID"`
+	LeaseDuration time.Duration `env:"ACCRUAL_LEASE_DURATION"`
+	BatchSize     int           `env:"ACCRUAL_BATCH_SIZE"`
+
+	// экспоненциальная задержка повторного опроса заказа
+	RetryBaseDelay time.Duration `env:"ACCRUAL_RETRY_BASE_DELAY"`
+	RetryMaxDelay  time.Duration `env:"ACCRUAL_RETRY_MAX_DELAY"`
+
+	// после стольких некорректных ответов подряд задача уходит в dead-letter, 0 - никогда
+	MaxFailedAttempts int `env:"ACCRUAL_MAX_FAILED_ATTEMPTS"`
+
+	// circuit breaker: размыкается после стольких ошибок доступности подряд, 0 - отключен
+	BreakerFailureThreshold int           `env:"ACCRUAL_BREAKER_FAILURE_THRESHOLD"`
+	BreakerProbeInterval    time.Duration `env:"ACCRUAL_BREAKER_PROBE_INTERVAL"`
+
+	// максимум запросов в секунду ко всей системе начислений, 0 - без ограничений
+	RateLimit float64 `env:"ACCRUAL_RATE_LIMIT"`
+
+	// число воркеров опроса и емкость очереди задач между заправками
+	Workers       int `env:"ACCRUAL_WORKERS"`
+	QueueCapacity int `env:"ACCRUAL_QUEUE_CAPACITY"`
+
+	// немедленный опрос новых заказов по LISTEN/NOTIFY, без ожидания заправки
+	Listen bool `env:"ACCRUAL_LISTEN"`
+
+	// сколько при остановке ждать завершения выполняемых задач
+	ShutdownTimeout time.Duration `env:"ACCRUAL_SHUTDOWN_TIMEOUT"`
+
+	// сколько хранятся сохраненные обращения к системам начислений, 0 - бессрочно
+	CallsRetention time.Duration `env:"ACCRUAL_CALLS_RETENTION"`
+
+	// ключ HMAC-подписи результатов, присылаемых системой начислений; пустой - прием отключен
+	CallbackSecret entities.Secret `env:"ACCRUAL_CALLBACK_SECRET"`
+
+	// подозрительные начисления уходят на ручную проверку: отрицательные, больше максимума
+	// на заказ или больше доли от стоимости покупки, если она известна; 0 - без ограничения
+	ReviewMaxAmount        float64 `env:"ACCRUAL_REVIEW_MAX_AMOUNT"`
+	ReviewMaxPurchaseRatio float64 `env:"ACCRUAL_REVIEW_MAX_PURCHASE_RATIO"`
+
+	// обработанные заказы опрашиваются с интервалом CorrectionInterval в течение CorrectionWindow,
+	// исправления начисления проводятся корректировками; 0 - исправления не учитываются.
+	// CorrectionNegativeBalance: clamp - списание ограничивается балансом, allow - баланс может уйти в минус
+	CorrectionWindow          time.Duration `env:"ACCRUAL_CORRECTION_WINDOW"`
+	CorrectionInterval        time.Duration `env:"ACCRUAL_CORRECTION_INTERVAL"`
+	CorrectionNegativeBalance string        `env:"ACCRUAL_CORRECTION_NEGATIVE_BALANCE"`
+
+	// способ расчета начислений по умолчанию: external - внешняя система, internal - встроенные правила
+	Engine    string `env:"ACCRUAL_ENGINE"`
+	RulesFile string `env:"ACCRUAL_RULES_FILE"`
+
+	// дополнительные системы начислений, загружаются из yaml-файла
+	ProvidersFile string `env:"ACCRUAL_PROVIDERS_FILE"`
+	Providers     []AccrualProvider
+}
+
+// лимиты переводов между пользователями, 0 - без ограничений
+type Transfer struct {
+	DailyAmountLimit float64 `env:"TRANSFER_DAILY_AMOUNT_LIMIT"`
+	DailyCountLimit  int     `env:"TRANSFER_DAILY_COUNT_LIMIT"`
+}
+
+// правила списания баллов, 0 - без ограничений
+type Withdrawal struct {
+	MinAmount              float64       `env:"WITHDRAWAL_MIN_AMOUNT"`
+	MaxAmount              float64       `env:"WITHDRAWAL_MAX_AMOUNT"`
+	DailyLimit             float64       `env:"WITHDRAWAL_DAILY_LIMIT"`
+	MonthlyLimit           float64       `env:"WITHDRAWAL_MONTHLY_LIMIT"`
+	PasswordChangeCooldown time.Duration `env:"WITHDRAWAL_PASSWORD_CHANGE_COOLDOWN"`
 }
 
 type Config struct {
-	DB      DB
-	Server  Server
-	Accrual Accrual
+	DB         DB
+	Server     Server
+	Accrual    Accrual
+	Transfer   Transfer
+	Withdrawal Withdrawal
+
+	// позиционные аргументы командной строки: подкоманда и ее параметры
+	Args []string
 }
 
 func Parse() (*Config, error) {
@@ -43,9 +149,9 @@ func Parse() (*Config, error) {
 
 	cfg := &Config{}
 
-	// порядок парсинга настроек: дефолтные; ENV; flags
+	// порядок парсинга настроек: дефолтные; ENV; flags; файлы, пути к которым заданы выше
 	fns := []func(*Config) (*Config, error){
-		NewDefault, ConfigFromEnv, ConfigFromFlags,
+		NewDefault, ConfigFromEnv, ConfigFromFlags, ProvidersFromFile,
 	}
 
 	for _, fn := range fns {
@@ -65,7 +171,7 @@ func Parse() (*Config, error) {
 func NewDefault(_ *Config) (*Config, error) {
 	config := &Config{
 		DB: DB{
-			MigrationsSource: "file://internal/storage/migrations",
+			AutoMigrate: true,
 		},
 		Server: Server{
 			Address: "0.0.0.0:8080",
@@ -75,6 +181,26 @@ func NewDefault(_ *Config) (*Config, error) {
 			Address:        "0.0.0.0:8181",
 			RefillInterval: 5 * time.Second,
 			Timeout:        5 * time.Second,
+			LeaseDuration:  time.Minute,
+			BatchSize:      100,
+			RetryBaseDelay: 5 * time.Second,
+			RetryMaxDelay:  10 * time.Minute,
+
+			MaxFailedAttempts: 10,
+
+			BreakerFailureThreshold: 5,
+			BreakerProbeInterval:    30 * time.Second,
+
+			Workers:       runtime.NumCPU(),
+			QueueCapacity: 100,
+			Listen:        true,
+
+			ShutdownTimeout: 10 * time.Second,
+			CallsRetention:  30 * 24 * time.Hour,
+			Engine:          "external",
+
+			CorrectionInterval:        6 * time.Hour,
+			CorrectionNegativeBalance: "clamp",
 		},
 	}
 
@@ -84,16 +210,51 @@ func NewDefault(_ *Config) (*Config, error) {
 func ConfigFromFlags(config *Config) (*Config, error) {
 	flags := pflag.NewFlagSet(os.Args[0], pflag.ContinueOnError)
 
-	flags.StringVarP(&config.DB.DSN, "database", "d", config.DB.DSN, "PostgreSQL database DSN")
+	flags.StringVarP(&config.DB.DSN, "database", "d", config.DB.DSN, "database DSN: PostgreSQL, sqlite://path for SQLite or memory:// for in-memory storage")
+	flags.BoolVar(&config.DB.AutoMigrate, "auto-migrate", config.DB.AutoMigrate, "apply new database migrations on server start")
 	flags.StringVarP(&config.Accrual.Address, "accrual-address", "r", config.Accrual.Address, "address:port for accrual service")
+	flags.StringVar(&config.Accrual.InstanceID, "accrual-instance-id", config.Accrual.InstanceID, "instance id used to lease orders for accrual polling; generated from hostname if empty")
+	flags.DurationVar(&config.Accrual.LeaseDuration, "accrual-lease-duration", config.Accrual.LeaseDuration, "how long a claimed order is reserved for this instance")
+	flags.IntVar(&config.Accrual.BatchSize, "accrual-batch-size", config.Accrual.BatchSize, "max orders claimed for accrual polling per refill")
+	flags.DurationVar(&config.Accrual.RetryBaseDelay, "accrual-retry-base-delay", config.Accrual.RetryBaseDelay, "initial delay between accrual lookups of the same order")
+	flags.DurationVar(&config.Accrual.RetryMaxDelay, "accrual-retry-max-delay", config.Accrual.RetryMaxDelay, "max delay between accrual lookups of the same order")
+	flags.IntVar(&config.Accrual.MaxFailedAttempts, "accrual-max-failed-attempts", config.Accrual.MaxFailedAttempts, "failed accrual lookups in a row before an order is moved to dead-letter; 0 means never")
+	flags.IntVar(&config.Accrual.BreakerFailureThreshold, "accrual-breaker-failure-threshold", config.Accrual.BreakerFailureThreshold, "accrual unavailability errors in a row before requests are paused; 0 disables circuit breaker")
+	flags.DurationVar(&config.Accrual.BreakerProbeInterval, "accrual-breaker-probe-interval", config.Accrual.BreakerProbeInterval, "how long requests are paused before a probe request to accrual")
+	flags.Float64Var(&config.Accrual.RateLimit, "accrual-rate-limit", config.Accrual.RateLimit, "max requests per second to accrual shared by all workers; 0 means unlimited")
+	flags.IntVar(&config.Accrual.Workers, "accrual-workers", config.Accrual.Workers, "number of accrual polling workers")
+	flags.IntVar(&config.Accrual.QueueCapacity, "accrual-queue-capacity", config.Accrual.QueueCapacity, "max accrual tasks waiting for a worker")
+	flags.BoolVar(&config.Accrual.Listen, "accrual-listen", config.Accrual.Listen, "queue new orders for accrual lookup immediately via LISTEN/NOTIFY")
+	flags.DurationVar(&config.Accrual.ShutdownTimeout, "accrual-shutdown-timeout", config.Accrual.ShutdownTimeout, "how long to wait for running accrual tasks on shutdown")
+	flags.DurationVar(&config.Accrual.CallsRetention, "accrual-calls-retention", config.Accrual.CallsRetention, "how long requests to accrual and their responses are kept; 0 means forever")
+	flags.Var(&config.Accrual.CallbackSecret, "accrual-callback-secret", "a key to verify HMAC signature of accrual callbacks; callbacks are disabled if empty")
+	flags.Float64Var(&config.Accrual.ReviewMaxAmount, "accrual-review-max-amount", config.Accrual.ReviewMaxAmount, "accruals above this amount per order are held for manual review; 0 means unlimited")
+	flags.Float64Var(&config.Accrual.ReviewMaxPurchaseRatio, "accrual-review-max-purchase-ratio", config.Accrual.ReviewMaxPurchaseRatio, "accruals above this share of purchase amount are held for manual review; 0 means unlimited")
+	flags.DurationVar(&config.Accrual.CorrectionWindow, "accrual-correction-window", config.Accrual.CorrectionWindow, "how long processed orders are polled for accrual corrections; 0 disables corrections")
+	flags.DurationVar(&config.Accrual.CorrectionInterval, "accrual-correction-interval", config.Accrual.CorrectionInterval, "delay between accrual lookups of a processed order within correction window")
+	flags.StringVar(&config.Accrual.CorrectionNegativeBalance, "accrual-correction-negative-balance", config.Accrual.CorrectionNegativeBalance, "how a correction that exceeds user balance is applied: clamp or allow")
+	flags.StringVar(&config.Accrual.Engine, "accrual-engine", config.Accrual.Engine, "default accrual engine for orders: external or internal")
+	flags.StringVar(&config.Accrual.RulesFile, "accrual-rules-file", config.Accrual.RulesFile, "path to YAML file with rules of the internal accrual engine")
+	flags.StringVar(&config.Accrual.ProvidersFile, "accrual-providers-file", config.Accrual.ProvidersFile, "path to YAML file with additional accrual providers routed by order number prefix")
+	flags.StringVar(&config.Accrual.TiersFile, "tiers-file", config.Accrual.TiersFile, "path to loyalty tiers YAML file; tiers are disabled if empty")
 	flags.StringVarP(&config.Server.Address, "gophermart-address", "a", config.Server.Address, "address:port for HTTP API requests")
 	flags.VarP(&config.Server.Secret, "secret", "k", "a key to sign data; will be generated automatically if empty")
+	flags.Float64Var(&config.Transfer.DailyAmountLimit, "transfer-daily-amount-limit", config.Transfer.DailyAmountLimit, "max points a user can transfer per day; 0 means unlimited")
+	flags.IntVar(&config.Transfer.DailyCountLimit, "transfer-daily-count-limit", config.Transfer.DailyCountLimit, "max transfers a user can make per day; 0 means unlimited")
+	flags.Float64Var(&config.Withdrawal.MinAmount, "withdrawal-min-amount", config.Withdrawal.MinAmount, "min points per withdrawal; 0 means unlimited")
+	flags.Float64Var(&config.Withdrawal.MaxAmount, "withdrawal-max-amount", config.Withdrawal.MaxAmount, "max points per withdrawal; 0 means unlimited")
+	flags.Float64Var(&config.Withdrawal.DailyLimit, "withdrawal-daily-limit", config.Withdrawal.DailyLimit, "max points a user can withdraw per day; 0 means unlimited")
+	flags.Float64Var(&config.Withdrawal.MonthlyLimit, "withdrawal-monthly-limit", config.Withdrawal.MonthlyLimit, "max points a user can withdraw per month; 0 means unlimited")
+	flags.DurationVar(&config.Withdrawal.PasswordChangeCooldown, "withdrawal-password-change-cooldown", config.Withdrawal.PasswordChangeCooldown, "withdrawals are disabled for this period after password change; 0 means disabled")
+	flags.Var(&config.Server.AdminToken, "admin-token", "a token for admin API requests; admin API is disabled if empty")
 
 	err := flags.Parse(os.Args[1:])
 	if err != nil {
 		return nil, err
 	}
 
+	config.Args = flags.Args()
+
 	if len(config.Server.Secret) <= 0 {
 		config.Server.Secret = entities.Secret(utils.GenerateRandomString(16))
 	}
@@ -113,10 +274,43 @@ func validateConfig(c *Config) error {
 	g := &errgroup.Group{}
 	g.Go(func() error { return validateAddr(c.Server.Address) })
 	g.Go(func() error { return validateAddr(c.Accrual.Address) })
-	g.Go(func() error { return validateDSN(c.DB.DSN) })
+	g.Go(func() error { return validateDSN(c.DB) })
+	g.Go(func() error { return validateEngine(c.Accrual.Engine, c.Accrual.RulesFile) })
+	g.Go(func() error {
+		return validateCorrection(c.Accrual.CorrectionWindow, c.Accrual.CorrectionInterval, c.Accrual.CorrectionNegativeBalance)
+	})
 	return g.Wait()
 }
 
+// без явной политики списание исправлений не уводит баланс в минус
+func validateCorrection(window, interval time.Duration, negativeBalance string) error {
+	if window > 0 && interval <= 0 {
+		return fmt.Errorf("accrual correction interval must be positive")
+	}
+
+	switch negativeBalance {
+	case "", "clamp", "allow":
+		return nil
+	default:
+		return fmt.Errorf("unknown accrual correction negative balance policy %q", negativeBalance)
+	}
+}
+
+// встроенный расчет по умолчанию без правил начислял бы ноль по всем заказам
+func validateEngine(engine, rulesFile string) error {
+	switch engine {
+	case "", "external":
+		return nil
+	case "internal":
+		if rulesFile == "" {
+			return fmt.Errorf("internal accrual engine requires rules file")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown accrual engine %q", engine)
+	}
+}
+
 func validateAddr(address string) error {
 	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
 		address = "http://" + address
@@ -156,10 +350,14 @@ func validateAddr(address string) error {
 	return nil
 }
 
-func validateDSN(dsn string) error {
-	if dsn == "" {
+func validateDSN(db DB) error {
+	if db.DSN == "" {
 		return fmt.Errorf("empty DB DSN")
 	}
 
+	if db.Driver() == "" {
+		return fmt.Errorf("unsupported DB DSN scheme")
+	}
+
 	return nil
 }
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// дополнительная система начислений; заказы направляются к ней по магазину или префиксу номера
+type AccrualProvider struct {
+	Name      string         `yaml:"name"`
+	Address   string         `yaml:"address"`
+	Path      string         `yaml:"path"` // шаблон пути запроса с {number}, по умолчанию /api/orders/{number}
+	Timeout   time.Duration  `yaml:"timeout"`
+	RateLimit float64        `yaml:"rate_limit"` // запросов в секунду, 0 - без ограничений
+	Prefixes  []string       `yaml:"prefixes"`
+	Stores    []string       `yaml:"stores"`  // магазины из данных о покупке; имеют приоритет перед префиксами
+	Default   bool           `yaml:"default"` // заказы без подходящего магазина и префикса идут к этому провайдеру
+	Mapping   AccrualMapping `yaml:"mapping"`
+}
+
+// соответствие полей ответа провайдера полям accrual.Response; пустые поля - как в основном API
+type AccrualMapping struct {
+	Order    string            `yaml:"order"`
+	Status   string            `yaml:"status"`
+	Accrual  string            `yaml:"accrual"`
+	Statuses map[string]string `yaml:"statuses"` // статус провайдера -> REGISTERED, INVALID, PROCESSING, PROCESSED
+}
+
+type accrualProvidersFile struct {
+	Providers []AccrualProvider `yaml:"providers"`
+}
+
+var accrualStatuses = map[string]bool{"REGISTERED": true, "INVALID": true, "PROCESSING": true, "PROCESSED": true}
+
+// загружает дополнительные системы начислений из yaml-файла ACCRUAL_PROVIDERS_FILE
+func ProvidersFromFile(config *Config) (*Config, error) {
+	if config.Accrual.ProvidersFile == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(config.Accrual.ProvidersFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", config.Accrual.ProvidersFile, err)
+	}
+
+	providers, err := ParseProviders(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Accrual.Providers = providers
+
+	return config, nil
+}
+
+func ParseProviders(data []byte) ([]AccrualProvider, error) {
+	file := accrualProvidersFile{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing accrual providers: %w", err)
+	}
+
+	if err := validateProviders(file.Providers); err != nil {
+		return nil, err
+	}
+
+	return file.Providers, nil
+}
+
+func validateProviders(providers []AccrualProvider) error {
+	names := make(map[string]bool, len(providers))
+	prefixes := make(map[string]string)
+	stores := make(map[string]string)
+	defaults := 0
+
+	for _, p := range providers {
+		if p.Name == "" {
+			return fmt.Errorf("accrual provider name is empty")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate accrual provider %s", p.Name)
+		}
+		names[p.Name] = true
+
+		if err := validateProviderURL(p.Address); err != nil {
+			return fmt.Errorf("accrual provider %s: %w", p.Name, err)
+		}
+		if p.Path != "" && !strings.Contains(p.Path, "{number}") {
+			return fmt.Errorf("accrual provider %s: path must contain {number}", p.Name)
+		}
+		if p.Timeout < 0 || p.RateLimit < 0 {
+			return fmt.Errorf("accrual provider %s: timeout and rate limit must not be negative", p.Name)
+		}
+
+		for _, prefix := range p.Prefixes {
+			if prefix == "" {
+				return fmt.Errorf("accrual provider %s: empty prefix", p.Name)
+			}
+			if other, ok := prefixes[prefix]; ok {
+				return fmt.Errorf("accrual provider %s: prefix %s is already routed to %s", p.Name, prefix, other)
+			}
+			prefixes[prefix] = p.Name
+		}
+
+		for _, store := range p.Stores {
+			if store == "" {
+				return fmt.Errorf("accrual provider %s: empty store", p.Name)
+			}
+			if other, ok := stores[store]; ok {
+				return fmt.Errorf("accrual provider %s: store %s is already routed to %s", p.Name, store, other)
+			}
+			stores[store] = p.Name
+		}
+
+		if p.Default {
+			defaults++
+		}
+		if len(p.Prefixes) == 0 && len(p.Stores) == 0 && !p.Default {
+			return fmt.Errorf("accrual provider %s: no prefixes or stores and not default", p.Name)
+		}
+
+		for from, to := range p.Mapping.Statuses {
+			if !accrualStatuses[to] {
+				return fmt.Errorf("accrual provider %s: status %s is mapped to unknown status %s", p.Name, from, to)
+			}
+		}
+	}
+
+	if defaults > 1 {
+		return fmt.Errorf("only one accrual provider can be default")
+	}
+
+	return nil
+}
+
+// адрес провайдера - URL со схемой http(s) и хостом; порт необязателен, а имя хоста не разрешается,
+// чтобы недоступный при запуске DNS партнера не мешал запуску сервиса
+func validateProviderURL(address string) error {
+	parsedURL, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("error parsing URL: %w", err)
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("error parsing URL: scheme must be http or https")
+	}
+
+	if parsedURL.Hostname() == "" {
+		return fmt.Errorf("error parsing URL: host is missing")
+	}
+
+	return nil
+}
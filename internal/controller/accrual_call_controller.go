@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type AccrualCallController struct {
+	AccrualCallListUsecase usecase.IAccrualCallListUsecase
+}
+
+func (ctrl *AccrualCallController) CallList(c *gin.Context) {
+	const errorPrefix = "AccrualCallController -> CallList()"
+	ctx := c.Request.Context()
+
+	calls, err := ctrl.AccrualCallListUsecase.Call(ctx, c.Param("number"))
+	if err != nil {
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	if len(calls) == 0 {
+		c.Status(http.StatusNoContent)
+	} else {
+		c.JSON(http.StatusOK, calls)
+	}
+}
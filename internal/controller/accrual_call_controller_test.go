@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	mock_usecase "github.com/ex0rcist/gophermart/internal/usecase/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccrualCallController_CallList(t *testing.T) {
+	tests := []struct {
+		name     string
+		calls    []*usecase.AccrualCallResult
+		err      error
+		expected int
+	}{
+		{"success", []*usecase.AccrualCallResult{{Provider: "default", HTTPStatus: 200, Response: `{"status":"PROCESSED"}`}}, nil, http.StatusOK},
+		{"empty", []*usecase.AccrualCallResult{}, nil, http.StatusNoContent},
+		{"internal error", nil, errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockListUsecase := mock_usecase.NewMockIAccrualCallListUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			callController := &AccrualCallController{AccrualCallListUsecase: mockListUsecase}
+
+			r.GET("/calls/:number", callController.CallList)
+
+			mockListUsecase.EXPECT().Call(gomock.Any(), "12345678903").Return(tt.calls, tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/calls/12345678903", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"http_status":200`)
+			}
+		})
+	}
+}
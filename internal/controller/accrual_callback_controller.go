@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	"github.com/gin-gonic/gin"
+)
+
+// заказ из пачки не найден; остальные заказы пачки применяются
+const callbackResultUnknown accrual.ApplyResult = "unknown"
+
+type AccrualCallbackController struct {
+	Accrual accrual.IService
+}
+
+type AccrualCallbackResult struct {
+	Order  string              `json:"order"`
+	Result accrual.ApplyResult `json:"result"`
+}
+
+// принимает результат расчета по одному заказу или пачку результатов
+func (ctrl *AccrualCallbackController) Callback(c *gin.Context) {
+	const errorPrefix = "AccrualCallbackController -> Callback()"
+	ctx := c.Request.Context()
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+
+	var responses []accrual.Response
+	if batch {
+		err = json.Unmarshal(body, &responses)
+	} else {
+		responses = make([]accrual.Response, 1)
+		err = json.Unmarshal(body, &responses[0])
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// пачка проверяется целиком до применения
+	for _, res := range responses {
+		if res.OrderNumber == "" || !res.Status.IsValid() {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": accrual.ErrInvalidAccrualStatus.Error(), "order": res.OrderNumber})
+			return
+		}
+	}
+
+	results := make([]AccrualCallbackResult, 0, len(responses))
+	for _, res := range responses {
+		result, err := ctrl.Accrual.Apply(ctx, res)
+		switch {
+		case err == accrual.ErrUnknownOrder && !batch:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case err == accrual.ErrUnknownOrder:
+			result = callbackResultUnknown
+		case err != nil:
+			// примененные заказы пачки при повторной доставке будут пропущены
+			handleInternalError(c, ctx, err, errorPrefix)
+			return
+		}
+
+		results = append(results, AccrualCallbackResult{Order: res.OrderNumber, Result: result})
+	}
+
+	if batch {
+		c.JSON(http.StatusOK, results)
+	} else {
+		c.JSON(http.StatusOK, results[0])
+	}
+}
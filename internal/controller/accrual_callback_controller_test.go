@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccrualCallbackController_Callback(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		results  []accrual.ApplyResult
+		errs     []error
+		expected int
+		response string
+	}{
+		{
+			name:     "single applied",
+			body:     `{"order":"12345678903","status":"PROCESSED","accrual":500}`,
+			results:  []accrual.ApplyResult{accrual.ApplyResultApplied},
+			errs:     []error{nil},
+			expected: http.StatusOK,
+			response: `{"order":"12345678903","result":"applied"}`,
+		},
+		{
+			name:     "single repeated",
+			body:     `{"order":"12345678903","status":"PROCESSED","accrual":500}`,
+			results:  []accrual.ApplyResult{accrual.ApplyResultIgnored},
+			errs:     []error{nil},
+			expected: http.StatusOK,
+			response: `{"order":"12345678903","result":"ignored"}`,
+		},
+		{
+			name:     "single unknown order",
+			body:     `{"order":"12345678903","status":"INVALID"}`,
+			results:  []accrual.ApplyResult{""},
+			errs:     []error{accrual.ErrUnknownOrder},
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "batch",
+			body:     ` [{"order":"12345678903","status":"PROCESSED","accrual":500},{"order":"9278923470","status":"INVALID"}]`,
+			results:  []accrual.ApplyResult{accrual.ApplyResultApplied, ""},
+			errs:     []error{nil, accrual.ErrUnknownOrder},
+			expected: http.StatusOK,
+			response: `[{"order":"12345678903","result":"applied"},{"order":"9278923470","result":"unknown"}]`,
+		},
+		{
+			name:     "batch with invalid status",
+			body:     `[{"order":"12345678903","status":"PROCESSED","accrual":500},{"order":"9278923470","status":"DONE"}]`,
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "malformed body",
+			body:     `{"order":`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "internal error",
+			body:     `{"order":"12345678903","status":"PROCESSING"}`,
+			results:  []accrual.ApplyResult{""},
+			errs:     []error{errors.New("database error")},
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mock_accrual.NewMockIService(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			callbackController := &AccrualCallbackController{Accrual: mockService}
+
+			r.POST("/callback", callbackController.Callback)
+
+			for i := range tt.results {
+				mockService.EXPECT().Apply(gomock.Any(), gomock.Any()).Return(tt.results[i], tt.errs[i])
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.response != "" {
+				assert.JSONEq(t, tt.response, w.Body.String())
+			}
+		})
+	}
+}
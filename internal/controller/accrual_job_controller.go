@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type AccrualJobController struct {
+	AccrualJobListUsecase    usecase.IAccrualJobListUsecase
+	AccrualJobFindUsecase    usecase.IAccrualJobFindUsecase
+	AccrualJobRequeueUsecase usecase.IAccrualJobRequeueUsecase
+}
+
+func (ctrl *AccrualJobController) DeadJobList(c *gin.Context) {
+	const errorPrefix = "AccrualJobController -> DeadJobList()"
+	ctx := c.Request.Context()
+
+	jobs, err := ctrl.AccrualJobListUsecase.Call(ctx)
+	if err != nil {
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	if len(jobs) == 0 {
+		c.Status(http.StatusNoContent)
+	} else {
+		c.JSON(http.StatusOK, jobs)
+	}
+}
+
+func (ctrl *AccrualJobController) GetJob(c *gin.Context) {
+	const errorPrefix = "AccrualJobController -> GetJob()"
+	ctx := c.Request.Context()
+
+	job, err := ctrl.AccrualJobFindUsecase.Call(ctx, c.Param("number"))
+	switch {
+	case err == usecase.ErrAccrualJobNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (ctrl *AccrualJobController) RequeueJob(c *gin.Context) {
+	const errorPrefix = "AccrualJobController -> RequeueJob()"
+	ctx := c.Request.Context()
+
+	job, err := ctrl.AccrualJobRequeueUsecase.Call(ctx, c.Param("number"))
+	switch {
+	case err == usecase.ErrAccrualJobNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err == usecase.ErrAccrualJobNotDead:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
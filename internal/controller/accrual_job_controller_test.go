@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	mock_usecase "github.com/ex0rcist/gophermart/internal/usecase/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupAccrualJobRouter(ctrl *gomock.Controller) (*gin.Engine, *AccrualJobController) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	jobController := &AccrualJobController{
+		AccrualJobListUsecase:    mock_usecase.NewMockIAccrualJobListUsecase(ctrl),
+		AccrualJobFindUsecase:    mock_usecase.NewMockIAccrualJobFindUsecase(ctrl),
+		AccrualJobRequeueUsecase: mock_usecase.NewMockIAccrualJobRequeueUsecase(ctrl),
+	}
+
+	r.GET("/jobs/dead", jobController.DeadJobList)
+	r.GET("/jobs/:number", jobController.GetJob)
+	r.POST("/jobs/:number/requeue", jobController.RequeueJob)
+
+	return r, jobController
+}
+
+func TestAccrualJobController_DeadJobList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r, jobController := setupAccrualJobRouter(ctrl)
+
+	jobs := []*usecase.AccrualJobResult{
+		{OrderNumber: "12345678903", OrderStatus: domain.OrderStatusNew, Failures: 10, LastHTTPStatus: 500},
+	}
+	jobController.AccrualJobListUsecase.(*mock_usecase.MockIAccrualJobListUsecase).EXPECT().Call(gomock.Any()).Return(jobs, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/dead", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"order":"12345678903"`)
+	assert.Contains(t, w.Body.String(), `"last_http_status":500`)
+}
+
+func TestAccrualJobController_DeadJobList_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r, jobController := setupAccrualJobRouter(ctrl)
+
+	jobController.AccrualJobListUsecase.(*mock_usecase.MockIAccrualJobListUsecase).EXPECT().Call(gomock.Any()).Return([]*usecase.AccrualJobResult{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/dead", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAccrualJobController_GetJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found", usecase.ErrAccrualJobNotFound, http.StatusNotFound},
+		{"internal error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r, jobController := setupAccrualJobRouter(ctrl)
+
+			var job *usecase.AccrualJobResult
+			if tt.err == nil {
+				job = &usecase.AccrualJobResult{OrderNumber: "12345678903"}
+			}
+			jobController.AccrualJobFindUsecase.(*mock_usecase.MockIAccrualJobFindUsecase).EXPECT().Call(gomock.Any(), "12345678903").Return(job, tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/12345678903", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestAccrualJobController_RequeueJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found", usecase.ErrAccrualJobNotFound, http.StatusNotFound},
+		{"not dead", usecase.ErrAccrualJobNotDead, http.StatusConflict},
+		{"internal error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			r, jobController := setupAccrualJobRouter(ctrl)
+
+			var job *usecase.AccrualJobResult
+			if tt.err == nil {
+				job = &usecase.AccrualJobResult{OrderNumber: "12345678903"}
+			}
+			jobController.AccrualJobRequeueUsecase.(*mock_usecase.MockIAccrualJobRequeueUsecase).EXPECT().Call(gomock.Any(), "12345678903").Return(job, tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/jobs/12345678903/requeue", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
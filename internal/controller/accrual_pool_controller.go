@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	"github.com/gin-gonic/gin"
+)
+
+type AccrualPoolController struct {
+	Pool accrual.IService
+}
+
+type AccrualPoolResizeRequest struct {
+	Workers int `json:"workers" binding:"required"`
+}
+
+func (ctrl *AccrualPoolController) GetPool(c *gin.Context) {
+	c.JSON(http.StatusOK, ctrl.Pool.PoolStats())
+}
+
+func (ctrl *AccrualPoolController) ResizePool(c *gin.Context) {
+	const errorPrefix = "AccrualPoolController -> ResizePool()"
+	ctx := c.Request.Context()
+
+	var form = AccrualPoolResizeRequest{}
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := ctrl.Pool.Resize(form.Workers)
+	switch {
+	case err == accrual.ErrInvalidWorkerCount:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, ctrl.Pool.PoolStats())
+}
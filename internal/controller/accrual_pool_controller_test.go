@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccrualPoolController_GetPool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_accrual.NewMockIService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	poolController := &AccrualPoolController{Pool: mockService}
+
+	r.GET("/pool", poolController.GetPool)
+
+	mockService.EXPECT().PoolStats().Return(accrual.PoolStats{Workers: 4, QueueLength: 2, QueueCapacity: 100, InFlight: 5})
+
+	req := httptest.NewRequest(http.MethodGet, "/pool", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"workers":4,"queue_length":2,"queue_capacity":100,"in_flight":5}`, w.Body.String())
+}
+
+func TestAccrualPoolController_ResizePool(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		resize   bool
+		expected int
+	}{
+		{"success", `{"workers":8}`, nil, true, http.StatusOK},
+		{"invalid count", `{"workers":-1}`, accrual.ErrInvalidWorkerCount, true, http.StatusUnprocessableEntity},
+		{"missing count", `{}`, nil, false, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mock_accrual.NewMockIService(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			poolController := &AccrualPoolController{Pool: mockService}
+
+			r.PUT("/pool", poolController.ResizePool)
+
+			if tt.resize {
+				mockService.EXPECT().Resize(gomock.Any()).Return(tt.err)
+			}
+			if tt.expected == http.StatusOK {
+				mockService.EXPECT().PoolStats().Return(accrual.PoolStats{Workers: 8})
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/pool", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type AccrualReviewController struct {
+	OrderReviewListUsecase usecase.IOrderReviewListUsecase
+	Accrual                accrual.IService
+}
+
+// сумма указывается, только если администратор исправляет отложенное начисление
+type AccrualReviewApproveRequest struct {
+	Accrual *decimal.Decimal `json:"accrual"`
+}
+
+type AccrualReviewResult struct {
+	Number  string             `json:"number"`
+	Status  domain.OrderStatus `json:"status"`
+	Accrual entities.GDecimal  `json:"accrual"`
+}
+
+func (ctrl *AccrualReviewController) ReviewList(c *gin.Context) {
+	const errorPrefix = "AccrualReviewController -> ReviewList()"
+	ctx := c.Request.Context()
+
+	orders, err := ctrl.OrderReviewListUsecase.Call(ctx)
+	if err != nil {
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	if len(orders) == 0 {
+		c.Status(http.StatusNoContent)
+	} else {
+		c.JSON(http.StatusOK, orders)
+	}
+}
+
+func (ctrl *AccrualReviewController) Approve(c *gin.Context) {
+	const errorPrefix = "AccrualReviewController -> Approve()"
+	ctx := c.Request.Context()
+
+	var form = AccrualReviewApproveRequest{}
+	if err := c.ShouldBindJSON(&form); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := ctrl.Accrual.Approve(ctx, c.Param("number"), form.Accrual)
+	ctrl.respond(c, order, err, errorPrefix)
+}
+
+func (ctrl *AccrualReviewController) Reject(c *gin.Context) {
+	const errorPrefix = "AccrualReviewController -> Reject()"
+	ctx := c.Request.Context()
+
+	order, err := ctrl.Accrual.Reject(ctx, c.Param("number"))
+	ctrl.respond(c, order, err, errorPrefix)
+}
+
+func (ctrl *AccrualReviewController) respond(c *gin.Context, order *domain.Order, err error, errorPrefix string) {
+	switch {
+	case err == accrual.ErrUnknownOrder:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err == accrual.ErrOrderNotInReview:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err == accrual.ErrInvalidReviewAmount:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		handleInternalError(c, c.Request.Context(), err, errorPrefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, AccrualReviewResult{Number: order.Number, Status: order.Status, Accrual: entities.GDecimal(order.Accrual)})
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/accrual"
+	mock_accrual "github.com/ex0rcist/gophermart/internal/accrual/mocks"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	mock_usecase "github.com/ex0rcist/gophermart/internal/usecase/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccrualReviewController_ReviewList(t *testing.T) {
+	tests := []struct {
+		name     string
+		orders   []*usecase.OrderReviewResult
+		err      error
+		expected int
+	}{
+		{"success", []*usecase.OrderReviewResult{{Number: "12345678903", Reason: "negative accrual -10"}}, nil, http.StatusOK},
+		{"empty", []*usecase.OrderReviewResult{}, nil, http.StatusNoContent},
+		{"internal error", nil, errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockListUsecase := mock_usecase.NewMockIOrderReviewListUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			reviewController := &AccrualReviewController{OrderReviewListUsecase: mockListUsecase}
+
+			r.GET("/orders/review", reviewController.ReviewList)
+
+			mockListUsecase.EXPECT().Call(gomock.Any()).Return(tt.orders, tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/orders/review", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"reason":"negative accrual -10"`)
+			}
+		})
+	}
+}
+
+func TestAccrualReviewController_Approve(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		amount   *decimal.Decimal
+		err      error
+		call     bool
+		expected int
+	}{
+		{"held amount", ``, nil, nil, true, http.StatusOK},
+		{"corrected amount", `{"accrual":300}`, decimalPtr(300), nil, true, http.StatusOK},
+		{"malformed body", `{"accrual":`, nil, nil, false, http.StatusUnprocessableEntity},
+		{"unknown order", ``, nil, accrual.ErrUnknownOrder, true, http.StatusNotFound},
+		{"not in review", ``, nil, accrual.ErrOrderNotInReview, true, http.StatusConflict},
+		{"negative amount", `{"accrual":-1}`, decimalPtr(-1), accrual.ErrInvalidReviewAmount, true, http.StatusUnprocessableEntity},
+		{"internal error", ``, nil, errors.New("database error"), true, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mock_accrual.NewMockIService(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			reviewController := &AccrualReviewController{Accrual: mockService}
+
+			r.POST("/orders/:number/approve", reviewController.Approve)
+
+			if tt.call {
+				var order *domain.Order
+				if tt.err == nil {
+					order = &domain.Order{Number: "12345678903", Status: domain.OrderStatusProcessed, Accrual: decimal.NewFromInt(300)}
+				}
+				mockService.EXPECT().Approve(gomock.Any(), "12345678903", tt.amount).Return(order, tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/orders/12345678903/approve", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusOK {
+				assert.JSONEq(t, `{"number":"12345678903","status":"PROCESSED","accrual":300}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAccrualReviewController_Reject(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, http.StatusOK},
+		{"unknown order", accrual.ErrUnknownOrder, http.StatusNotFound},
+		{"not in review", accrual.ErrOrderNotInReview, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mock_accrual.NewMockIService(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			reviewController := &AccrualReviewController{Accrual: mockService}
+
+			r.POST("/orders/:number/reject", reviewController.Reject)
+
+			var order *domain.Order
+			if tt.err == nil {
+				order = &domain.Order{Number: "12345678903", Status: domain.OrderStatusInvalid}
+			}
+			mockService.EXPECT().Reject(gomock.Any(), "12345678903").Return(order, tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/orders/12345678903/reject", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func decimalPtr(v int64) *decimal.Decimal {
+	d := decimal.NewFromInt(v)
+	return &d
+}
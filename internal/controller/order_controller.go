@@ -19,14 +19,15 @@ func (ctrl *OrderController) CreateOrder(c *gin.Context) {
 	ctx := c.Request.Context()
 	currentUser := getCurrentUser(c)
 
+	// данные о покупке и способ расчета от пользователя не принимаются, иначе начисление
+	// считалось бы по ценам, которые он указал сам
 	body, err := c.GetRawData()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Unable to read request body"})
 		return
 	}
-	number := strings.TrimSpace(string(body))
 
-	_, err = ctrl.OrderCreateUsecase.Create(ctx, currentUser, number)
+	_, err = ctrl.OrderCreateUsecase.Create(ctx, currentUser, strings.TrimSpace(string(body)))
 	if err != nil {
 		switch {
 		case err == usecase.ErrInvalidOrderNumber:
@@ -47,6 +48,41 @@ func (ctrl *OrderController) CreateOrder(c *gin.Context) {
 	// приняли в обработку
 	c.Status(http.StatusAccepted)
 }
+
+// заказ с данными о покупке для встроенного расчета, передаваемый магазином через служебный API
+func (ctrl *OrderController) CreatePurchaseOrder(c *gin.Context) {
+	const errorPrefix = "OrderController -> CreatePurchaseOrder()"
+	ctx := c.Request.Context()
+
+	var form usecase.OrderCreateRequest
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, err := ctrl.OrderCreateUsecase.CreateWithPurchase(ctx, form)
+	if err != nil {
+		switch {
+		case err == usecase.ErrInvalidOrderNumber,
+			err == usecase.ErrInvalidAccrualEngine,
+			err == usecase.ErrInvalidPurchase,
+			err == usecase.ErrUserNotFound:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case err == usecase.ErrOrderAlreadyRegistered:
+			c.Status(http.StatusOK)
+			return
+		case err == usecase.ErrOrderConflict:
+			c.Status(http.StatusConflict)
+			return
+		default:
+			handleInternalError(c, ctx, err, errorPrefix)
+			return
+		}
+	}
+
+	c.Status(http.StatusAccepted)
+}
 func (ctrl *OrderController) OrderList(c *gin.Context) {
 	const errorPrefix = "OrderController -> OrderList()"
 	ctx := c.Request.Context()
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -213,3 +214,96 @@ func TestOrderController_OrderList_Error(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+// пользователь не может передать данные о покупке или выбрать способ расчета:
+// тело запроса всегда считается номером заказа
+func TestOrderController_CreateOrder_IgnoresPurchase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCreateUsecase := mock_usecase.NewMockIOrderCreateUsecase(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	orderController := &OrderController{OrderCreateUsecase: mockCreateUsecase}
+
+	r.POST("/orders", orderController.CreateOrder)
+
+	body := `{"order":"12345678903","accrual_engine":"internal","items":[{"price":1000000}]}`
+	mockCreateUsecase.EXPECT().Create(gomock.Any(), gomock.Any(), body).Return(nil, usecase.ErrInvalidOrderNumber)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestOrderController_CreatePurchaseOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		expected int
+	}{
+		{"success", `{"login":"me","order":"12345678903","accrual_engine":"internal","store":"downtown","items":[{"product":"bork-k800","price":7000}]}`, nil, http.StatusAccepted},
+		{"already registered", `{"login":"me","order":"12345678903"}`, usecase.ErrOrderAlreadyRegistered, http.StatusOK},
+		{"conflict", `{"login":"me","order":"12345678903"}`, usecase.ErrOrderConflict, http.StatusConflict},
+		{"unknown user", `{"login":"ghost","order":"12345678903"}`, usecase.ErrUserNotFound, http.StatusUnprocessableEntity},
+		{"invalid engine", `{"login":"me","order":"12345678903","accrual_engine":"magic"}`, usecase.ErrInvalidAccrualEngine, http.StatusUnprocessableEntity},
+		{"invalid item", `{"login":"me","order":"12345678903","items":[{"price":-1}]}`, usecase.ErrInvalidPurchase, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCreateUsecase := mock_usecase.NewMockIOrderCreateUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			orderController := &OrderController{OrderCreateUsecase: mockCreateUsecase}
+
+			r.POST("/orders", orderController.CreatePurchaseOrder)
+
+			mockCreateUsecase.EXPECT().CreateWithPurchase(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, form usecase.OrderCreateRequest) (*domain.Order, error) {
+					assert.Equal(t, "12345678903", form.Number)
+					return &domain.Order{}, tt.err
+				},
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestOrderController_CreatePurchaseOrder_MalformedJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCreateUsecase := mock_usecase.NewMockIOrderCreateUsecase(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	orderController := &OrderController{OrderCreateUsecase: mockCreateUsecase}
+
+	r.POST("/orders", orderController.CreatePurchaseOrder)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{"order":`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	statementFormatJSON = "json"
+	statementFormatCSV  = "csv"
+	statementFormatText = "text"
+)
+
+type StatementController struct {
+	StatementUsecase usecase.IStatementUsecase
+}
+
+// формат выписки задается параметром format: json (по умолчанию), csv или text
+func (ctrl *StatementController) GetStatement(c *gin.Context) {
+	const ep = "StatementController -> GetStatement()"
+	ctx := c.Request.Context()
+	currentUser := getCurrentUser(c)
+
+	format := c.DefaultQuery("format", statementFormatJSON)
+	if format != statementFormatJSON && format != statementFormatCSV && format != statementFormatText {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported statement format"})
+		return
+	}
+
+	statement, err := ctrl.StatementUsecase.Call(ctx, currentUser, c.Param("period"))
+	switch {
+	case err == usecase.ErrInvalidStatementPeriod:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err == usecase.ErrStatementPeriodNotClosed:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		handleInternalError(c, ctx, err, ep)
+		return
+	}
+
+	switch format {
+	case statementFormatCSV:
+		data, err := renderStatementCSV(statement)
+		if err != nil {
+			handleInternalError(c, ctx, err, ep)
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s.csv"`, statement.Period))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+	case statementFormatText:
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s.txt"`, statement.Period))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", renderStatementText(statement))
+	default:
+		c.JSON(http.StatusOK, statement)
+	}
+}
+
+func statementRows(s *usecase.StatementResult) [][2]string {
+	amount := func(d entities.GDecimal) string {
+		return decimal.Decimal(d).StringFixed(2)
+	}
+
+	return [][2]string{
+		{"period", s.Period},
+		{"opening_balance", amount(s.OpeningBalance)},
+		{"accruals", amount(s.Accruals)},
+		{"withdrawals", amount(s.Withdrawals)},
+		{"refunds", amount(s.Refunds)},
+		{"transfers_in", amount(s.TransfersIn)},
+		{"transfers_out", amount(s.TransfersOut)},
+		{"expirations", amount(s.Expirations)},
+		{"closing_balance", amount(s.ClosingBalance)},
+		{"generated_at", time.Time(s.GeneratedAt).Format(time.RFC3339)},
+	}
+}
+
+// строка заголовков и строка значений
+func renderStatementCSV(s *usecase.StatementResult) ([]byte, error) {
+	rows := statementRows(s)
+	header := make([]string, 0, len(rows))
+	values := make([]string, 0, len(rows))
+	for _, r := range rows {
+		header = append(header, r[0])
+		values = append(values, r[1])
+	}
+
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	if err := w.WriteAll([][]string{header, values}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderStatementText(s *usecase.StatementResult) []byte {
+	buf := new(bytes.Buffer)
+	for _, r := range statementRows(s) {
+		fmt.Fprintf(buf, "%-16s %s\n", r[0]+":", r[1])
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	mock_usecase "github.com/ex0rcist/gophermart/internal/usecase/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func testStatementResult() *usecase.StatementResult {
+	return &usecase.StatementResult{
+		Period:         "2024-05",
+		OpeningBalance: entities.GDecimal(decimal.NewFromInt(100)),
+		Accruals:       entities.GDecimal(decimal.NewFromInt(50)),
+		Withdrawals:    entities.GDecimal(decimal.NewFromInt(30)),
+		Refunds:        entities.GDecimal(decimal.Zero),
+		TransfersIn:    entities.GDecimal(decimal.Zero),
+		TransfersOut:   entities.GDecimal(decimal.Zero),
+		Expirations:    entities.GDecimal(decimal.Zero),
+		ClosingBalance: entities.GDecimal(decimal.NewFromInt(120)),
+		GeneratedAt:    entities.RFC3339Time(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)),
+	}
+}
+
+func TestStatementController_GetStatement(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		err         error
+		expected    int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "json",
+			expected:    http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body:        `"closing_balance":120`,
+		},
+		{
+			name:        "csv",
+			query:       "?format=csv",
+			expected:    http.StatusOK,
+			contentType: "text/csv; charset=utf-8",
+			body:        "period,opening_balance,accruals,withdrawals,refunds,transfers_in,transfers_out,expirations,closing_balance,generated_at\n2024-05,100.00,50.00,30.00,0.00,0.00,0.00,0.00,120.00,2024-06-01T10:00:00Z\n",
+		},
+		{
+			name:        "text",
+			query:       "?format=text",
+			expected:    http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        "closing_balance: 120.00\n",
+		},
+		{"invalid period", "", usecase.ErrInvalidStatementPeriod, http.StatusBadRequest, "", ""},
+		{"period not closed", "", usecase.ErrStatementPeriodNotClosed, http.StatusUnprocessableEntity, "", ""},
+		{"internal error", "", errors.New("database error"), http.StatusInternalServerError, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStatementUsecase := mock_usecase.NewMockIStatementUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			statementController := &StatementController{
+				StatementUsecase: mockStatementUsecase,
+			}
+
+			r.GET("/statements/:period", statementController.GetStatement)
+
+			if tt.err != nil {
+				mockStatementUsecase.EXPECT().Call(gomock.Any(), gomock.Any(), "2024-05").Return(nil, tt.err)
+			} else {
+				mockStatementUsecase.EXPECT().Call(gomock.Any(), gomock.Any(), "2024-05").Return(testStatementResult(), nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/statements/2024-05"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			}
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+}
+
+func TestStatementController_GetStatement_UnsupportedFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStatementUsecase := mock_usecase.NewMockIStatementUsecase(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	statementController := &StatementController{
+		StatementUsecase: mockStatementUsecase,
+	}
+
+	r.GET("/statements/:period", statementController.GetStatement)
+
+	req := httptest.NewRequest(http.MethodGet, "/statements/2024-05?format=pdf", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
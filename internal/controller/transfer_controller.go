@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferController struct {
+	TransferBalanceUsecase usecase.ITransferBalanceUsecase
+	TransferListUsecase    usecase.ITransferListUsecase
+}
+
+func (ctrl *TransferController) TransferBalance(c *gin.Context) {
+	const ep = "TransferController -> TransferBalance()"
+	ctx := c.Request.Context()
+	currentUser := getCurrentUser(c)
+
+	var form = usecase.TransferBalanceRequest{}
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := ctrl.TransferBalanceUsecase.Call(ctx, currentUser, form)
+	switch {
+	case err == usecase.ErrInvalidTransferAmount, err == usecase.ErrTransferToSelf:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err == usecase.ErrTransferRecipientNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err == usecase.ErrInsufficientUserBalance:
+		c.Status(http.StatusPaymentRequired)
+		return
+	case err == usecase.ErrTransferDailyLimitExceeded:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		handleInternalError(c, ctx, err, ep)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (ctrl *TransferController) TransferList(c *gin.Context) {
+	const errorPrefix = "TransferController -> TransferList()"
+	ctx := c.Request.Context()
+	currentUser := getCurrentUser(c)
+
+	transfers, err := ctrl.TransferListUsecase.Call(ctx, currentUser)
+	if err != nil && err != storage.ErrRecordNotFound {
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	if len(transfers) == 0 {
+		c.Status(http.StatusNoContent)
+	} else {
+		c.JSON(http.StatusOK, transfers)
+	}
+}
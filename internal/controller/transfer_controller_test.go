@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/usecase"
+	mock_usecase "github.com/ex0rcist/gophermart/internal/usecase/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransferController_TransferBalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, http.StatusOK},
+		{"recipient not found", usecase.ErrTransferRecipientNotFound, http.StatusNotFound},
+		{"self transfer", usecase.ErrTransferToSelf, http.StatusUnprocessableEntity},
+		{"insufficient balance", usecase.ErrInsufficientUserBalance, http.StatusPaymentRequired},
+		{"limit exceeded", usecase.ErrTransferDailyLimitExceeded, http.StatusForbidden},
+		{"internal error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTransferUsecase := mock_usecase.NewMockITransferBalanceUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			transferController := &TransferController{
+				TransferBalanceUsecase: mockTransferUsecase,
+			}
+
+			r.POST("/transfer", transferController.TransferBalance)
+
+			mockTransferUsecase.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/transfer", bytes.NewBufferString(`{"login":"mom","sum":10}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestTransferController_TransferList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockListUsecase := mock_usecase.NewMockITransferListUsecase(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	transferController := &TransferController{
+		TransferListUsecase: mockListUsecase,
+	}
+
+	r.GET("/transfers", transferController.TransferList)
+
+	transfers := []*usecase.TransferListResult{
+		{
+			Direction: usecase.TransferDirectionIncoming,
+			Login:     "dad",
+			Amount:    entities.GDecimal(decimal.NewFromInt(20)),
+			CreatedAt: entities.RFC3339Time(time.Now()),
+		},
+	}
+
+	mockListUsecase.EXPECT().Call(gomock.Any(), gomock.Any()).Return(transfers, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/transfers", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"direction":"INCOMING"`)
+	assert.Contains(t, w.Body.String(), `"login":"dad"`)
+}
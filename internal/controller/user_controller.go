@@ -12,6 +12,7 @@ type UserController struct {
 	RegisterUsecase        usecase.IRegisterUsecase
 	GetUserBalanceUsecase  usecase.IGetUserBalanceUsecase
 	WithdrawBalanceUsecase usecase.IWithdrawBalanceUsecase
+	ChangePasswordUsecase  usecase.IChangePasswordUsecase
 }
 
 func (ctrl *UserController) Login(c *gin.Context) {
@@ -98,9 +99,19 @@ func (ctrl *UserController) WithdrawBalance(c *gin.Context) {
 		// первая в form (для общего развития)
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
+	case err == usecase.ErrInvalidWithdrawalAmount,
+		err == usecase.ErrWithdrawalAmountTooSmall,
+		err == usecase.ErrWithdrawalAmountTooLarge:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
 	case err == usecase.ErrInsufficientUserBalance:
 		c.Status(http.StatusPaymentRequired)
 		return
+	case err == usecase.ErrWithdrawalDailyLimitExceeded,
+		err == usecase.ErrWithdrawalMonthlyLimitExceeded,
+		err == usecase.ErrWithdrawalCooldown:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
 	case err != nil:
 		handleInternalError(c, ctx, err, ep)
 		return
@@ -108,3 +119,28 @@ func (ctrl *UserController) WithdrawBalance(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+func (ctrl *UserController) ChangePassword(c *gin.Context) {
+	const errorPrefix = "UserController -> ChangePassword()"
+	ctx := c.Request.Context()
+	currentUser := getCurrentUser(c)
+
+	var form usecase.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := ctrl.ChangePasswordUsecase.Call(ctx, currentUser, form)
+	if err != nil {
+		if err == usecase.ErrInvalidPassword {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
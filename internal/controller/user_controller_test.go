@@ -209,3 +209,87 @@ func TestUserController_WithdrawBalance_InsufficientBalance(t *testing.T) {
 
 	assert.Equal(t, http.StatusPaymentRequired, w.Code)
 }
+
+func TestUserController_WithdrawBalance_RuleViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"invalid amount", usecase.ErrInvalidWithdrawalAmount, http.StatusUnprocessableEntity},
+		{"too small", usecase.ErrWithdrawalAmountTooSmall, http.StatusUnprocessableEntity},
+		{"too large", usecase.ErrWithdrawalAmountTooLarge, http.StatusUnprocessableEntity},
+		{"daily limit", usecase.ErrWithdrawalDailyLimitExceeded, http.StatusForbidden},
+		{"monthly limit", usecase.ErrWithdrawalMonthlyLimitExceeded, http.StatusForbidden},
+		{"cooldown", usecase.ErrWithdrawalCooldown, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWithdrawBalanceUsecase := mock_usecase.NewMockIWithdrawBalanceUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			userController := &UserController{
+				WithdrawBalanceUsecase: mockWithdrawBalanceUsecase,
+			}
+
+			r.POST("/withdraw", userController.WithdrawBalance)
+
+			withdrawRequest := `{"order":"12345678903","sum":150.0}`
+			mockWithdrawBalanceUsecase.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/withdraw", bytes.NewBuffer([]byte(withdrawRequest)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestUserController_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		calls    int
+		expected int
+	}{
+		{"success", `{"password":"old","new_password":"new"}`, nil, 1, http.StatusOK},
+		{"invalid password", `{"password":"wrong","new_password":"new"}`, usecase.ErrInvalidPassword, 1, http.StatusUnauthorized},
+		{"short new password", `{"password":"old","new_password":"n"}`, nil, 0, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChangePasswordUsecase := mock_usecase.NewMockIChangePasswordUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			userController := &UserController{
+				ChangePasswordUsecase: mockChangePasswordUsecase,
+			}
+
+			r.POST("/password", userController.ChangePassword)
+
+			mockChangePasswordUsecase.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.err).Times(tt.calls)
+
+			req := httptest.NewRequest(http.MethodPost, "/password", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
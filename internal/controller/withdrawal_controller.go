@@ -9,7 +9,8 @@ import (
 )
 
 type WithdrawalController struct {
-	WithdrawalListUsecase usecase.IWithdrawalListUsecase
+	WithdrawalListUsecase   usecase.IWithdrawalListUsecase
+	RefundWithdrawalUsecase usecase.IRefundWithdrawalUsecase
 }
 
 func (ctrl *WithdrawalController) WithdrawalList(c *gin.Context) {
@@ -29,3 +30,29 @@ func (ctrl *WithdrawalController) WithdrawalList(c *gin.Context) {
 		c.JSON(http.StatusOK, wds)
 	}
 }
+
+func (ctrl *WithdrawalController) RefundWithdrawal(c *gin.Context) {
+	const errorPrefix = "WithdrawalController -> RefundWithdrawal()"
+	ctx := c.Request.Context()
+
+	var form = usecase.RefundWithdrawalRequest{}
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	wd, err := ctrl.RefundWithdrawalUsecase.Call(ctx, form)
+	switch {
+	case err == usecase.ErrInvalidOrderNumber, err == usecase.ErrInvalidRefundAmount, err == usecase.ErrRefundExceedsWithdrawal:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err == usecase.ErrWithdrawalNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		handleInternalError(c, ctx, err, errorPrefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, wd)
+}
@@ -1,12 +1,14 @@
 package controller
 
 import (
+	"bytes"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/ex0rcist/gophermart/internal/domain"
 	"github.com/ex0rcist/gophermart/internal/entities"
 	"github.com/ex0rcist/gophermart/internal/storage"
 	"github.com/ex0rcist/gophermart/internal/usecase"
@@ -130,3 +132,79 @@ func TestWithdrawalController_WithdrawalList_RecordNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
 }
+
+func TestWithdrawalController_RefundWithdrawal_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundUsecase := mock_usecase.NewMockIRefundWithdrawalUsecase(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	withdrawalController := &WithdrawalController{
+		RefundWithdrawalUsecase: mockRefundUsecase,
+	}
+
+	r.POST("/refund", withdrawalController.RefundWithdrawal)
+
+	refunded := entities.GDecimal(decimal.NewFromInt(40))
+	result := &usecase.WithdrawalListResult{
+		OrderNumber: "12345678903",
+		Amount:      entities.GDecimal(decimal.NewFromInt(100)),
+		Status:      domain.WithdrawalStatusPartiallyRefunded,
+		Refunded:    &refunded,
+		CreatedAt:   entities.RFC3339Time(time.Now()),
+	}
+
+	mockRefundUsecase.EXPECT().Call(gomock.Any(), gomock.Any()).Return(result, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/refund", bytes.NewBufferString(`{"order":"12345678903","sum":40}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"PARTIALLY_REFUNDED"`)
+	assert.Contains(t, w.Body.String(), `"refunded":40`)
+}
+
+func TestWithdrawalController_RefundWithdrawal_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"not found", usecase.ErrWithdrawalNotFound, http.StatusNotFound},
+		{"exceeds withdrawal", usecase.ErrRefundExceedsWithdrawal, http.StatusUnprocessableEntity},
+		{"invalid amount", usecase.ErrInvalidRefundAmount, http.StatusUnprocessableEntity},
+		{"internal error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRefundUsecase := mock_usecase.NewMockIRefundWithdrawalUsecase(ctrl)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+			withdrawalController := &WithdrawalController{
+				RefundWithdrawalUsecase: mockRefundUsecase,
+			}
+
+			r.POST("/refund", withdrawalController.RefundWithdrawal)
+
+			mockRefundUsecase.EXPECT().Call(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/refund", bytes.NewBufferString(`{"order":"12345678903"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// обращение к системе начислений, сохраняется для разбора спорных начислений
+type AccrualCall struct {
+	ID           int64
+	OrderNumber  string
+	Provider     string
+	URL          string
+	HTTPStatus   int // 0 - ответ не получен
+	ResponseBody string
+	Error        string
+	Latency      time.Duration
+	RequestID    string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// задача опроса системы начислений по необработанному заказу
+type AccrualJob struct {
+	OrderID        OrderID
+	Order          *Order
+	Attempts       int // неудачные или безрезультатные попытки подряд
+	Failures       int // ошибочные ответы системы начислений подряд
+	NextAttemptAt  time.Time
+	LastError      string
+	LastHTTPStatus int
+	LastResponse   string
+	LeaseOwner     string
+	RequestID      string // запрос, создавший заказ, для сквозного поиска по логам
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	// задача перестает опрашиваться после исчерпания попыток
+	DeadAt *time.Time
+
+	// у обработанного заказа - окончание окна, в течение которого учитываются исправления начисления
+	CorrectUntil *time.Time
+}
+
+func (j *AccrualJob) IsDead() bool {
+	return j.DeadAt != nil
+}
+
+func (j *AccrualJob) IsCorrection() bool {
+	return j.CorrectUntil != nil
+}
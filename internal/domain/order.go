@@ -16,18 +16,40 @@ const (
 	OrderStatusProcessing OrderStatus = "PROCESSING"
 	OrderStatusInvalid    OrderStatus = "INVALID"
 	OrderStatusProcessed  OrderStatus = "PROCESSED"
+
+	// подозрительное начисление ждет решения администратора, заказ не опрашивается
+	OrderStatusNeedsReview OrderStatus = "NEEDS_REVIEW"
 )
 
 type Order struct {
-	ID        OrderID
-	UserID    UserID
-	Number    string
-	Status    OrderStatus
-	Accrual   decimal.Decimal
+	ID          OrderID
+	UserID      UserID
+	Number      string
+	Status      OrderStatus
+	Accrual     decimal.Decimal
+	BaseAccrual decimal.Decimal // начисление до применения множителя уровня
+	RequestID   string          // запрос, создавший заказ; передается в задачу опроса начислений
+	Engine      AccrualEngine   // пустой - способ расчета по умолчанию из конфигурации
+	Store       string          // магазин из данных о покупке; заполняется в задачах опроса для выбора системы начислений
+	Purchase    *Purchase
+
+	// начисление, отложенное до ручной проверки, и причина проверки
+	ReviewAccrual decimal.Decimal
+	ReviewReason  string
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// заказ в конечном статусе больше не опрашивается
+func (o *Order) IsFinal() bool {
+	return o.Status == OrderStatusProcessed || o.Status == OrderStatusInvalid
+}
+
+func (o *Order) AwaitsReview() bool {
+	return o.Status == OrderStatusNeedsReview
+}
+
 func (o *Order) String() string {
 	str := []string{
 		fmt.Sprintf("user_id=%d", o.UserID),
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// корректирующая проводка по обработанному заказу, если система начислений исправила начисление
+type OrderAdjustment struct {
+	ID          int32
+	OrderID     OrderID
+	UserID      UserID
+	Amount      decimal.Decimal // изменение баланса, может быть отрицательным
+	BaseAccrual decimal.Decimal // исправленное начисление системы до применения множителя уровня
+	Unrecovered decimal.Decimal // часть списания, не взысканная, чтобы баланс не стал отрицательным
+	CreatedAt   time.Time
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// способ расчета начислений по заказу
+type AccrualEngine string
+
+const (
+	AccrualEngineExternal AccrualEngine = "external" // внешняя система начислений
+	AccrualEngineInternal AccrualEngine = "internal" // встроенный расчет по локальным правилам
+)
+
+func (e AccrualEngine) IsValid() bool {
+	return e == AccrualEngineExternal || e == AccrualEngineInternal
+}
+
+type PurchaseItem struct {
+	Product  string
+	Category string
+	Price    decimal.Decimal // цена за единицу
+	Quantity int
+}
+
+func (i PurchaseItem) Amount() decimal.Decimal {
+	return i.Price.Mul(decimal.NewFromInt(int64(i.Quantity)))
+}
+
+// данные о покупке, передаваемые вместе с заказом для встроенного расчета начислений
+type Purchase struct {
+	Store     string
+	Items     []PurchaseItem
+	CreatedAt time.Time
+}
+
+// стоимость покупки
+func (p *Purchase) Total() decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range p.Items {
+		total = total.Add(item.Amount())
+	}
+
+	return total
+}
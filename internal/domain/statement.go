@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type StatementID int32
+
+// выписка по счету пользователя за календарный месяц;
+// после сохранения не изменяется, даже если позже данные за период были скорректированы
+type Statement struct {
+	ID             StatementID
+	UserID         UserID
+	Period         time.Time // первое число месяца
+	OpeningBalance decimal.Decimal
+	Accruals       decimal.Decimal
+	Withdrawals    decimal.Decimal
+	Refunds        decimal.Decimal
+	TransfersIn    decimal.Decimal
+	TransfersOut   decimal.Decimal
+	Expirations    decimal.Decimal
+	ClosingBalance decimal.Decimal
+	CreatedAt      time.Time
+}
+
+// изменение баланса за период по всем операциям
+func (s *Statement) Turnover() decimal.Decimal {
+	return s.Accruals.
+		Add(s.Refunds).
+		Add(s.TransfersIn).
+		Sub(s.Withdrawals).
+		Sub(s.TransfersOut).
+		Sub(s.Expirations)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TransferID int32
+
+// перевод баллов между пользователями
+type Transfer struct {
+	ID             TransferID
+	SenderID       UserID
+	SenderLogin    string
+	RecipientID    UserID
+	RecipientLogin string
+	Amount         decimal.Decimal
+	CreatedAt      time.Time
+}
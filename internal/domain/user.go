@@ -7,12 +7,16 @@ import (
 )
 
 type UserID int32
+type LoyaltyTier string
 
 type User struct {
 	ID        UserID
 	Login     string
 	Password  string
 	Balance   decimal.Decimal
+	Tier      LoyaltyTier
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	PasswordChangedAt *time.Time
 }
@@ -7,11 +7,36 @@ import (
 )
 
 type WithdrawalID int32
+type WithdrawalStatus string
+
+const (
+	WithdrawalStatusWithdrawn         WithdrawalStatus = "WITHDRAWN"
+	WithdrawalStatusPartiallyRefunded WithdrawalStatus = "PARTIALLY_REFUNDED"
+	WithdrawalStatusRefunded          WithdrawalStatus = "REFUNDED"
+)
 
 type Withdrawal struct {
 	ID          WithdrawalID
 	UserID      UserID
 	OrderNumber string
 	Amount      decimal.Decimal
+	Refunded    decimal.Decimal
+	Status      WithdrawalStatus
 	CreatedAt   time.Time
 }
+
+// остаток списания, доступный для возврата
+func (w *Withdrawal) Refundable() decimal.Decimal {
+	return w.Amount.Sub(w.Refunded)
+}
+
+type WithdrawalRefundID int32
+
+// возврат (полный или частичный) ранее списанных баллов
+type WithdrawalRefund struct {
+	ID           WithdrawalRefundID
+	WithdrawalID WithdrawalID
+	UserID       UserID
+	Amount       decimal.Decimal
+	CreatedAt    time.Time
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/ex0rcist/gophermart/internal/accrual"
 	"github.com/ex0rcist/gophermart/internal/config"
 	"github.com/ex0rcist/gophermart/internal/controller"
 	"github.com/ex0rcist/gophermart/internal/middleware"
@@ -12,6 +13,7 @@ import (
 	"github.com/ex0rcist/gophermart/internal/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
 )
 
 type IHTTPBackend interface {
@@ -20,14 +22,22 @@ type IHTTPBackend interface {
 }
 
 type HTTPBackend struct {
-	config     *config.Server
+	config     *config.Config
 	httpServer *http.Server
 	router     *gin.Engine
-	storage    storage.IPGXStorage
+	storage    storage.IStorage
+	repos      *repository.Repositories
+	accrual    accrual.IService
 }
 
-func NewHTTPBackend(ctx context.Context, config *config.Server, storage storage.IPGXStorage) *HTTPBackend {
-	b := &HTTPBackend{config: config, storage: storage}
+func NewHTTPBackend(
+	ctx context.Context,
+	config *config.Config,
+	storage storage.IStorage,
+	repos *repository.Repositories,
+	accrService accrual.IService,
+) *HTTPBackend {
+	b := &HTTPBackend{config: config, storage: storage, repos: repos, accrual: accrService}
 	b.setupRouter()
 	b.setupRoutes()
 	b.setupServer()
@@ -60,28 +70,64 @@ func (b *HTTPBackend) setupRoutes() {
 		c.JSON(http.StatusOK, gin.H{"message": "pong"})
 	})
 
+	b.router.GET("/health", b.health)
+
 	publicRouter := b.router.Group("")
 
 	privateRouter := b.router.Group("")
 	privateRouter.Use(middleware.Auth(
-		repository.NewUserRepository(b.storage.GetPool()),
-		b.config.Secret,
+		b.repos.User,
+		b.config.Server.Secret,
 	))
 
-	b.setupUserController(publicRouter, privateRouter)
-	b.setupOrderController(publicRouter, privateRouter)
-	b.setupWithdrawalController(publicRouter, privateRouter)
+	adminRouter := b.router.Group("/api/admin")
+	adminRouter.Use(middleware.AdminAuth(b.config.Server.AdminToken))
+
+	b.setupUserController(publicRouter, privateRouter, adminRouter)
+	b.setupOrderController(publicRouter, privateRouter, adminRouter)
+	b.setupWithdrawalController(publicRouter, privateRouter, adminRouter)
+	b.setupTransferController(publicRouter, privateRouter, adminRouter)
+	b.setupStatementController(publicRouter, privateRouter, adminRouter)
+	b.setupAccrualJobController(publicRouter, privateRouter, adminRouter)
+	b.setupAccrualCallController(publicRouter, privateRouter, adminRouter)
+	b.setupAccrualPoolController(publicRouter, privateRouter, adminRouter)
+	b.setupAccrualCallbackController(publicRouter, privateRouter, adminRouter)
+	b.setupAccrualReviewController(publicRouter, privateRouter, adminRouter)
+}
+
+// при разомкнутой цепи accrual сервис продолжает принимать запросы, но начисления задерживаются
+func (b *HTTPBackend) health(c *gin.Context) {
+	circuit := b.accrual.CircuitState()
+
+	status := "ok"
+	if circuit != accrual.CircuitClosed {
+		status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  status,
+		"accrual": gin.H{"circuit": circuit},
+	})
 }
 
-func (b *HTTPBackend) setupUserController(publicRouter *gin.RouterGroup, privateRouter *gin.RouterGroup) {
-	userRepo := repository.NewUserRepository(b.storage.GetPool())
-	wdrwRepo := repository.NewWithdrawalRepository(b.storage.GetPool())
+func (b *HTTPBackend) setupUserController(publicRouter *gin.RouterGroup, privateRouter *gin.RouterGroup, _ *gin.RouterGroup) {
+	userRepo := b.repos.User
+	wdrwRepo := b.repos.Withdrawal
+
+	rules := usecase.WithdrawalRules{
+		MinAmount:              decimal.NewFromFloat(b.config.Withdrawal.MinAmount),
+		MaxAmount:              decimal.NewFromFloat(b.config.Withdrawal.MaxAmount),
+		DailyLimit:             decimal.NewFromFloat(b.config.Withdrawal.DailyLimit),
+		MonthlyLimit:           decimal.NewFromFloat(b.config.Withdrawal.MonthlyLimit),
+		PasswordChangeCooldown: b.config.Withdrawal.PasswordChangeCooldown,
+	}
 
 	ctrl := &controller.UserController{
-		LoginUsecase:           usecase.NewLoginUsecase(b.storage, userRepo, b.config.Secret, b.config.Timeout),
-		RegisterUsecase:        usecase.NewRegisterUsecase(b.storage, userRepo, b.config.Secret, b.config.Timeout),
-		GetUserBalanceUsecase:  usecase.NewGetUserBalanceUsecase(b.storage, userRepo, b.config.Timeout),
-		WithdrawBalanceUsecase: usecase.NewWithdrawBalanceUsecase(b.storage, userRepo, wdrwRepo, b.config.Timeout),
+		LoginUsecase:           usecase.NewLoginUsecase(b.storage, userRepo, b.config.Server.Secret, b.config.Server.Timeout),
+		RegisterUsecase:        usecase.NewRegisterUsecase(b.storage, userRepo, b.config.Server.Secret, b.config.Server.Timeout),
+		GetUserBalanceUsecase:  usecase.NewGetUserBalanceUsecase(b.storage, userRepo, b.config.Server.Timeout),
+		WithdrawBalanceUsecase: usecase.NewWithdrawBalanceUsecase(b.storage, userRepo, wdrwRepo, rules, b.config.Server.Timeout),
+		ChangePasswordUsecase:  usecase.NewChangePasswordUsecase(b.storage, userRepo, b.config.Server.Timeout),
 	}
 
 	publicRouter.POST("/api/user/register", ctrl.Register)
@@ -89,33 +135,123 @@ func (b *HTTPBackend) setupUserController(publicRouter *gin.RouterGroup, private
 
 	privateRouter.GET("/api/user/balance", ctrl.GetUserBalance)
 	privateRouter.POST("/api/user/balance/withdraw", ctrl.WithdrawBalance)
+	privateRouter.POST("/api/user/password", ctrl.ChangePassword)
 }
 
-func (b *HTTPBackend) setupOrderController(_ *gin.RouterGroup, privateRouter *gin.RouterGroup) {
-	repo := repository.NewOrderRepository(b.storage.GetPool())
+func (b *HTTPBackend) setupOrderController(_ *gin.RouterGroup, privateRouter *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	repo := b.repos.Order
 
 	ctrl := &controller.OrderController{
-		OrderCreateUsecase: usecase.NewOrderCreateUsecase(b.storage, repo, b.config.Timeout),
-		OrderListUsecase:   usecase.NewOrderListUsecase(b.storage, repo, b.config.Timeout),
+		OrderCreateUsecase: usecase.NewOrderCreateUsecase(b.storage, repo, b.repos.User, b.config.Server.Timeout),
+		OrderListUsecase:   usecase.NewOrderListUsecase(b.storage, repo, b.config.Server.Timeout),
 	}
 
 	privateRouter.POST("/api/user/orders", ctrl.CreateOrder)
 	privateRouter.GET("/api/user/orders", ctrl.OrderList)
+
+	adminRouter.POST("/orders", ctrl.CreatePurchaseOrder)
 }
 
-func (b *HTTPBackend) setupWithdrawalController(_ *gin.RouterGroup, privateRouter *gin.RouterGroup) {
-	repo := repository.NewWithdrawalRepository(b.storage.GetPool())
+func (b *HTTPBackend) setupWithdrawalController(_ *gin.RouterGroup, privateRouter *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	userRepo := b.repos.User
+	repo := b.repos.Withdrawal
 
 	ctrl := &controller.WithdrawalController{
-		WithdrawalListUsecase: usecase.NewWithdrawalListUsecase(b.storage, repo, b.config.Timeout),
+		WithdrawalListUsecase:   usecase.NewWithdrawalListUsecase(b.storage, repo, b.config.Server.Timeout),
+		RefundWithdrawalUsecase: usecase.NewRefundWithdrawalUsecase(b.storage, userRepo, repo, b.config.Server.Timeout),
 	}
 
 	privateRouter.GET("/api/user/withdrawals", ctrl.WithdrawalList)
+
+	adminRouter.POST("/withdrawals/refund", ctrl.RefundWithdrawal)
+}
+
+func (b *HTTPBackend) setupTransferController(_ *gin.RouterGroup, privateRouter *gin.RouterGroup, _ *gin.RouterGroup) {
+	userRepo := b.repos.User
+	repo := b.repos.Transfer
+
+	limits := usecase.TransferLimits{
+		DailyAmount: decimal.NewFromFloat(b.config.Transfer.DailyAmountLimit),
+		DailyCount:  b.config.Transfer.DailyCountLimit,
+	}
+
+	ctrl := &controller.TransferController{
+		TransferBalanceUsecase: usecase.NewTransferBalanceUsecase(b.storage, userRepo, repo, limits, b.config.Server.Timeout),
+		TransferListUsecase:    usecase.NewTransferListUsecase(b.storage, repo, b.config.Server.Timeout),
+	}
+
+	privateRouter.POST("/api/user/balance/transfer", ctrl.TransferBalance)
+	privateRouter.GET("/api/user/transfers", ctrl.TransferList)
+}
+
+func (b *HTTPBackend) setupStatementController(_ *gin.RouterGroup, privateRouter *gin.RouterGroup, _ *gin.RouterGroup) {
+	repo := b.repos.Statement
+
+	ctrl := &controller.StatementController{
+		StatementUsecase: usecase.NewStatementUsecase(b.storage, repo, b.config.Server.Timeout),
+	}
+
+	privateRouter.GET("/api/user/statements/:period", ctrl.GetStatement)
+}
+
+func (b *HTTPBackend) setupAccrualJobController(_ *gin.RouterGroup, _ *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	repo := b.repos.AccrualJob
+
+	ctrl := &controller.AccrualJobController{
+		AccrualJobListUsecase:    usecase.NewAccrualJobListUsecase(b.storage, repo, b.config.Server.Timeout),
+		AccrualJobFindUsecase:    usecase.NewAccrualJobFindUsecase(b.storage, repo, b.config.Server.Timeout),
+		AccrualJobRequeueUsecase: usecase.NewAccrualJobRequeueUsecase(b.storage, repo, b.config.Server.Timeout),
+	}
+
+	adminRouter.GET("/accrual/jobs/dead", ctrl.DeadJobList)
+	adminRouter.GET("/accrual/jobs/:number", ctrl.GetJob)
+	adminRouter.POST("/accrual/jobs/:number/requeue", ctrl.RequeueJob)
+}
+
+func (b *HTTPBackend) setupAccrualCallController(_ *gin.RouterGroup, _ *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	repo := b.repos.AccrualCall
+
+	ctrl := &controller.AccrualCallController{
+		AccrualCallListUsecase: usecase.NewAccrualCallListUsecase(b.storage, repo, b.config.Server.Timeout),
+	}
+
+	adminRouter.GET("/accrual/calls/:number", ctrl.CallList)
+}
+
+func (b *HTTPBackend) setupAccrualPoolController(_ *gin.RouterGroup, _ *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	ctrl := &controller.AccrualPoolController{Pool: b.accrual}
+
+	adminRouter.GET("/accrual/pool", ctrl.GetPool)
+	adminRouter.PUT("/accrual/pool", ctrl.ResizePool)
+}
+
+// результаты, присылаемые системой начислений, принимаются только с подписью общим ключом
+func (b *HTTPBackend) setupAccrualCallbackController(publicRouter *gin.RouterGroup, _ *gin.RouterGroup, _ *gin.RouterGroup) {
+	ctrl := &controller.AccrualCallbackController{Accrual: b.accrual}
+
+	internalRouter := publicRouter.Group("/api/internal")
+	internalRouter.Use(middleware.Signature(b.config.Accrual.CallbackSecret))
+
+	internalRouter.POST("/accrual/callback", ctrl.Callback)
+}
+
+// подозрительные начисления ждут решения администратора
+func (b *HTTPBackend) setupAccrualReviewController(_ *gin.RouterGroup, _ *gin.RouterGroup, adminRouter *gin.RouterGroup) {
+	repo := b.repos.Order
+
+	ctrl := &controller.AccrualReviewController{
+		OrderReviewListUsecase: usecase.NewOrderReviewListUsecase(b.storage, repo, b.config.Server.Timeout),
+		Accrual:                b.accrual,
+	}
+
+	adminRouter.GET("/orders/review", ctrl.ReviewList)
+	adminRouter.POST("/orders/:number/approve", ctrl.Approve)
+	adminRouter.POST("/orders/:number/reject", ctrl.Reject)
 }
 
 func (b *HTTPBackend) setupServer() {
 	b.httpServer = &http.Server{
-		Addr:    b.config.Address,
+		Addr:    b.config.Server.Address,
 		Handler: b.router.Handler(),
 	}
 }
@@ -0,0 +1,123 @@
+package loyalty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidProgram = errors.New("invalid loyalty program")
+
+// уровень программы лояльности
+type Tier struct {
+	Name       domain.LoyaltyTier `yaml:"name"`
+	Threshold  decimal.Decimal    `yaml:"threshold"`  // минимум баллов, начисленных за окно
+	Multiplier decimal.Decimal    `yaml:"multiplier"` // множитель входящих начислений
+}
+
+// программа лояльности: уровни считаются по баллам, начисленным за скользящее окно
+type Program struct {
+	Window time.Duration `yaml:"window"`
+	Tiers  []Tier        `yaml:"tiers"`
+}
+
+// загружает программу из yaml-файла; пустой путь - программа отключена
+func LoadProgram(path string) (*Program, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loyalty: error reading %s: %w", path, err)
+	}
+
+	return ParseProgram(data)
+}
+
+func ParseProgram(data []byte) (*Program, error) {
+	p := &Program{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("loyalty: error parsing program: %w", err)
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	// по возрастанию порога, чтобы TierFor выбирал наибольший достигнутый
+	sort.Slice(p.Tiers, func(i, j int) bool {
+		return p.Tiers[i].Threshold.LessThan(p.Tiers[j].Threshold)
+	})
+
+	return p, nil
+}
+
+// уровень, соответствующий сумме начисленных за окно баллов;
+// пустой уровень, если не достигнут ни один порог
+func (p *Program) TierFor(earned decimal.Decimal) domain.LoyaltyTier {
+	if p == nil {
+		return ""
+	}
+
+	var tier domain.LoyaltyTier
+	for _, t := range p.Tiers {
+		if earned.GreaterThanOrEqual(t.Threshold) {
+			tier = t.Name
+		}
+	}
+
+	return tier
+}
+
+// применяет множитель уровня к начислению
+func (p *Program) Apply(tier domain.LoyaltyTier, amount decimal.Decimal) decimal.Decimal {
+	if p == nil || tier == "" {
+		return amount
+	}
+
+	for _, t := range p.Tiers {
+		if t.Name == tier {
+			return amount.Mul(t.Multiplier).Round(2)
+		}
+	}
+
+	return amount
+}
+
+// начало скользящего окна относительно now
+func (p *Program) WindowStart(now time.Time) time.Time {
+	return now.Add(-p.Window)
+}
+
+func (p *Program) validate() error {
+	if p.Window <= 0 {
+		return fmt.Errorf("%w: window must be positive", ErrInvalidProgram)
+	}
+
+	names := make(map[domain.LoyaltyTier]bool, len(p.Tiers))
+	for _, t := range p.Tiers {
+		if t.Name == "" {
+			return fmt.Errorf("%w: tier name is empty", ErrInvalidProgram)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("%w: duplicate tier %s", ErrInvalidProgram, t.Name)
+		}
+		if t.Threshold.IsNegative() {
+			return fmt.Errorf("%w: tier %s threshold is negative", ErrInvalidProgram, t.Name)
+		}
+		if !t.Multiplier.IsPositive() {
+			return fmt.Errorf("%w: tier %s multiplier must be positive", ErrInvalidProgram, t.Name)
+		}
+
+		names[t.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package loyalty
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProgram = `
+window: 720h
+tiers:
+  - name: GOLD
+    threshold: 2000
+    multiplier: 1.1
+  - name: SILVER
+    threshold: 500
+    multiplier: 1.05
+`
+
+func TestParseProgram(t *testing.T) {
+	p, err := ParseProgram([]byte(testProgram))
+	require.NoError(t, err)
+
+	assert.Equal(t, 720*time.Hour, p.Window)
+	assert.Len(t, p.Tiers, 2)
+	assert.Equal(t, domain.LoyaltyTier("SILVER"), p.Tiers[0].Name) // отсортированы по порогу
+}
+
+func TestParseProgram_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no window", "tiers: []"},
+		{"empty name", "window: 1h\ntiers:\n  - threshold: 1\n    multiplier: 1"},
+		{"duplicate", "window: 1h\ntiers:\n  - {name: A, threshold: 1, multiplier: 1}\n  - {name: A, threshold: 2, multiplier: 1}"},
+		{"zero multiplier", "window: 1h\ntiers:\n  - {name: A, threshold: 1, multiplier: 0}"},
+		{"negative threshold", "window: 1h\ntiers:\n  - {name: A, threshold: -1, multiplier: 1}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProgram([]byte(tt.data))
+			assert.ErrorIs(t, err, ErrInvalidProgram)
+		})
+	}
+}
+
+func TestLoadProgram(t *testing.T) {
+	p, err := LoadProgram("")
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	path := filepath.Join(t.TempDir(), "tiers.yml")
+	require.NoError(t, os.WriteFile(path, []byte(testProgram), 0600))
+
+	p, err = LoadProgram(path)
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+
+	_, err = LoadProgram(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestProgram_TierFor(t *testing.T) {
+	p, err := ParseProgram([]byte(testProgram))
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.LoyaltyTier(""), p.TierFor(decimal.NewFromInt(499)))
+	assert.Equal(t, domain.LoyaltyTier("SILVER"), p.TierFor(decimal.NewFromInt(500)))
+	assert.Equal(t, domain.LoyaltyTier("GOLD"), p.TierFor(decimal.NewFromInt(5000)))
+
+	var disabled *Program
+	assert.Equal(t, domain.LoyaltyTier(""), disabled.TierFor(decimal.NewFromInt(5000)))
+}
+
+func TestProgram_Apply(t *testing.T) {
+	p, err := ParseProgram([]byte(testProgram))
+	require.NoError(t, err)
+
+	amount := decimal.RequireFromString("100.55")
+
+	assert.True(t, p.Apply("", amount).Equal(amount))
+	assert.True(t, p.Apply("UNKNOWN", amount).Equal(amount))
+	assert.True(t, p.Apply("GOLD", amount).Equal(decimal.RequireFromString("110.61")))
+
+	var disabled *Program
+	assert.True(t, disabled.Apply("GOLD", amount).Equal(amount))
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const AdminTokenHeader = "X-Admin-Token"
+
+// доступ к служебным эндпоинтам по статическому токену;
+// если токен не задан в конфигурации, служебные эндпоинты недоступны
+func AdminAuth(key entities.Secret) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if len(key) == 0 {
+			logging.LogInfoCtx(ctx, "admin auth: admin token is not configured")
+			c.Status(http.StatusForbidden)
+			c.Abort()
+			return
+		}
+
+		token := c.Request.Header.Get(AdminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+			logging.LogInfoCtx(ctx, "admin auth: invalid token")
+			c.Status(http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
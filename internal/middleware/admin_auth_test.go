@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      entities.Secret
+		token    string
+		expected int
+	}{
+		{"not configured", entities.Secret(""), "", http.StatusForbidden},
+		{"no token", entities.Secret("admin-secret"), "", http.StatusUnauthorized},
+		{"invalid token", entities.Secret("admin-secret"), "wrong", http.StatusUnauthorized},
+		{"valid token", entities.Secret("admin-secret"), "admin-secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+
+			r.Use(AdminAuth(tt.key))
+			r.GET("/test", func(c *gin.Context) {
+				c.String(http.StatusOK, "ok")
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			if tt.token != "" {
+				req.Header.Set(AdminTokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/ex0rcist/gophermart/internal/utils"
@@ -9,6 +10,10 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// идентификатор клиента сохраняется в БД (VARCHAR(100)) и передается в заголовках внешним системам,
+// поэтому принимается только короткий и из безопасных символов; иначе создается новый
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,100}$`)
+
 func RequestsLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -31,7 +36,7 @@ func RequestsLogger() gin.HandlerFunc {
 
 		c.Writer.Header().Set("X-Request-Id", requestID)
 
-		ctx := logger.WithContext(c.Request.Context())
+		ctx := utils.ContextWithRequestID(logger.WithContext(c.Request.Context()), requestID)
 		c.Request = c.Request.WithContext(ctx)
 
 		// execute
@@ -53,7 +58,7 @@ func RequestsLogger() gin.HandlerFunc {
 func findOrCreateRequestID(r *http.Request) string {
 	requestID := r.Header.Get("X-Request-Id")
 
-	if requestID == "" {
+	if !validRequestID.MatchString(requestID) {
 		requestID = utils.GenerateRequestID()
 	}
 
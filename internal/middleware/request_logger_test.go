@@ -3,7 +3,11 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/gin-gonic/gin"
 )
 
 func TestFindOrCreateRequestID_ExistingID(t *testing.T) {
@@ -24,3 +28,59 @@ func TestFindOrCreateRequestID_NewID(t *testing.T) {
 		t.Fatalf("expected non-empty request ID")
 	}
 }
+
+func TestFindOrCreateRequestID_InvalidID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"too long", strings.Repeat("a", 101)},
+		{"header injection", "id\r\nX-Admin-Token: secret"},
+		{"spaces", "my request"},
+		{"non-ascii", "запрос-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header["X-Request-Id"] = []string{tt.id}
+
+			requestID := findOrCreateRequestID(req)
+			if requestID == tt.id || !validRequestID.MatchString(requestID) {
+				t.Fatalf("expected a new request ID instead of %q, got %q", tt.id, requestID)
+			}
+		})
+	}
+}
+
+func TestFindOrCreateRequestID_MaxLength(t *testing.T) {
+	id := strings.Repeat("a", 100)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", id)
+
+	if requestID := findOrCreateRequestID(req); requestID != id {
+		t.Fatalf("expected %q, got %q", id, requestID)
+	}
+}
+
+func TestRequestsLogger_StoresRequestIDInContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestsLogger())
+
+	var requestID string
+	r.GET("/", func(c *gin.Context) {
+		requestID = utils.RequestIDFromContext(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "existing-id")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if requestID != "existing-id" {
+		t.Fatalf("expected 'existing-id' in context, got %q", requestID)
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+
+	// допустимое расхождение времени подписи с часами сервера
+	SignatureTolerance = 5 * time.Minute
+
+	// тело читается в память целиком до проверки подписи, поэтому его размер ограничен
+	MaxSignedBodySize = 1 << 20
+)
+
+// проверка подписи запроса: hex(HMAC-SHA256(timestamp + "." + body, key)) в заголовке X-Signature,
+// unix-время подписи в X-Signature-Timestamp; запросы со старой подписью отклоняются, чтобы
+// перехваченный запрос нельзя было повторить позже; если ключ не задан в конфигурации, эндпоинты недоступны
+func Signature(key entities.Secret) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if len(key) == 0 {
+			logging.LogInfoCtx(ctx, "signature: key is not configured")
+			c.Status(http.StatusForbidden)
+			c.Abort()
+			return
+		}
+
+		timestamp := c.Request.Header.Get(SignatureTimestampHeader)
+		if !validTimestamp(timestamp, time.Now()) {
+			logging.LogInfoCtx(ctx, "signature: invalid or expired timestamp")
+			c.Status(http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxSignedBodySize))
+		if err != nil {
+			var mbErr *http.MaxBytesError
+			if errors.As(err, &mbErr) {
+				logging.LogInfoCtx(ctx, "signature: body is too large")
+				c.Status(http.StatusRequestEntityTooLarge)
+				c.Abort()
+				return
+			}
+
+			logging.LogInfoCtx(ctx, "signature: error reading body")
+			c.Status(http.StatusBadRequest)
+			c.Abort()
+			return
+		}
+
+		// тело нужно обработчику, поэтому возвращаем его в запрос
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		signature, err := hex.DecodeString(c.Request.Header.Get(SignatureHeader))
+		if err != nil || !hmac.Equal(signature, Sign(timestamp, body, key)) {
+			logging.LogInfoCtx(ctx, "signature: invalid signature")
+			c.Status(http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func Sign(timestamp string, body []byte, key entities.Secret) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
+
+func validTimestamp(timestamp string, now time.Time) bool {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	diff := now.Sub(time.Unix(sec, 0))
+
+	return diff <= SignatureTolerance && diff >= -SignatureTolerance
+}
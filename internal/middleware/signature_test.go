@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureMiddleware(t *testing.T) {
+	body := `{"order":"12345678903","status":"PROCESSED","accrual":500}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-SignatureTolerance-time.Minute).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(SignatureTolerance+time.Minute).Unix(), 10)
+
+	sign := func(timestamp string) string {
+		return hex.EncodeToString(Sign(timestamp, []byte(body), entities.Secret("callback-secret")))
+	}
+
+	tests := []struct {
+		name      string
+		key       entities.Secret
+		timestamp string
+		signature string
+		expected  int
+	}{
+		{"not configured", entities.Secret(""), now, sign(now), http.StatusForbidden},
+		{"no signature", entities.Secret("callback-secret"), now, "", http.StatusUnauthorized},
+		{"not hex", entities.Secret("callback-secret"), now, "zzz", http.StatusUnauthorized},
+		{"wrong key", entities.Secret("other-secret"), now, sign(now), http.StatusUnauthorized},
+		{"no timestamp", entities.Secret("callback-secret"), "", sign(""), http.StatusUnauthorized},
+		{"timestamp not signed", entities.Secret("callback-secret"), now, sign(old), http.StatusUnauthorized},
+		{"expired timestamp", entities.Secret("callback-secret"), old, sign(old), http.StatusUnauthorized},
+		{"future timestamp", entities.Secret("callback-secret"), future, sign(future), http.StatusUnauthorized},
+		{"valid signature", entities.Secret("callback-secret"), now, sign(now), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+
+			r.Use(Signature(tt.key))
+			r.POST("/test", func(c *gin.Context) {
+				// обработчик получает тело целиком
+				got, _ := io.ReadAll(c.Request.Body)
+				c.String(http.StatusOK, string(got))
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+			if tt.timestamp != "" {
+				req.Header.Set(SignatureTimestampHeader, tt.timestamp)
+			}
+			if tt.signature != "" {
+				req.Header.Set(SignatureHeader, tt.signature)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusOK {
+				assert.Equal(t, body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSignatureMiddleware_BodyTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	called := false
+	r.Use(Signature(entities.Secret("callback-secret")))
+	r.POST("/test", func(c *gin.Context) { called = true })
+
+	body := strings.Repeat("a", MaxSignedBodySize+1)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+	req.Header.Set(SignatureTimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, hex.EncodeToString(Sign(timestamp, []byte(body), entities.Secret("callback-secret"))))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.False(t, called)
+}
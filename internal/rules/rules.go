@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidRules = errors.New("invalid accrual rules")
+
+var hundred = decimal.NewFromInt(100)
+
+// правило начисления: пустые product, store и category подходят к любому товару,
+// from и to ограничивают дату заказа (to не включается)
+type Rule struct {
+	Name     string          `yaml:"name"`
+	Product  string          `yaml:"product"`
+	Store    string          `yaml:"store"`
+	Category string          `yaml:"category"`
+	Percent  decimal.Decimal `yaml:"percent"` // процент от стоимости товара
+	Fixed    decimal.Decimal `yaml:"fixed"`   // баллы за единицу товара
+	Cap      decimal.Decimal `yaml:"cap"`     // максимум по правилу на заказ, 0 - без ограничений
+	From     time.Time       `yaml:"from"`
+	To       time.Time       `yaml:"to"`
+}
+
+// набор правил встроенного расчета начислений; к каждому товару
+// применяется первое подходящее правило
+type RuleSet struct {
+	Rules []Rule          `yaml:"rules"`
+	Cap   decimal.Decimal `yaml:"cap"` // максимум на заказ, 0 - без ограничений
+}
+
+// загружает правила из yaml-файла; пустой путь - правил нет
+func LoadRuleSet(path string) (*RuleSet, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("rules: error reading %s: %w", path, err)
+	}
+
+	return ParseRuleSet(data)
+}
+
+func ParseRuleSet(data []byte) (*RuleSet, error) {
+	rs := &RuleSet{}
+	if err := yaml.Unmarshal(data, rs); err != nil {
+		return nil, fmt.Errorf("rules: error parsing rules: %w", err)
+	}
+
+	if err := rs.validate(); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+// начисление за покупку; без правил начисление нулевое
+func (rs *RuleSet) Calculate(p *domain.Purchase) decimal.Decimal {
+	if rs == nil || p == nil {
+		return decimal.Zero
+	}
+
+	perRule := make([]decimal.Decimal, len(rs.Rules))
+	for _, item := range p.Items {
+		i := rs.match(p, item)
+		if i < 0 {
+			continue
+		}
+
+		perRule[i] = perRule[i].Add(rs.Rules[i].reward(item))
+	}
+
+	total := decimal.Zero
+	for i, sum := range perRule {
+		total = total.Add(capped(sum, rs.Rules[i].Cap))
+	}
+
+	return capped(total, rs.Cap).Round(2)
+}
+
+func (rs *RuleSet) match(p *domain.Purchase, item domain.PurchaseItem) int {
+	for i, r := range rs.Rules {
+		if r.matches(p, item) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (r Rule) matches(p *domain.Purchase, item domain.PurchaseItem) bool {
+	if r.Product != "" && r.Product != item.Product {
+		return false
+	}
+	if r.Store != "" && r.Store != p.Store {
+		return false
+	}
+	if r.Category != "" && r.Category != item.Category {
+		return false
+	}
+	if !r.From.IsZero() && p.CreatedAt.Before(r.From) {
+		return false
+	}
+	if !r.To.IsZero() && !p.CreatedAt.Before(r.To) {
+		return false
+	}
+
+	return true
+}
+
+func (r Rule) reward(item domain.PurchaseItem) decimal.Decimal {
+	reward := item.Amount().Mul(r.Percent).Div(hundred)
+	return reward.Add(r.Fixed.Mul(decimal.NewFromInt(int64(item.Quantity))))
+}
+
+func capped(amount, limit decimal.Decimal) decimal.Decimal {
+	if limit.IsPositive() && amount.GreaterThan(limit) {
+		return limit
+	}
+
+	return amount
+}
+
+func (rs *RuleSet) validate() error {
+	if rs.Cap.IsNegative() {
+		return fmt.Errorf("%w: cap is negative", ErrInvalidRules)
+	}
+
+	for i, r := range rs.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		if r.Percent.IsNegative() || r.Fixed.IsNegative() {
+			return fmt.Errorf("%w: rule %s reward is negative", ErrInvalidRules, name)
+		}
+		if !r.Percent.IsPositive() && !r.Fixed.IsPositive() {
+			return fmt.Errorf("%w: rule %s has no reward", ErrInvalidRules, name)
+		}
+		if r.Cap.IsNegative() {
+			return fmt.Errorf("%w: rule %s cap is negative", ErrInvalidRules, name)
+		}
+		if !r.From.IsZero() && !r.To.IsZero() && !r.To.After(r.From) {
+			return fmt.Errorf("%w: rule %s date range is empty", ErrInvalidRules, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRules = `
+cap: 1000
+rules:
+  - name: bork-kettle
+    product: bork-k800
+    fixed: 150
+  - name: electronics-promo
+    category: electronics
+    percent: 10
+    cap: 300
+    from: 2024-06-01
+    to: 2024-07-01
+  - name: downtown
+    store: downtown
+    percent: 2
+`
+
+func newPurchase(createdAt time.Time, store string, items ...domain.PurchaseItem) *domain.Purchase {
+	return &domain.Purchase{Store: store, Items: items, CreatedAt: createdAt}
+}
+
+func TestParseRuleSet(t *testing.T) {
+	rs, err := ParseRuleSet([]byte(testRules))
+	require.NoError(t, err)
+
+	assert.Len(t, rs.Rules, 3)
+	assert.True(t, decimal.NewFromInt(1000).Equal(rs.Cap))
+	assert.Equal(t, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), rs.Rules[1].From)
+}
+
+func TestParseRuleSet_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no reward", "rules: [{name: a, category: x}]"},
+		{"negative percent", "rules: [{name: a, percent: -1}]"},
+		{"negative cap", "rules: [{name: a, percent: 1, cap: -1}]"},
+		{"empty range", "rules: [{name: a, percent: 1, from: 2024-07-01, to: 2024-06-01}]"},
+		{"negative order cap", "cap: -1"},
+		{"malformed", "rules: {"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRuleSet([]byte(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRuleSet_Calculate(t *testing.T) {
+	rs, err := ParseRuleSet([]byte(testRules))
+	require.NoError(t, err)
+
+	inPromo := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+	afterPromo := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		purchase *domain.Purchase
+		expected string
+	}{
+		{
+			"fixed per unit",
+			newPurchase(inPromo, "", domain.PurchaseItem{Product: "bork-k800", Category: "electronics", Price: decimal.NewFromInt(7000), Quantity: 2}),
+			"300",
+		},
+		{
+			"percent within date range",
+			newPurchase(inPromo, "", domain.PurchaseItem{Product: "tv", Category: "electronics", Price: decimal.NewFromInt(1000), Quantity: 1}),
+			"100",
+		},
+		{
+			"rule cap",
+			newPurchase(inPromo, "", domain.PurchaseItem{Product: "tv", Category: "electronics", Price: decimal.NewFromInt(5000), Quantity: 1}),
+			"300",
+		},
+		{
+			"outside date range falls through to store rule",
+			newPurchase(afterPromo, "downtown", domain.PurchaseItem{Product: "tv", Category: "electronics", Price: decimal.NewFromInt(1000), Quantity: 1}),
+			"20",
+		},
+		{
+			"no matching rule",
+			newPurchase(afterPromo, "uptown", domain.PurchaseItem{Product: "tv", Category: "electronics", Price: decimal.NewFromInt(1000), Quantity: 1}),
+			"0",
+		},
+		{
+			"order cap",
+			newPurchase(inPromo, "downtown",
+				domain.PurchaseItem{Product: "bork-k800", Price: decimal.NewFromInt(7000), Quantity: 5},
+				domain.PurchaseItem{Product: "sofa", Category: "furniture", Price: decimal.NewFromInt(15000), Quantity: 1},
+			),
+			"1000",
+		},
+		{
+			"rounding",
+			newPurchase(afterPromo, "downtown", domain.PurchaseItem{Product: "pen", Price: decimal.RequireFromString("9.99"), Quantity: 1}),
+			"0.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rs.Calculate(tt.purchase).String())
+		})
+	}
+}
+
+func TestRuleSet_Calculate_NoRules(t *testing.T) {
+	var rs *RuleSet
+
+	purchase := newPurchase(time.Now(), "", domain.PurchaseItem{Price: decimal.NewFromInt(100), Quantity: 1})
+	assert.True(t, rs.Calculate(purchase).IsZero())
+}
+
+func TestLoadRuleSet(t *testing.T) {
+	rs, err := LoadRuleSet("")
+	assert.NoError(t, err)
+	assert.Nil(t, rs)
+
+	path := filepath.Join(t.TempDir(), "rules.yml")
+	require.NoError(t, os.WriteFile(path, []byte(testRules), 0600))
+
+	rs, err = LoadRuleSet(path)
+	require.NoError(t, err)
+	assert.Len(t, rs.Rules, 3)
+
+	_, err = LoadRuleSet(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
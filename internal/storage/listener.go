@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// подписывается на канал LISTEN/NOTIFY на выделенном соединении пула и вызывает handle
+// для каждого уведомления; возвращает ошибку при потере соединения или отмене ctx
+func Listen(ctx context.Context, pool IPGXPool, channel string, handle func(payload string)) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("storage -> Listen() acquire error: %w", err)
+	}
+	defer func() {
+		// соединение возвращается в пул без подписки; если оно разорвано, пул его закроет
+		_, _ = conn.Exec(context.Background(), "UNLISTEN *")
+		conn.Release()
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("storage -> Listen() error: %w", err)
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("storage -> Listen() wait error: %w", err)
+		}
+
+		handle(n.Payload)
+	}
+}
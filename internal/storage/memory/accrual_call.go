@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type accrualCallRepository struct {
+	storage *Storage
+}
+
+func NewAccrualCallRepository(s *Storage) repository.IAccrualCallRepository {
+	return &accrualCallRepository{storage: s}
+}
+
+func (repo *accrualCallRepository) AccrualCallCreate(ctx context.Context, call domain.AccrualCall) error {
+	defer repo.storage.lock(ctx)()
+
+	call.ID = int64(repo.storage.nextID())
+	call.CreatedAt = time.Now()
+	repo.storage.data.calls = append(repo.storage.data.calls, call)
+
+	return nil
+}
+
+// обращения по заказу в хронологическом порядке
+func (repo *accrualCallRepository) AccrualCallListByOrderNumber(ctx context.Context, number string) ([]*domain.AccrualCall, error) {
+	defer repo.storage.lock(ctx)()
+
+	calls := make([]*domain.AccrualCall, 0)
+	for _, call := range repo.storage.data.calls {
+		if call.OrderNumber == number {
+			calls = append(calls, &call)
+		}
+	}
+
+	return calls, nil
+}
+
+// удаляет обращения старше age, возвращает число удаленных
+func (repo *accrualCallRepository) AccrualCallDeleteOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	defer repo.storage.lock(ctx)()
+
+	threshold := time.Now().Add(-age)
+
+	before := len(repo.storage.data.calls)
+	repo.storage.data.calls = slices.DeleteFunc(repo.storage.data.calls, func(call domain.AccrualCall) bool {
+		return call.CreatedAt.Before(threshold)
+	})
+
+	return int64(before - len(repo.storage.data.calls)), nil
+}
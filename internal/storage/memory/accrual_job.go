@@ -0,0 +1,257 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type accrualJobRepository struct {
+	storage *Storage
+}
+
+func NewAccrualJobRepository(s *Storage) repository.IAccrualJobRepository {
+	return &accrualJobRepository{storage: s}
+}
+
+// захватывает в аренду пачку задач, время которых подошло;
+// задачи, захваченные другими экземплярами сервиса, пропускаются до истечения их аренды
+func (repo *accrualJobRepository) AccrualJobClaimDue(ctx context.Context, owner string, lease time.Duration, limit int) ([]*domain.AccrualJob, error) {
+	defer repo.storage.lock(ctx)()
+
+	now := time.Now()
+
+	due := make([]jobRow, 0)
+	for _, j := range repo.storage.data.jobs {
+		if j.claimable(now) {
+			due = append(due, j)
+		}
+	}
+
+	slices.SortFunc(due, func(a, b jobRow) int {
+		if c := a.NextAttemptAt.Compare(b.NextAttemptAt); c != 0 {
+			return c
+		}
+		return int(a.OrderID - b.OrderID)
+	})
+
+	jobs := make([]*domain.AccrualJob, 0, min(len(due), limit))
+	for _, j := range due[:min(len(due), limit)] {
+		jobs = append(jobs, repo.storage.claimJob(j, owner, now.Add(lease)))
+	}
+
+	return jobs, nil
+}
+
+// захватывает в аренду задачу по конкретному заказу, если ее время подошло и она свободна;
+// иначе ErrRecordNotFound
+func (repo *accrualJobRepository) AccrualJobClaim(ctx context.Context, owner string, lease time.Duration, orderID domain.OrderID) (*domain.AccrualJob, error) {
+	defer repo.storage.lock(ctx)()
+
+	now := time.Now()
+
+	j, ok := repo.storage.data.jobs[orderID]
+	if !ok || !j.claimable(now) {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	return repo.storage.claimJob(j, owner, now.Add(lease)), nil
+}
+
+// откладывает задачу на delay и снимает аренду;
+// если аренда уже перешла к другому экземпляру, задача не меняется
+func (repo *accrualJobRepository) AccrualJobReschedule(ctx context.Context, job domain.AccrualJob, delay time.Duration) error {
+	defer repo.storage.lock(ctx)()
+
+	j, ok := repo.storage.leasedJob(job)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	j.setResult(job)
+	j.NextAttemptAt = now.Add(delay)
+	j.release(now)
+	repo.storage.data.jobs[j.OrderID] = j
+
+	return nil
+}
+
+// переводит задачу в dead-letter, сохраняя последний ответ и ошибку
+func (repo *accrualJobRepository) AccrualJobMarkDead(ctx context.Context, job domain.AccrualJob) error {
+	defer repo.storage.lock(ctx)()
+
+	j, ok := repo.storage.leasedJob(job)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	j.setResult(job)
+	j.DeadAt = &now
+	j.release(now)
+	repo.storage.data.jobs[j.OrderID] = j
+
+	return nil
+}
+
+func (repo *accrualJobRepository) AccrualJobDelete(ctx context.Context, orderID domain.OrderID) error {
+	defer repo.storage.lock(ctx)()
+
+	delete(repo.storage.data.jobs, orderID)
+
+	return nil
+}
+
+// после обработки заказа задача остается до конца окна исправлений и опрашивается раз в delay
+func (repo *accrualJobRepository) AccrualJobStartCorrection(ctx context.Context, orderID domain.OrderID, window time.Duration, delay time.Duration) error {
+	defer repo.storage.lock(ctx)()
+
+	j, ok := repo.storage.data.jobs[orderID]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	until := now.Add(window)
+	j.CorrectUntil = &until
+	j.NextAttemptAt = now.Add(delay)
+	j.setResult(domain.AccrualJob{})
+	j.release(now)
+	repo.storage.data.jobs[orderID] = j
+
+	return nil
+}
+
+func (repo *accrualJobRepository) AccrualJobFindByOrderNumber(ctx context.Context, number string) (*domain.AccrualJob, error) {
+	defer repo.storage.lock(ctx)()
+
+	o, ok := repo.storage.findOrderByNumber(number)
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	j, ok := repo.storage.data.jobs[o.ID]
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	return repo.storage.jobWithOrder(j), nil
+}
+
+func (repo *accrualJobRepository) AccrualJobListDead(ctx context.Context) ([]*domain.AccrualJob, error) {
+	defer repo.storage.lock(ctx)()
+
+	jobs := make([]*domain.AccrualJob, 0)
+	for _, j := range repo.storage.data.jobs {
+		if j.IsDead() {
+			jobs = append(jobs, repo.storage.jobWithOrder(j))
+		}
+	}
+
+	slices.SortFunc(jobs, func(a, b *domain.AccrualJob) int {
+		if c := b.DeadAt.Compare(*a.DeadAt); c != 0 {
+			return c
+		}
+		return int(b.OrderID - a.OrderID)
+	})
+
+	return jobs, nil
+}
+
+// возвращает задачу из dead-letter в очередь с немедленной попыткой
+func (repo *accrualJobRepository) AccrualJobRequeue(ctx context.Context, orderID domain.OrderID) error {
+	defer repo.storage.lock(ctx)()
+
+	j, ok := repo.storage.data.jobs[orderID]
+	if !ok || !j.IsDead() {
+		return storage.ErrRecordNotFound
+	}
+
+	now := time.Now()
+	j.DeadAt = nil
+	j.Attempts = 0
+	j.Failures = 0
+	j.NextAttemptAt = now
+	j.UpdatedAt = now
+	repo.storage.data.jobs[orderID] = j
+
+	return nil
+}
+
+// снимает аренду задач экземпляра по номерам заказов, время следующей попытки не меняется
+func (repo *accrualJobRepository) AccrualJobReleaseLeases(ctx context.Context, owner string, numbers []string) error {
+	defer repo.storage.lock(ctx)()
+
+	now := time.Now()
+	for id, j := range repo.storage.data.jobs {
+		if j.LeaseOwner != owner || !slices.Contains(numbers, repo.storage.data.orders[id].Number) {
+			continue
+		}
+
+		j.release(now)
+		repo.storage.data.jobs[id] = j
+	}
+
+	return nil
+}
+
+// задача свободна, ее время подошло и она не в dead-letter
+func (j jobRow) claimable(now time.Time) bool {
+	return !j.NextAttemptAt.After(now) && !j.IsDead() && (j.LeaseExpiresAt == nil || j.LeaseExpiresAt.Before(now))
+}
+
+func (j *jobRow) setResult(job domain.AccrualJob) {
+	j.Attempts = job.Attempts
+	j.Failures = job.Failures
+	j.LastError = job.LastError
+	j.LastHTTPStatus = job.LastHTTPStatus
+	j.LastResponse = job.LastResponse
+}
+
+func (j *jobRow) release(now time.Time) {
+	j.LeaseOwner = ""
+	j.LeaseExpiresAt = nil
+	j.UpdatedAt = now
+}
+
+// задача, аренда которой принадлежит тому же экземпляру, что и у job
+func (s *Storage) leasedJob(job domain.AccrualJob) (jobRow, bool) {
+	j, ok := s.data.jobs[job.OrderID]
+	if !ok || j.LeaseOwner == "" || j.LeaseOwner != job.LeaseOwner {
+		return jobRow{}, false
+	}
+
+	return j, true
+}
+
+func (s *Storage) claimJob(j jobRow, owner string, expiresAt time.Time) *domain.AccrualJob {
+	j.LeaseOwner = owner
+	j.LeaseExpiresAt = &expiresAt
+	s.data.jobs[j.OrderID] = j
+
+	return s.jobWithOrder(j)
+}
+
+func (s *Storage) jobWithOrder(j jobRow) *domain.AccrualJob {
+	o := s.data.orders[j.OrderID]
+
+	job := j.AccrualJob
+	job.Order = &domain.Order{
+		ID:        o.ID,
+		UserID:    o.UserID,
+		Number:    o.Number,
+		Status:    o.Status,
+		Engine:    o.Engine,
+		CreatedAt: o.CreatedAt,
+	}
+	if o.Purchase != nil {
+		job.Order.Store = o.Purchase.Store
+	}
+
+	return &job
+}
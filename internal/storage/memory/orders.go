@@ -0,0 +1,296 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type orderRepository struct {
+	storage *Storage
+}
+
+func NewOrderRepository(s *Storage) repository.IOrderRepository {
+	return &orderRepository{storage: s}
+}
+
+// вместе с заказом создается задача опроса системы начислений и сохраняются данные о покупке
+func (repo *orderRepository) OrderCreate(ctx context.Context, order domain.Order) (*domain.Order, error) {
+	defer repo.storage.lock(ctx)()
+
+	if _, ok := repo.storage.findOrderByNumber(order.Number); ok {
+		return nil, fmt.Errorf("orderRepository -> OrderCreate() error: %w", storage.ErrRecordExists)
+	}
+
+	now := time.Now()
+	row := domain.Order{
+		ID:        domain.OrderID(repo.storage.nextID()),
+		UserID:    order.UserID,
+		Number:    order.Number,
+		Status:    order.Status,
+		RequestID: order.RequestID,
+		Engine:    order.Engine,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if order.Purchase != nil {
+		row.Purchase = &domain.Purchase{Store: order.Purchase.Store, Items: slices.Clone(order.Purchase.Items)}
+	}
+	repo.storage.data.orders[row.ID] = row
+
+	repo.storage.data.jobs[row.ID] = jobRow{
+		AccrualJob: domain.AccrualJob{
+			OrderID:       row.ID,
+			NextAttemptAt: now,
+			RequestID:     order.RequestID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		},
+	}
+
+	return &domain.Order{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Number:    row.Number,
+		Status:    row.Status,
+		Accrual:   row.Accrual,
+		Engine:    order.Engine,
+		Purchase:  order.Purchase,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+func (repo *orderRepository) OrderList(ctx context.Context, userID domain.UserID) ([]*domain.Order, error) {
+	defer repo.storage.lock(ctx)()
+
+	orders := make([]*domain.Order, 0)
+	for _, o := range repo.storage.newestOrders() {
+		if o.UserID != userID {
+			continue
+		}
+
+		// начисление показывается с учетом корректировок
+		orders = append(orders, &domain.Order{
+			Number:    o.Number,
+			Status:    o.Status,
+			Accrual:   o.Accrual.Add(repo.storage.adjustmentSum(o.ID)),
+			CreatedAt: o.CreatedAt,
+		})
+	}
+
+	return orders, nil
+}
+
+func (repo *orderRepository) OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error) {
+	defer repo.storage.lock(ctx)()
+
+	o, ok := repo.storage.findOrderByNumber(number)
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	return &domain.Order{
+		ID:            o.ID,
+		UserID:        o.UserID,
+		Number:        o.Number,
+		Status:        o.Status,
+		Accrual:       o.Accrual,
+		Engine:        o.Engine,
+		ReviewAccrual: o.ReviewAccrual,
+		ReviewReason:  o.ReviewReason,
+		CreatedAt:     o.CreatedAt,
+		UpdatedAt:     o.UpdatedAt,
+	}, nil
+}
+
+// данные о покупке для встроенного расчета начислений
+func (repo *orderRepository) OrderPurchaseFindByNumber(ctx context.Context, number string) (*domain.Purchase, error) {
+	defer repo.storage.lock(ctx)()
+
+	o, ok := repo.storage.findOrderByNumber(number)
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	purchase := &domain.Purchase{Items: make([]domain.PurchaseItem, 0), CreatedAt: o.CreatedAt}
+	if o.Purchase != nil {
+		purchase.Store = o.Purchase.Store
+		purchase.Items = append(purchase.Items, o.Purchase.Items...)
+	}
+
+	return purchase, nil
+}
+
+func (repo *orderRepository) OrderUpdate(ctx context.Context, order domain.Order) error {
+	defer repo.storage.lock(ctx)()
+
+	o, ok := repo.storage.data.orders[order.ID]
+	if !ok {
+		return nil
+	}
+
+	o.Status = order.Status
+	o.Accrual = order.Accrual
+	o.BaseAccrual = order.BaseAccrual
+	o.UpdatedAt = time.Now()
+	repo.storage.data.orders[o.ID] = o
+
+	return nil
+}
+
+// откладывает начисление до решения администратора
+func (repo *orderRepository) OrderMarkForReview(ctx context.Context, order domain.Order) error {
+	defer repo.storage.lock(ctx)()
+
+	o, ok := repo.storage.data.orders[order.ID]
+	if !ok {
+		return nil
+	}
+
+	o.Status = domain.OrderStatusNeedsReview
+	o.ReviewAccrual = order.ReviewAccrual
+	o.ReviewReason = order.ReviewReason
+	o.UpdatedAt = time.Now()
+	repo.storage.data.orders[o.ID] = o
+
+	return nil
+}
+
+// заказы, ждущие ручной проверки, начиная с самых давних
+func (repo *orderRepository) OrderListForReview(ctx context.Context) ([]*domain.Order, error) {
+	defer repo.storage.lock(ctx)()
+
+	orders := make([]*domain.Order, 0)
+	for _, o := range repo.storage.data.orders {
+		if !o.AwaitsReview() {
+			continue
+		}
+
+		orders = append(orders, &domain.Order{
+			ID:            o.ID,
+			UserID:        o.UserID,
+			Number:        o.Number,
+			Status:        o.Status,
+			ReviewAccrual: o.ReviewAccrual,
+			ReviewReason:  o.ReviewReason,
+			CreatedAt:     o.CreatedAt,
+			UpdatedAt:     o.UpdatedAt,
+		})
+	}
+
+	slices.SortFunc(orders, func(a, b *domain.Order) int {
+		if c := a.UpdatedAt.Compare(b.UpdatedAt); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+
+	return orders, nil
+}
+
+// сумма начислений системы без множителя уровня по обработанным заказам пользователя начиная с since
+func (repo *orderRepository) OrderAccrualSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	defer repo.storage.lock(ctx)()
+
+	sum := decimal.Zero
+	for _, o := range repo.storage.data.orders {
+		if o.UserID == userID && o.Status == domain.OrderStatusProcessed && !o.UpdatedAt.Before(since) {
+			sum = sum.Add(o.BaseAccrual)
+		}
+	}
+
+	// корректировка меняет начисление системы на разницу с предыдущим значением
+	prev := make(map[domain.OrderID]decimal.Decimal)
+	for _, a := range repo.storage.data.adjustments {
+		if a.UserID != userID {
+			continue
+		}
+
+		base, ok := prev[a.OrderID]
+		if !ok {
+			base = repo.storage.data.orders[a.OrderID].BaseAccrual
+		}
+		prev[a.OrderID] = a.BaseAccrual
+
+		if !a.CreatedAt.Before(since) {
+			sum = sum.Add(a.BaseAccrual.Sub(base))
+		}
+	}
+
+	return sum, nil
+}
+
+// начисление по заказу с учетом корректировок и последнее начисление системы до применения множителя уровня
+func (repo *orderRepository) OrderAccrualCurrent(ctx context.Context, id domain.OrderID) (decimal.Decimal, decimal.Decimal, error) {
+	defer repo.storage.lock(ctx)()
+
+	o, ok := repo.storage.data.orders[id]
+	if !ok {
+		return decimal.Zero, decimal.Zero, storage.ErrRecordNotFound
+	}
+
+	base := o.BaseAccrual
+	for _, a := range repo.storage.data.adjustments {
+		if a.OrderID == id {
+			base = a.BaseAccrual
+		}
+	}
+
+	return o.Accrual.Add(repo.storage.adjustmentSum(id)), base, nil
+}
+
+// проводка исправления начисления; само начисление по заказу не меняется
+func (repo *orderRepository) OrderAdjustmentCreate(ctx context.Context, a domain.OrderAdjustment) error {
+	defer repo.storage.lock(ctx)()
+
+	a.ID = repo.storage.nextID()
+	a.CreatedAt = time.Now()
+	repo.storage.data.adjustments = append(repo.storage.data.adjustments, a)
+
+	return nil
+}
+
+func (s *Storage) findOrderByNumber(number string) (domain.Order, bool) {
+	for _, o := range s.data.orders {
+		if o.Number == number {
+			return o, true
+		}
+	}
+
+	return domain.Order{}, false
+}
+
+// заказы от новых к старым
+func (s *Storage) newestOrders() []domain.Order {
+	orders := make([]domain.Order, 0, len(s.data.orders))
+	for _, o := range s.data.orders {
+		orders = append(orders, o)
+	}
+
+	slices.SortFunc(orders, func(a, b domain.Order) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return int(b.ID - a.ID)
+	})
+
+	return orders
+}
+
+func (s *Storage) adjustmentSum(orderID domain.OrderID) decimal.Decimal {
+	sum := decimal.Zero
+	for _, a := range s.data.adjustments {
+		if a.OrderID == orderID {
+			sum = sum.Add(a.Amount)
+		}
+	}
+
+	return sum
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type statementRepository struct {
+	storage *Storage
+}
+
+func NewStatementRepository(s *Storage) repository.IStatementRepository {
+	return &statementRepository{storage: s}
+}
+
+func (repo *statementRepository) StatementFind(ctx context.Context, userID domain.UserID, period time.Time) (*domain.Statement, error) {
+	defer repo.storage.lock(ctx)()
+
+	s, ok := repo.storage.findStatement(userID, period)
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	return &s, nil
+}
+
+// сохраняет выписку; уже существующая выписка за период не перезаписывается
+func (repo *statementRepository) StatementCreate(ctx context.Context, s domain.Statement) error {
+	defer repo.storage.lock(ctx)()
+
+	if _, ok := repo.storage.findStatement(s.UserID, s.Period); ok {
+		return nil
+	}
+
+	s.ID = domain.StatementID(repo.storage.nextID())
+	s.CreatedAt = time.Now()
+	repo.storage.data.statements = append(repo.storage.data.statements, s)
+
+	return nil
+}
+
+// обороты пользователя за полуинтервал [from, to); заполняются только суммы операций,
+// начисление относится к моменту обработки заказа, корректировка - к моменту проводки
+func (repo *statementRepository) StatementTurnover(ctx context.Context, userID domain.UserID, from time.Time, to time.Time) (*domain.Statement, error) {
+	defer repo.storage.lock(ctx)()
+
+	data := repo.storage.data
+	within := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	s := &domain.Statement{UserID: userID}
+
+	for _, o := range data.orders {
+		if o.UserID == userID && o.Status == domain.OrderStatusProcessed && within(o.UpdatedAt) {
+			s.Accruals = s.Accruals.Add(o.Accrual)
+		}
+	}
+
+	for _, a := range data.adjustments {
+		if a.UserID == userID && within(a.CreatedAt) {
+			s.Accruals = s.Accruals.Add(a.Amount)
+		}
+	}
+
+	for _, w := range data.withdrawals {
+		if w.UserID == userID && within(w.CreatedAt) {
+			s.Withdrawals = s.Withdrawals.Add(w.Amount)
+		}
+	}
+
+	for _, r := range data.refunds {
+		if r.UserID == userID && within(r.CreatedAt) {
+			s.Refunds = s.Refunds.Add(r.Amount)
+		}
+	}
+
+	for _, t := range data.transfers {
+		if !within(t.CreatedAt) {
+			continue
+		}
+		if t.RecipientID == userID {
+			s.TransfersIn = s.TransfersIn.Add(t.Amount)
+		}
+		if t.SenderID == userID {
+			s.TransfersOut = s.TransfersOut.Add(t.Amount)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Storage) findStatement(userID domain.UserID, period time.Time) (domain.Statement, bool) {
+	for _, st := range s.data.statements {
+		if st.UserID == userID && st.Period.Equal(period) {
+			return st, true
+		}
+	}
+
+	return domain.Statement{}, false
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+var _ storage.IStorage = (*Storage)(nil)
+
+// хранилище в памяти процесса для тестов и демонстрации; данные теряются при остановке.
+// транзакции выполняются по одной, что соответствует блокировкам строк в PostgreSQL
+type Storage struct {
+	mu   sync.Mutex
+	data *tables
+}
+
+type txKey struct{}
+
+type userRow struct {
+	domain.User
+	Withdrawn decimal.Decimal
+}
+
+type jobRow struct {
+	domain.AccrualJob
+	LeaseExpiresAt *time.Time
+}
+
+type tables struct {
+	seq int32
+
+	users       map[domain.UserID]userRow
+	orders      map[domain.OrderID]domain.Order
+	adjustments []domain.OrderAdjustment
+	withdrawals map[domain.WithdrawalID]domain.Withdrawal
+	refunds     []domain.WithdrawalRefund
+	transfers   []domain.Transfer
+	statements  []domain.Statement
+	jobs        map[domain.OrderID]jobRow
+	calls       []domain.AccrualCall
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		data: &tables{
+			users:       make(map[domain.UserID]userRow),
+			orders:      make(map[domain.OrderID]domain.Order),
+			withdrawals: make(map[domain.WithdrawalID]domain.Withdrawal),
+			jobs:        make(map[domain.OrderID]jobRow),
+		},
+	}
+}
+
+// репозитории, работающие с данными хранилища
+func NewRepositories(s *Storage) *repository.Repositories {
+	return &repository.Repositories{
+		User:        NewUserRepository(s),
+		Order:       NewOrderRepository(s),
+		Withdrawal:  NewWithdrawalRepository(s),
+		Transfer:    NewTransferRepository(s),
+		Statement:   NewStatementRepository(s),
+		AccrualJob:  NewAccrualJobRepository(s),
+		AccrualCall: NewAccrualCallRepository(s),
+	}
+}
+
+// fn выполняется под блокировкой всего хранилища; при ошибке или панике изменения
+// откатываются к снимку, сделанному перед началом. вложенный вызов выполняется в той же транзакции
+func (s *Storage) WithinTx(ctx context.Context, _ storage.TxOptions, fn func(ctx context.Context) error) error {
+	if s.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.data.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			s.data = snapshot
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, s)); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
+
+func (s *Storage) Close() {}
+
+func (s *Storage) inTx(ctx context.Context) bool {
+	tx, _ := ctx.Value(txKey{}).(*Storage)
+	return tx == s
+}
+
+// вне транзакции каждый вызов репозитория блокирует хранилище на время выполнения
+func (s *Storage) lock(ctx context.Context) func() {
+	if s.inTx(ctx) {
+		return func() {}
+	}
+
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (s *Storage) nextID() int32 {
+	s.data.seq++
+	return s.data.seq
+}
+
+// строки хранятся по значению, поэтому для снимка достаточно копий контейнеров
+func (t *tables) clone() *tables {
+	return &tables{
+		seq:         t.seq,
+		users:       maps.Clone(t.users),
+		orders:      maps.Clone(t.orders),
+		adjustments: slices.Clone(t.adjustments),
+		withdrawals: maps.Clone(t.withdrawals),
+		refunds:     slices.Clone(t.refunds),
+		transfers:   slices.Clone(t.transfers),
+		statements:  slices.Clone(t.statements),
+		jobs:        maps.Clone(t.jobs),
+		calls:       slices.Clone(t.calls),
+	}
+}
@@ -0,0 +1,76 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/memory"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/storage/repository/repotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStorage(_ *testing.T) (storage.IStorage, *repository.Repositories) {
+	s := memory.NewStorage()
+	return s, memory.NewRepositories(s)
+}
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, newStorage)
+}
+
+func TestDuplicatesReturnErrRecordExists(t *testing.T) {
+	ctx := context.Background()
+	_, repos := newStorage(t)
+
+	user, err := repos.User.UserCreate(ctx, "me", "hash")
+	require.NoError(t, err)
+
+	_, err = repos.User.UserCreate(ctx, "me", "hash")
+	assert.ErrorIs(t, err, storage.ErrRecordExists)
+
+	_, err = repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903"})
+	require.NoError(t, err)
+
+	_, err = repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903"})
+	assert.ErrorIs(t, err, storage.ErrRecordExists)
+
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "9278923470"}))
+	assert.ErrorIs(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "9278923470"}), storage.ErrRecordExists)
+}
+
+func TestWithinTxRollbackOnPanic(t *testing.T) {
+	ctx := context.Background()
+	s, repos := newStorage(t)
+
+	assert.Panics(t, func() {
+		_ = s.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+			if _, err := repos.User.UserCreate(ctx, "me", "hash"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	})
+
+	_, err := repos.User.UserFindByLogin(ctx, "me")
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+}
+
+func TestWithinTxNested(t *testing.T) {
+	ctx := context.Background()
+	s, repos := newStorage(t)
+
+	err := s.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		return s.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+			_, err := repos.User.UserCreate(ctx, "me", "hash")
+			return err
+		})
+	})
+	require.NoError(t, err)
+
+	_, err = repos.User.UserFindByLogin(ctx, "me")
+	assert.NoError(t, err)
+}
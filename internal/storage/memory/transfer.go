@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type transferRepository struct {
+	storage *Storage
+}
+
+func NewTransferRepository(s *Storage) repository.ITransferRepository {
+	return &transferRepository{storage: s}
+}
+
+func (repo *transferRepository) TransferCreate(ctx context.Context, t domain.Transfer) error {
+	defer repo.storage.lock(ctx)()
+
+	if !t.Amount.IsPositive() {
+		return fmt.Errorf("transferRepository -> TransferCreate() error: amount must be positive")
+	}
+
+	repo.storage.data.transfers = append(repo.storage.data.transfers, domain.Transfer{
+		ID:          domain.TransferID(repo.storage.nextID()),
+		SenderID:    t.SenderID,
+		RecipientID: t.RecipientID,
+		Amount:      t.Amount,
+		CreatedAt:   time.Now(),
+	})
+
+	return nil
+}
+
+// входящие и исходящие переводы пользователя
+func (repo *transferRepository) TransferList(ctx context.Context, userID domain.UserID) ([]*domain.Transfer, error) {
+	defer repo.storage.lock(ctx)()
+
+	transfers := make([]*domain.Transfer, 0)
+	for _, t := range repo.storage.data.transfers {
+		if t.SenderID != userID && t.RecipientID != userID {
+			continue
+		}
+
+		t.SenderLogin = repo.storage.data.users[t.SenderID].Login
+		t.RecipientLogin = repo.storage.data.users[t.RecipientID].Login
+		transfers = append(transfers, &t)
+	}
+
+	slices.SortFunc(transfers, func(a, b *domain.Transfer) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return int(b.ID - a.ID)
+	})
+
+	return transfers, nil
+}
+
+// сумма и количество исходящих переводов пользователя начиная с since
+func (repo *transferRepository) TransferStatsSince(ctx context.Context, senderID domain.UserID, since time.Time) (decimal.Decimal, int, error) {
+	defer repo.storage.lock(ctx)()
+
+	sum, count := decimal.Zero, 0
+	for _, t := range repo.storage.data.transfers {
+		if t.SenderID == senderID && !t.CreatedAt.Before(since) {
+			sum = sum.Add(t.Amount)
+			count++
+		}
+	}
+
+	return sum, count, nil
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type userRepository struct {
+	storage *Storage
+}
+
+func NewUserRepository(s *Storage) repository.IUserRepository {
+	return &userRepository{storage: s}
+}
+
+func (repo *userRepository) UserCreate(ctx context.Context, login string, password string) (*domain.User, error) {
+	defer repo.storage.lock(ctx)()
+
+	if _, ok := repo.storage.findUserByLogin(login); ok {
+		return nil, fmt.Errorf("userRepository -> UserCreate() error: %w", storage.ErrRecordExists)
+	}
+
+	now := time.Now()
+	row := userRow{
+		User: domain.User{
+			ID:        domain.UserID(repo.storage.nextID()),
+			Login:     login,
+			Password:  password,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+	repo.storage.data.users[row.ID] = row
+
+	return &domain.User{ID: row.ID, Login: row.Login, Balance: row.Balance, CreatedAt: row.CreatedAt, UpdatedAt: row.UpdatedAt}, nil
+}
+
+func (repo *userRepository) UserFindByLogin(ctx context.Context, login string) (*domain.User, error) {
+	defer repo.storage.lock(ctx)()
+
+	row, ok := repo.storage.findUserByLogin(login)
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	user := row.User
+	return &user, nil
+}
+
+func (repo *userRepository) UserGetBalance(ctx context.Context, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error) {
+	defer repo.storage.lock(ctx)()
+
+	row, ok := repo.storage.data.users[id]
+	if !ok {
+		return nil, nil, storage.ErrRecordNotFound
+	}
+
+	b, w := row.Balance, row.Withdrawn
+	return &b, &w, nil
+}
+
+// баланс пересчитывается по всем операциям пользователя, как и в PostgreSQL
+func (repo *userRepository) UserUpdateBalanceAndWithdrawals(ctx context.Context, id domain.UserID) error {
+	defer repo.storage.lock(ctx)()
+
+	row, ok := repo.storage.data.users[id]
+	if !ok {
+		return nil
+	}
+
+	data := repo.storage.data
+	balance, withdrawn := decimal.Zero, decimal.Zero
+
+	for _, o := range data.orders {
+		if o.UserID == id && o.Status == domain.OrderStatusProcessed {
+			balance = balance.Add(o.Accrual)
+		}
+	}
+
+	for _, a := range data.adjustments {
+		if a.UserID == id {
+			balance = balance.Add(a.Amount)
+		}
+	}
+
+	for _, w := range data.withdrawals {
+		if w.UserID == id {
+			withdrawn = withdrawn.Add(w.Refundable())
+		}
+	}
+
+	for _, t := range data.transfers {
+		if t.SenderID == id {
+			balance = balance.Sub(t.Amount)
+		}
+		if t.RecipientID == id {
+			balance = balance.Add(t.Amount)
+		}
+	}
+
+	row.Balance = balance.Sub(withdrawn)
+	row.Withdrawn = withdrawn
+	data.users[id] = row
+
+	return nil
+}
+
+func (repo *userRepository) UserGetTier(ctx context.Context, id domain.UserID) (domain.LoyaltyTier, error) {
+	defer repo.storage.lock(ctx)()
+
+	row, ok := repo.storage.data.users[id]
+	if !ok {
+		return "", storage.ErrRecordNotFound
+	}
+
+	return row.Tier, nil
+}
+
+func (repo *userRepository) UserUpdateTier(ctx context.Context, id domain.UserID, tier domain.LoyaltyTier) error {
+	defer repo.storage.lock(ctx)()
+
+	row, ok := repo.storage.data.users[id]
+	if !ok {
+		return nil
+	}
+
+	row.Tier = tier
+	row.UpdatedAt = time.Now()
+	repo.storage.data.users[id] = row
+
+	return nil
+}
+
+// вместе с паролем запоминается время смены, от которого отсчитывается запрет списаний
+func (repo *userRepository) UserUpdatePassword(ctx context.Context, id domain.UserID, password string) error {
+	defer repo.storage.lock(ctx)()
+
+	row, ok := repo.storage.data.users[id]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	row.Password = password
+	row.PasswordChangedAt = &now
+	row.UpdatedAt = now
+	repo.storage.data.users[id] = row
+
+	return nil
+}
+
+func (s *Storage) findUserByLogin(login string) (userRow, bool) {
+	for _, row := range s.data.users {
+		if row.Login == login {
+			return row, true
+		}
+	}
+
+	return userRow{}, false
+}
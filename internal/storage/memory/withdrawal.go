@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type withdrawalRepository struct {
+	storage *Storage
+}
+
+func NewWithdrawalRepository(s *Storage) repository.IWithdrawalRepository {
+	return &withdrawalRepository{storage: s}
+}
+
+func (repo *withdrawalRepository) WithdrawalCreate(ctx context.Context, w domain.Withdrawal) error {
+	defer repo.storage.lock(ctx)()
+
+	if _, ok := repo.storage.findWithdrawalByOrderNumber(w.OrderNumber); ok {
+		return fmt.Errorf("withdrawalRepository -> WithdrawalCreate() error: %w", storage.ErrRecordExists)
+	}
+
+	row := domain.Withdrawal{
+		ID:          domain.WithdrawalID(repo.storage.nextID()),
+		UserID:      w.UserID,
+		OrderNumber: w.OrderNumber,
+		Amount:      w.Amount,
+		Status:      domain.WithdrawalStatusWithdrawn,
+		CreatedAt:   time.Now(),
+	}
+	repo.storage.data.withdrawals[row.ID] = row
+
+	return nil
+}
+
+func (repo *withdrawalRepository) WithdrawalFindByOrderNumber(ctx context.Context, number string) (*domain.Withdrawal, error) {
+	defer repo.storage.lock(ctx)()
+
+	w, ok := repo.storage.findWithdrawalByOrderNumber(number)
+	if !ok {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	return &w, nil
+}
+
+func (repo *withdrawalRepository) WithdrawalList(ctx context.Context, userID domain.UserID) ([]*domain.Withdrawal, error) {
+	defer repo.storage.lock(ctx)()
+
+	wds := make([]*domain.Withdrawal, 0)
+	for _, w := range repo.storage.data.withdrawals {
+		if w.UserID != userID {
+			continue
+		}
+
+		wds = append(wds, &domain.Withdrawal{
+			ID:          w.ID,
+			OrderNumber: w.OrderNumber,
+			Amount:      w.Amount,
+			Refunded:    w.Refunded,
+			Status:      w.Status,
+			CreatedAt:   w.CreatedAt,
+		})
+	}
+
+	slices.SortFunc(wds, func(a, b *domain.Withdrawal) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return int(b.ID - a.ID)
+	})
+
+	return wds, nil
+}
+
+func (repo *withdrawalRepository) WithdrawalUpdate(ctx context.Context, w domain.Withdrawal) error {
+	defer repo.storage.lock(ctx)()
+
+	row, ok := repo.storage.data.withdrawals[w.ID]
+	if !ok {
+		return nil
+	}
+
+	row.Refunded = w.Refunded
+	row.Status = w.Status
+	repo.storage.data.withdrawals[w.ID] = row
+
+	return nil
+}
+
+func (repo *withdrawalRepository) WithdrawalRefundCreate(ctx context.Context, r domain.WithdrawalRefund) error {
+	defer repo.storage.lock(ctx)()
+
+	r.ID = domain.WithdrawalRefundID(repo.storage.nextID())
+	r.CreatedAt = time.Now()
+	repo.storage.data.refunds = append(repo.storage.data.refunds, r)
+
+	return nil
+}
+
+// сумма списаний пользователя начиная с since за вычетом возвратов, как и в балансе
+func (repo *withdrawalRepository) WithdrawalSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	defer repo.storage.lock(ctx)()
+
+	sum := decimal.Zero
+	for _, w := range repo.storage.data.withdrawals {
+		if w.UserID == userID && !w.CreatedAt.Before(since) {
+			sum = sum.Add(w.Amount.Sub(w.Refunded))
+		}
+	}
+
+	return sum, nil
+}
+
+func (s *Storage) findWithdrawalByOrderNumber(number string) (domain.Withdrawal, bool) {
+	for _, w := range s.data.withdrawals {
+		if w.OrderNumber == number {
+			return w, true
+		}
+	}
+
+	return domain.Withdrawal{}, false
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS withdrawal_refunds;
+
+ALTER TABLE withdrawals
+    DROP COLUMN IF EXISTS refunded,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS withdrawal_status;
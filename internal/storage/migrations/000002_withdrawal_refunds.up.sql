@@ -0,0 +1,16 @@
+CREATE TYPE withdrawal_status AS ENUM ('WITHDRAWN', 'PARTIALLY_REFUNDED', 'REFUNDED');
+
+ALTER TABLE withdrawals
+    ADD COLUMN IF NOT EXISTS status withdrawal_status NOT NULL DEFAULT 'WITHDRAWN',
+    ADD COLUMN IF NOT EXISTS refunded DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+CREATE TABLE
+    IF NOT EXISTS withdrawal_refunds (
+        id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+        withdrawal_id INTEGER NOT NULL,
+        user_id INTEGER NOT NULL,
+        amount DECIMAL(10, 2) NOT NULL,
+        created_at TIMESTAMP DEFAULT now () NOT NULL,
+        CONSTRAINT withdrawal_refunds_fk_withdrawals foreign key (withdrawal_id) REFERENCES withdrawals (id),
+        CONSTRAINT withdrawal_refunds_fk_users foreign key (user_id) REFERENCES users (id)
+    );
@@ -0,0 +1,15 @@
+CREATE TABLE
+    IF NOT EXISTS transfers (
+        id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+        sender_id INTEGER NOT NULL,
+        recipient_id INTEGER NOT NULL,
+        amount DECIMAL(10, 2) NOT NULL,
+        created_at TIMESTAMP DEFAULT now () NOT NULL,
+        CONSTRAINT transfers_fk_sender foreign key (sender_id) REFERENCES users (id),
+        CONSTRAINT transfers_fk_recipient foreign key (recipient_id) REFERENCES users (id),
+        CONSTRAINT transfers_amount_positive CHECK (amount > 0)
+    );
+
+CREATE INDEX IF NOT EXISTS transfers_sender_created_at_idx ON transfers (sender_id, created_at);
+
+CREATE INDEX IF NOT EXISTS transfers_recipient_created_at_idx ON transfers (recipient_id, created_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS orders_user_id_status_updated_at_idx;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS base_accrual;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS tier;
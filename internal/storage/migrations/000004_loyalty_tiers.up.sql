@@ -0,0 +1,9 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS tier VARCHAR(32) NOT NULL DEFAULT '';
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS base_accrual DECIMAL(10, 2) DEFAULT 0;
+
+UPDATE orders SET base_accrual = accrual;
+
+CREATE INDEX IF NOT EXISTS orders_user_id_status_updated_at_idx ON orders (user_id, status, updated_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS withdrawals_user_id_created_at_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS password_changed_at;
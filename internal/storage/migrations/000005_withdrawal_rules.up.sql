@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS withdrawals_user_id_created_at_idx ON withdrawals (user_id, created_at);
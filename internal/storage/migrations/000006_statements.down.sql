@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS withdrawal_refunds_user_id_created_at_idx;
+
+DROP TABLE IF EXISTS statements;
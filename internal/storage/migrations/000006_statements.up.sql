@@ -0,0 +1,19 @@
+CREATE TABLE
+    IF NOT EXISTS statements (
+        id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+        user_id INTEGER NOT NULL,
+        period DATE NOT NULL,
+        opening_balance DECIMAL(10, 2) NOT NULL,
+        accruals DECIMAL(10, 2) NOT NULL,
+        withdrawals DECIMAL(10, 2) NOT NULL,
+        refunds DECIMAL(10, 2) NOT NULL,
+        transfers_in DECIMAL(10, 2) NOT NULL,
+        transfers_out DECIMAL(10, 2) NOT NULL,
+        expirations DECIMAL(10, 2) NOT NULL,
+        closing_balance DECIMAL(10, 2) NOT NULL,
+        created_at TIMESTAMP DEFAULT now () NOT NULL,
+        CONSTRAINT statements_user_period_unique UNIQUE (user_id, period),
+        CONSTRAINT statements_fk_users foreign key (user_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS withdrawal_refunds_user_id_created_at_idx ON withdrawal_refunds (user_id, created_at);
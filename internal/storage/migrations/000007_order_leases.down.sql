@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS orders_status_lease_expires_at_idx;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS lease_owner,
+    DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,7 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS lease_owner VARCHAR(100) NULL,
+    ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS orders_status_lease_expires_at_idx ON orders (status, lease_expires_at);
+
+DROP TABLE IF EXISTS accrual_jobs;
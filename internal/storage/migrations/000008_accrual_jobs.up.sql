@@ -0,0 +1,25 @@
+CREATE TABLE
+    IF NOT EXISTS accrual_jobs (
+        order_id INTEGER PRIMARY KEY,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        next_attempt_at TIMESTAMP DEFAULT now () NOT NULL,
+        last_error TEXT NULL,
+        lease_owner VARCHAR(100) NULL,
+        lease_expires_at TIMESTAMP NULL,
+        created_at TIMESTAMP DEFAULT now () NOT NULL,
+        updated_at TIMESTAMP DEFAULT now () NOT NULL,
+        CONSTRAINT accrual_jobs_fk_orders foreign key (order_id) REFERENCES orders (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS accrual_jobs_next_attempt_at_idx ON accrual_jobs (next_attempt_at);
+
+INSERT INTO accrual_jobs (order_id)
+SELECT id FROM orders WHERE status IN ('NEW', 'PROCESSING')
+ON CONFLICT DO NOTHING;
+
+-- аренда переезжает из заказов в задачи
+DROP INDEX IF EXISTS orders_status_lease_expires_at_idx;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS lease_owner,
+    DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS accrual_jobs_dead_at_idx;
+
+ALTER TABLE accrual_jobs
+    DROP COLUMN IF EXISTS failures,
+    DROP COLUMN IF EXISTS last_http_status,
+    DROP COLUMN IF EXISTS last_response,
+    DROP COLUMN IF EXISTS dead_at;
@@ -0,0 +1,7 @@
+ALTER TABLE accrual_jobs
+    ADD COLUMN IF NOT EXISTS failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_http_status INTEGER NULL,
+    ADD COLUMN IF NOT EXISTS last_response TEXT NULL,
+    ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS accrual_jobs_dead_at_idx ON accrual_jobs (dead_at) WHERE dead_at IS NOT NULL;
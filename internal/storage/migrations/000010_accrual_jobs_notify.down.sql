@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS accrual_jobs_notify_requeue ON accrual_jobs;
+DROP TRIGGER IF EXISTS accrual_jobs_notify_insert ON accrual_jobs;
+DROP FUNCTION IF EXISTS accrual_jobs_notify();
@@ -0,0 +1,19 @@
+-- уведомление о задаче, готовой к немедленному опросу: новая или возвращенная из dead-letter;
+-- payload - id заказа, доставляется слушателям после фиксации транзакции
+CREATE OR REPLACE FUNCTION accrual_jobs_notify() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('accrual_jobs', NEW.order_id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS accrual_jobs_notify_insert ON accrual_jobs;
+CREATE TRIGGER accrual_jobs_notify_insert
+    AFTER INSERT ON accrual_jobs
+    FOR EACH ROW EXECUTE FUNCTION accrual_jobs_notify();
+
+DROP TRIGGER IF EXISTS accrual_jobs_notify_requeue ON accrual_jobs;
+CREATE TRIGGER accrual_jobs_notify_requeue
+    AFTER UPDATE OF dead_at ON accrual_jobs
+    FOR EACH ROW WHEN (OLD.dead_at IS NOT NULL AND NEW.dead_at IS NULL)
+    EXECUTE FUNCTION accrual_jobs_notify();
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS accrual_calls;
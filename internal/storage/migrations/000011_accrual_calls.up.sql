@@ -0,0 +1,16 @@
+CREATE TABLE
+    IF NOT EXISTS accrual_calls (
+        id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+        order_number VARCHAR(255) NOT NULL,
+        provider VARCHAR(100) NOT NULL,
+        url TEXT NOT NULL,
+        http_status INTEGER NULL,
+        response_body TEXT NULL,
+        error TEXT NULL,
+        latency_ms INTEGER NOT NULL,
+        created_at TIMESTAMP DEFAULT now () NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS accrual_calls_order_number_created_at_idx ON accrual_calls (order_number, created_at);
+
+CREATE INDEX IF NOT EXISTS accrual_calls_created_at_idx ON accrual_calls (created_at);
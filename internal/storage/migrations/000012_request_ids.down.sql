@@ -0,0 +1,5 @@
+ALTER TABLE accrual_calls
+    DROP COLUMN IF EXISTS request_id;
+
+ALTER TABLE accrual_jobs
+    DROP COLUMN IF EXISTS request_id;
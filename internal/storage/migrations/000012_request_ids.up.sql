@@ -0,0 +1,5 @@
+ALTER TABLE accrual_jobs
+    ADD COLUMN IF NOT EXISTS request_id VARCHAR(100) NULL;
+
+ALTER TABLE accrual_calls
+    ADD COLUMN IF NOT EXISTS request_id VARCHAR(100) NULL;
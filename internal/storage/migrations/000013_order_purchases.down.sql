@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS order_items;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS accrual_engine,
+    DROP COLUMN IF EXISTS store;
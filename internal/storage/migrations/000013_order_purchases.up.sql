@@ -0,0 +1,16 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS accrual_engine VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS store VARCHAR(100) NULL;
+
+CREATE TABLE
+    IF NOT EXISTS order_items (
+        id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+        order_id INTEGER NOT NULL,
+        product VARCHAR(255) NULL,
+        category VARCHAR(255) NULL,
+        price DECIMAL(10, 2) NOT NULL,
+        quantity INTEGER NOT NULL DEFAULT 1,
+        CONSTRAINT order_items_fk_orders foreign key (order_id) REFERENCES orders (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS order_items_order_id_idx ON order_items (order_id);
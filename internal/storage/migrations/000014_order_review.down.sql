@@ -0,0 +1,6 @@
+-- значение из enum удалить нельзя: заказы на проверке считаются отклоненными
+UPDATE orders SET status = 'INVALID' WHERE status = 'NEEDS_REVIEW';
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS review_accrual,
+    DROP COLUMN IF EXISTS review_reason;
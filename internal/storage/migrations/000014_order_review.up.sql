@@ -0,0 +1,5 @@
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'NEEDS_REVIEW';
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS review_accrual DECIMAL(10, 2) NULL,
+    ADD COLUMN IF NOT EXISTS review_reason TEXT NULL;
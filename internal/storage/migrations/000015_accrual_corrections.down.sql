@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS order_adjustments;
+
+DELETE FROM accrual_jobs WHERE correct_until IS NOT NULL;
+
+ALTER TABLE accrual_jobs
+    DROP COLUMN IF EXISTS correct_until;
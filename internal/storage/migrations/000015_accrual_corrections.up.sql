@@ -0,0 +1,19 @@
+-- после обработки заказ опрашивается до correct_until, чтобы учесть исправления начисления
+ALTER TABLE accrual_jobs
+    ADD COLUMN IF NOT EXISTS correct_until TIMESTAMP NULL;
+
+CREATE TABLE
+    IF NOT EXISTS order_adjustments (
+        id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+        order_id INTEGER NOT NULL,
+        user_id INTEGER NOT NULL,
+        amount DECIMAL(10, 2) NOT NULL,
+        base_accrual DECIMAL(10, 2) NOT NULL,
+        unrecovered DECIMAL(10, 2) NOT NULL DEFAULT 0,
+        created_at TIMESTAMP DEFAULT now () NOT NULL,
+        CONSTRAINT order_adjustments_fk_orders foreign key (order_id) REFERENCES orders (id) ON DELETE CASCADE,
+        CONSTRAINT order_adjustments_fk_users foreign key (user_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS order_adjustments_order_id_idx ON order_adjustments (order_id);
+CREATE INDEX IF NOT EXISTS order_adjustments_user_id_created_at_idx ON order_adjustments (user_id, created_at);
@@ -0,0 +1,155 @@
+// управление схемой базы встроенными миграциями для PostgreSQL и SQLite
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage/migrations"
+	sqlitemigrations "github.com/ex0rcist/gophermart/internal/storage/sqlite/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+)
+
+var ErrNoMigrations = errors.New("storage has no migrations")
+
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+}
+
+// состояние схемы: примененная версия, последняя доступная и признак прерванной миграции
+type Status struct {
+	Version uint
+	Latest  uint
+	Dirty   bool
+}
+
+func (s Status) Pending() bool {
+	return s.Version < s.Latest
+}
+
+// миграции выбираются по схеме DSN; у хранилища в памяти их нет
+func New(dbConfig config.DB) (*Migrator, error) {
+	var fsys fs.FS
+
+	switch dbConfig.Driver() {
+	case config.DBDriverPostgres:
+		fsys = migrations.FS
+	case config.DBDriverSQLite:
+		fsys = sqlitemigrations.FS
+	default:
+		return nil, ErrNoMigrations
+	}
+
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, dbConfig.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("migrate.NewWithSourceInstance() failed: %w", err)
+	}
+
+	return &Migrator{migrate: m, source: src}, nil
+}
+
+// применяет все новые миграции; для вызова при старте сервера
+func Up(dbConfig config.DB) error {
+	m, err := New(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Up()
+}
+
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.migrate.Up())
+}
+
+// откатывает steps последних миграций
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+
+	return ignoreNoChange(m.migrate.Steps(-steps))
+}
+
+// применяет или откатывает миграции до версии version
+func (m *Migrator) To(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+// записывает версию без выполнения миграций, чтобы снять признак прерванной миграции;
+// -1 - схема без миграций
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+func (m *Migrator) Status() (Status, error) {
+	var status Status
+
+	version, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return status, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	status.Version, status.Dirty = version, dirty
+
+	status.Latest, err = m.latest()
+	if err != nil {
+		return status, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	return status, nil
+}
+
+func (m *Migrator) Close() {
+	srcErr, dbErr := m.migrate.Close()
+	if srcErr != nil {
+		logging.LogError(srcErr, "failed closing migrator")
+	}
+	if dbErr != nil {
+		logging.LogError(dbErr, "failed closing migrator")
+	}
+}
+
+func (m *Migrator) latest() (uint, error) {
+	version, err := m.source.First()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	for {
+		next, err := m.source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		logging.LogInfo("migrations: no change")
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,83 @@
+package migrator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/storage/migrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMigrator(t *testing.T) *migrator.Migrator {
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "gophermart.db")
+
+	m, err := migrator.New(config.DB{DSN: dsn})
+	require.NoError(t, err)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+func TestUpAndDown(t *testing.T) {
+	m := newMigrator(t)
+
+	status, err := m.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), status.Version)
+	assert.Equal(t, uint(1), status.Latest)
+	assert.True(t, status.Pending())
+
+	require.NoError(t, m.Up())
+	require.NoError(t, m.Up()) // повторный запуск ничего не меняет
+
+	status, err = m.Status()
+	require.NoError(t, err)
+	assert.Equal(t, migrator.Status{Version: 1, Latest: 1}, status)
+	assert.False(t, status.Pending())
+
+	require.NoError(t, m.Down(1))
+
+	status, err = m.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), status.Version)
+
+	assert.Error(t, m.Down(0))
+}
+
+func TestTo(t *testing.T) {
+	m := newMigrator(t)
+
+	require.NoError(t, m.To(1))
+	require.NoError(t, m.To(1))
+
+	status, err := m.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), status.Version)
+
+	assert.Error(t, m.To(42))
+}
+
+func TestForce(t *testing.T) {
+	m := newMigrator(t)
+
+	require.NoError(t, m.Force(1))
+
+	status, err := m.Status()
+	require.NoError(t, err)
+	assert.Equal(t, migrator.Status{Version: 1, Latest: 1}, status)
+
+	require.NoError(t, m.Force(-1))
+
+	status, err = m.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), status.Version)
+}
+
+func TestNoMigrationsForMemory(t *testing.T) {
+	_, err := migrator.New(config.DB{DSN: "memory://"})
+	assert.ErrorIs(t, err, migrator.ErrNoMigrations)
+
+	assert.ErrorIs(t, migrator.Up(config.DB{DSN: "memory://"}), migrator.ErrNoMigrations)
+}
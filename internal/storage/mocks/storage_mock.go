@@ -3,64 +3,128 @@
 //
 // Generated by this command:
 //
-//      mockgen -source=internal/storage/storage.go
+//	mockgen -source=internal/storage/storage.go
 //
 
 // Package mock_storage is a generated GoMock package.
 package mock_storage
 
 import (
-        reflect "reflect"
+	context "context"
+	reflect "reflect"
 
-        storage "github.com/ex0rcist/gophermart/internal/storage"
-        gomock "go.uber.org/mock/gomock"
+	storage "github.com/ex0rcist/gophermart/internal/storage"
+	gomock "go.uber.org/mock/gomock"
 )
 
+// MockIStorage is a mock of IStorage interface.
+type MockIStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStorageMockRecorder
+}
+
+// MockIStorageMockRecorder is the mock recorder for MockIStorage.
+type MockIStorageMockRecorder struct {
+	mock *MockIStorage
+}
+
+// NewMockIStorage creates a new mock instance.
+func NewMockIStorage(ctrl *gomock.Controller) *MockIStorage {
+	mock := &MockIStorage{ctrl: ctrl}
+	mock.recorder = &MockIStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStorage) EXPECT() *MockIStorageMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockIStorage) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIStorageMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIStorage)(nil).Close))
+}
+
+// WithinTx mocks base method.
+func (m *MockIStorage) WithinTx(ctx context.Context, opts storage.TxOptions, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, opts, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockIStorageMockRecorder) WithinTx(ctx, opts, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockIStorage)(nil).WithinTx), ctx, opts, fn)
+}
+
 // MockIPGXStorage is a mock of IPGXStorage interface.
 type MockIPGXStorage struct {
-        ctrl     *gomock.Controller
-        recorder *MockIPGXStorageMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockIPGXStorageMockRecorder
 }
 
 // MockIPGXStorageMockRecorder is the mock recorder for MockIPGXStorage.
 type MockIPGXStorageMockRecorder struct {
-        mock *MockIPGXStorage
+	mock *MockIPGXStorage
 }
 
 // NewMockIPGXStorage creates a new mock instance.
 func NewMockIPGXStorage(ctrl *gomock.Controller) *MockIPGXStorage {
-        mock := &MockIPGXStorage{ctrl: ctrl}
-        mock.recorder = &MockIPGXStorageMockRecorder{mock}
-        return mock
+	mock := &MockIPGXStorage{ctrl: ctrl}
+	mock.recorder = &MockIPGXStorageMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockIPGXStorage) EXPECT() *MockIPGXStorageMockRecorder {
-        return m.recorder
+	return m.recorder
 }
 
 // Close mocks base method.
 func (m *MockIPGXStorage) Close() {
-        m.ctrl.T.Helper()
-        m.ctrl.Call(m, "Close")
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
 }
 
 // Close indicates an expected call of Close.
 func (mr *MockIPGXStorageMockRecorder) Close() *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIPGXStorage)(nil).Close))
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIPGXStorage)(nil).Close))
 }
 
 // GetPool mocks base method.
 func (m *MockIPGXStorage) GetPool() storage.IPGXPool {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "GetPool")
-        ret0, _ := ret[0].(storage.IPGXPool)
-        return ret0
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPool")
+	ret0, _ := ret[0].(storage.IPGXPool)
+	return ret0
 }
 
 // GetPool indicates an expected call of GetPool.
 func (mr *MockIPGXStorageMockRecorder) GetPool() *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPool", reflect.TypeOf((*MockIPGXStorage)(nil).GetPool))
-}
\ No newline at end of file
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPool", reflect.TypeOf((*MockIPGXStorage)(nil).GetPool))
+}
+
+// WithinTx mocks base method.
+func (m *MockIPGXStorage) WithinTx(ctx context.Context, opts storage.TxOptions, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, opts, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockIPGXStorageMockRecorder) WithinTx(ctx, opts, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockIPGXStorage)(nil).WithinTx), ctx, opts, fn)
+}
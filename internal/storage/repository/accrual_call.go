@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+)
+
+type IAccrualCallRepository interface {
+	AccrualCallCreate(ctx context.Context, call domain.AccrualCall) error
+	AccrualCallListByOrderNumber(ctx context.Context, number string) ([]*domain.AccrualCall, error)
+	AccrualCallDeleteOlderThan(ctx context.Context, age time.Duration) (int64, error)
+}
+
+type accrualCallRepository struct {
+	pool storage.IPGXPool
+}
+
+func NewAccrualCallRepository(pool storage.IPGXPool) IAccrualCallRepository {
+	return &accrualCallRepository{pool: pool}
+}
+
+func (repo *accrualCallRepository) AccrualCallCreate(ctx context.Context, call domain.AccrualCall) error {
+	stmt := `
+	INSERT INTO accrual_calls (order_number, provider, url, http_status, response_body, error, latency_ms, request_id)
+	VALUES ($1, $2, $3, NULLIF($4, 0), NULLIF($5, ''), NULLIF($6, ''), $7, NULLIF($8, ''))`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(
+		ctx, stmt,
+		call.OrderNumber, call.Provider, call.URL, call.HTTPStatus, call.ResponseBody, call.Error, call.Latency.Milliseconds(),
+		call.RequestID,
+	)
+	if err != nil {
+		return fmt.Errorf("accrualCallRepository -> AccrualCallCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// обращения по заказу в хронологическом порядке
+func (repo *accrualCallRepository) AccrualCallListByOrderNumber(ctx context.Context, number string) ([]*domain.AccrualCall, error) {
+	stmt := `
+	SELECT id, order_number, provider, url, COALESCE(http_status, 0), COALESCE(response_body, ''),
+		COALESCE(error, ''), latency_ms, COALESCE(request_id, ''), created_at
+	FROM accrual_calls WHERE order_number = $1 ORDER BY created_at, id`
+
+	calls := make([]*domain.AccrualCall, 0)
+
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, stmt, number)
+	if err != nil {
+		return nil, fmt.Errorf("accrualCallRepository -> AccrualCallListByOrderNumber() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		call := &domain.AccrualCall{}
+
+		var latencyMs int64
+		err = rows.Scan(
+			&call.ID, &call.OrderNumber, &call.Provider, &call.URL, &call.HTTPStatus, &call.ResponseBody,
+			&call.Error, &latencyMs, &call.RequestID, &call.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("accrualCallRepository -> AccrualCallListByOrderNumber() error: %w", err)
+		}
+
+		call.Latency = time.Duration(latencyMs) * time.Millisecond
+		calls = append(calls, call)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("accrualCallRepository -> AccrualCallListByOrderNumber() error: %w", err)
+	}
+
+	return calls, nil
+}
+
+// удаляет обращения старше age, возвращает число удаленных
+func (repo *accrualCallRepository) AccrualCallDeleteOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	stmt := `DELETE FROM accrual_calls WHERE created_at < now() - $1 * interval '1 millisecond'`
+
+	tag, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, age.Milliseconds())
+	if err != nil {
+		return 0, fmt.Errorf("accrualCallRepository -> AccrualCallDeleteOlderThan() error: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
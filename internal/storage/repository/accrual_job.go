@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/jackc/pgx/v5"
+)
+
+type IAccrualJobRepository interface {
+	AccrualJobClaimDue(ctx context.Context, owner string, lease time.Duration, limit int) ([]*domain.AccrualJob, error)
+	AccrualJobClaim(ctx context.Context, owner string, lease time.Duration, orderID domain.OrderID) (*domain.AccrualJob, error)
+	AccrualJobReschedule(ctx context.Context, job domain.AccrualJob, delay time.Duration) error
+	AccrualJobMarkDead(ctx context.Context, job domain.AccrualJob) error
+	AccrualJobDelete(ctx context.Context, orderID domain.OrderID) error
+	AccrualJobStartCorrection(ctx context.Context, orderID domain.OrderID, window time.Duration, delay time.Duration) error
+	AccrualJobFindByOrderNumber(ctx context.Context, number string) (*domain.AccrualJob, error)
+	AccrualJobListDead(ctx context.Context) ([]*domain.AccrualJob, error)
+	AccrualJobRequeue(ctx context.Context, orderID domain.OrderID) error
+	AccrualJobReleaseLeases(ctx context.Context, owner string, numbers []string) error
+}
+
+type accrualJobRepository struct {
+	pool storage.IPGXPool
+}
+
+func NewAccrualJobRepository(pool storage.IPGXPool) IAccrualJobRepository {
+	return &accrualJobRepository{pool: pool}
+}
+
+const accrualJobColumns = `
+	j.order_id, j.attempts, j.failures, j.next_attempt_at, COALESCE(j.last_error, ''),
+	COALESCE(j.last_http_status, 0), COALESCE(j.last_response, ''), COALESCE(j.lease_owner, ''),
+	COALESCE(j.request_id, ''), j.dead_at, j.correct_until, j.created_at, j.updated_at, o.user_id, o.number, o.status,
+	COALESCE(o.accrual_engine, ''), COALESCE(o.store, ''), o.created_at`
+
+func scanAccrualJob(row pgx.Row) (*domain.AccrualJob, error) {
+	job := &domain.AccrualJob{Order: &domain.Order{}}
+	err := row.Scan(
+		&job.OrderID, &job.Attempts, &job.Failures, &job.NextAttemptAt, &job.LastError,
+		&job.LastHTTPStatus, &job.LastResponse, &job.LeaseOwner,
+		&job.RequestID, &job.DeadAt, &job.CorrectUntil, &job.CreatedAt, &job.UpdatedAt,
+		&job.Order.UserID, &job.Order.Number, &job.Order.Status, &job.Order.Engine, &job.Order.Store, &job.Order.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Order.ID = job.OrderID
+	return job, nil
+}
+
+func (repo *accrualJobRepository) queryJobs(ctx context.Context, stmt string, args ...any) ([]*domain.AccrualJob, error) {
+	jobs := make([]*domain.AccrualJob, 0)
+
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		job, err := scanAccrualJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// захватывает в аренду пачку задач, время которых подошло;
+// задачи, захваченные другими экземплярами сервиса, пропускаются до истечения их аренды
+func (repo *accrualJobRepository) AccrualJobClaimDue(ctx context.Context, owner string, lease time.Duration, limit int) ([]*domain.AccrualJob, error) {
+	stmt := `
+	WITH due AS (
+		SELECT order_id FROM accrual_jobs
+		WHERE next_attempt_at <= now()
+			AND dead_at IS NULL
+			AND (lease_expires_at IS NULL OR lease_expires_at < now())
+		ORDER BY next_attempt_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	UPDATE accrual_jobs j
+	SET lease_owner = $1, lease_expires_at = now() + $2 * interval '1 millisecond'
+	FROM due
+	JOIN orders o ON o.id = due.order_id
+	WHERE j.order_id = due.order_id
+	RETURNING` + accrualJobColumns
+
+	jobs, err := repo.queryJobs(ctx, stmt, owner, lease.Milliseconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobClaimDue() error: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// захватывает в аренду задачу по конкретному заказу, если ее время подошло и она свободна;
+// иначе ErrRecordNotFound
+func (repo *accrualJobRepository) AccrualJobClaim(ctx context.Context, owner string, lease time.Duration, orderID domain.OrderID) (*domain.AccrualJob, error) {
+	stmt := `
+	WITH due AS (
+		SELECT order_id FROM accrual_jobs
+		WHERE order_id = $3
+			AND next_attempt_at <= now()
+			AND dead_at IS NULL
+			AND (lease_expires_at IS NULL OR lease_expires_at < now())
+		FOR UPDATE SKIP LOCKED
+	)
+	UPDATE accrual_jobs j
+	SET lease_owner = $1, lease_expires_at = now() + $2 * interval '1 millisecond'
+	FROM due
+	JOIN orders o ON o.id = due.order_id
+	WHERE j.order_id = due.order_id
+	RETURNING` + accrualJobColumns
+
+	job, err := scanAccrualJob(storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, owner, lease.Milliseconds(), orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobClaim() error: %w", err)
+	}
+
+	return job, nil
+}
+
+// откладывает задачу на delay и снимает аренду;
+// если аренда уже перешла к другому экземпляру, задача не меняется
+func (repo *accrualJobRepository) AccrualJobReschedule(ctx context.Context, job domain.AccrualJob, delay time.Duration) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET attempts = $1, failures = $2, last_error = NULLIF($3, ''),
+		last_http_status = NULLIF($4, 0), last_response = NULLIF($5, ''),
+		next_attempt_at = now() + $6 * interval '1 millisecond',
+		lease_owner = NULL, lease_expires_at = NULL, updated_at = now()
+	WHERE order_id = $7 AND lease_owner = $8`
+
+	args := []any{
+		job.Attempts, job.Failures, job.LastError, job.LastHTTPStatus, job.LastResponse,
+		delay.Milliseconds(), job.OrderID, job.LeaseOwner,
+	}
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobReschedule() error: %w", err)
+	}
+
+	return nil
+}
+
+// переводит задачу в dead-letter, сохраняя последний ответ и ошибку
+func (repo *accrualJobRepository) AccrualJobMarkDead(ctx context.Context, job domain.AccrualJob) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET attempts = $1, failures = $2, last_error = NULLIF($3, ''),
+		last_http_status = NULLIF($4, 0), last_response = NULLIF($5, ''),
+		dead_at = now(), lease_owner = NULL, lease_expires_at = NULL, updated_at = now()
+	WHERE order_id = $6 AND lease_owner = $7`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(
+		ctx, stmt,
+		job.Attempts, job.Failures, job.LastError, job.LastHTTPStatus, job.LastResponse, job.OrderID, job.LeaseOwner,
+	)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobMarkDead() error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *accrualJobRepository) AccrualJobDelete(ctx context.Context, orderID domain.OrderID) error {
+	stmt := `DELETE FROM accrual_jobs WHERE order_id = $1`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, orderID)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobDelete() error: %w", err)
+	}
+
+	return nil
+}
+
+// после обработки заказа задача остается до конца окна исправлений и опрашивается раз в delay
+func (repo *accrualJobRepository) AccrualJobStartCorrection(ctx context.Context, orderID domain.OrderID, window time.Duration, delay time.Duration) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET correct_until = now() + $1 * interval '1 millisecond',
+		next_attempt_at = now() + $2 * interval '1 millisecond',
+		attempts = 0, failures = 0, last_error = NULL, last_http_status = NULL, last_response = NULL,
+		lease_owner = NULL, lease_expires_at = NULL, updated_at = now()
+	WHERE order_id = $3`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, window.Milliseconds(), delay.Milliseconds(), orderID)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobStartCorrection() error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *accrualJobRepository) AccrualJobFindByOrderNumber(ctx context.Context, number string) (*domain.AccrualJob, error) {
+	stmt := `SELECT` + accrualJobColumns + `
+	FROM accrual_jobs j
+	JOIN orders o ON o.id = j.order_id
+	WHERE o.number = $1`
+
+	job, err := scanAccrualJob(storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, number))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobFindByOrderNumber() error: %w", err)
+	}
+
+	return job, nil
+}
+
+func (repo *accrualJobRepository) AccrualJobListDead(ctx context.Context) ([]*domain.AccrualJob, error) {
+	stmt := `SELECT` + accrualJobColumns + `
+	FROM accrual_jobs j
+	JOIN orders o ON o.id = j.order_id
+	WHERE j.dead_at IS NOT NULL
+	ORDER BY j.dead_at DESC`
+
+	jobs, err := repo.queryJobs(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobListDead() error: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// возвращает задачу из dead-letter в очередь с немедленной попыткой
+func (repo *accrualJobRepository) AccrualJobRequeue(ctx context.Context, orderID domain.OrderID) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET dead_at = NULL, attempts = 0, failures = 0, next_attempt_at = now(), updated_at = now()
+	WHERE order_id = $1 AND dead_at IS NOT NULL`
+
+	tag, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, orderID)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobRequeue() error: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return storage.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// снимает аренду задач экземпляра по номерам заказов, время следующей попытки не меняется
+func (repo *accrualJobRepository) AccrualJobReleaseLeases(ctx context.Context, owner string, numbers []string) error {
+	stmt := `
+	UPDATE accrual_jobs j
+	SET lease_owner = NULL, lease_expires_at = NULL, updated_at = now()
+	FROM orders o
+	WHERE o.id = j.order_id AND o.number = ANY($1) AND j.lease_owner = $2`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, numbers, owner)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobReleaseLeases() error: %w", err)
+	}
+
+	return nil
+}
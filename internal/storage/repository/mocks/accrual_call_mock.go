@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/storage/repository/accrual_call.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/storage/repository/accrual_call.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAccrualCallRepository is a mock of IAccrualCallRepository interface.
+type MockIAccrualCallRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccrualCallRepositoryMockRecorder
+}
+
+// MockIAccrualCallRepositoryMockRecorder is the mock recorder for MockIAccrualCallRepository.
+type MockIAccrualCallRepositoryMockRecorder struct {
+	mock *MockIAccrualCallRepository
+}
+
+// NewMockIAccrualCallRepository creates a new mock instance.
+func NewMockIAccrualCallRepository(ctrl *gomock.Controller) *MockIAccrualCallRepository {
+	mock := &MockIAccrualCallRepository{ctrl: ctrl}
+	mock.recorder = &MockIAccrualCallRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccrualCallRepository) EXPECT() *MockIAccrualCallRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AccrualCallCreate mocks base method.
+func (m *MockIAccrualCallRepository) AccrualCallCreate(ctx context.Context, call domain.AccrualCall) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualCallCreate", ctx, call)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrualCallCreate indicates an expected call of AccrualCallCreate.
+func (mr *MockIAccrualCallRepositoryMockRecorder) AccrualCallCreate(ctx, call any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualCallCreate", reflect.TypeOf((*MockIAccrualCallRepository)(nil).AccrualCallCreate), ctx, call)
+}
+
+// AccrualCallDeleteOlderThan mocks base method.
+func (m *MockIAccrualCallRepository) AccrualCallDeleteOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualCallDeleteOlderThan", ctx, age)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrualCallDeleteOlderThan indicates an expected call of AccrualCallDeleteOlderThan.
+func (mr *MockIAccrualCallRepositoryMockRecorder) AccrualCallDeleteOlderThan(ctx, age any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualCallDeleteOlderThan", reflect.TypeOf((*MockIAccrualCallRepository)(nil).AccrualCallDeleteOlderThan), ctx, age)
+}
+
+// AccrualCallListByOrderNumber mocks base method.
+func (m *MockIAccrualCallRepository) AccrualCallListByOrderNumber(ctx context.Context, number string) ([]*domain.AccrualCall, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualCallListByOrderNumber", ctx, number)
+	ret0, _ := ret[0].([]*domain.AccrualCall)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrualCallListByOrderNumber indicates an expected call of AccrualCallListByOrderNumber.
+func (mr *MockIAccrualCallRepositoryMockRecorder) AccrualCallListByOrderNumber(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualCallListByOrderNumber", reflect.TypeOf((*MockIAccrualCallRepository)(nil).AccrualCallListByOrderNumber), ctx, number)
+}
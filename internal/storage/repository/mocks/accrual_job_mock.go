@@ -0,0 +1,186 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/storage/repository/accrual_job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/storage/repository/accrual_job.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAccrualJobRepository is a mock of IAccrualJobRepository interface.
+type MockIAccrualJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccrualJobRepositoryMockRecorder
+}
+
+// MockIAccrualJobRepositoryMockRecorder is the mock recorder for MockIAccrualJobRepository.
+type MockIAccrualJobRepositoryMockRecorder struct {
+	mock *MockIAccrualJobRepository
+}
+
+// NewMockIAccrualJobRepository creates a new mock instance.
+func NewMockIAccrualJobRepository(ctrl *gomock.Controller) *MockIAccrualJobRepository {
+	mock := &MockIAccrualJobRepository{ctrl: ctrl}
+	mock.recorder = &MockIAccrualJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccrualJobRepository) EXPECT() *MockIAccrualJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AccrualJobClaim mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobClaim(ctx context.Context, owner string, lease time.Duration, orderID domain.OrderID) (*domain.AccrualJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobClaim", ctx, owner, lease, orderID)
+	ret0, _ := ret[0].(*domain.AccrualJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrualJobClaim indicates an expected call of AccrualJobClaim.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobClaim(ctx, owner, lease, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobClaim", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobClaim), ctx, owner, lease, orderID)
+}
+
+// AccrualJobClaimDue mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobClaimDue(ctx context.Context, owner string, lease time.Duration, limit int) ([]*domain.AccrualJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobClaimDue", ctx, owner, lease, limit)
+	ret0, _ := ret[0].([]*domain.AccrualJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrualJobClaimDue indicates an expected call of AccrualJobClaimDue.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobClaimDue(ctx, owner, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobClaimDue", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobClaimDue), ctx, owner, lease, limit)
+}
+
+// AccrualJobDelete mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobDelete(ctx context.Context, orderID domain.OrderID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobDelete", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrualJobDelete indicates an expected call of AccrualJobDelete.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobDelete(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobDelete", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobDelete), ctx, orderID)
+}
+
+// AccrualJobFindByOrderNumber mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobFindByOrderNumber(ctx context.Context, number string) (*domain.AccrualJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobFindByOrderNumber", ctx, number)
+	ret0, _ := ret[0].(*domain.AccrualJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrualJobFindByOrderNumber indicates an expected call of AccrualJobFindByOrderNumber.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobFindByOrderNumber(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobFindByOrderNumber", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobFindByOrderNumber), ctx, number)
+}
+
+// AccrualJobListDead mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobListDead(ctx context.Context) ([]*domain.AccrualJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobListDead", ctx)
+	ret0, _ := ret[0].([]*domain.AccrualJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrualJobListDead indicates an expected call of AccrualJobListDead.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobListDead(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobListDead", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobListDead), ctx)
+}
+
+// AccrualJobMarkDead mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobMarkDead(ctx context.Context, job domain.AccrualJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobMarkDead", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrualJobMarkDead indicates an expected call of AccrualJobMarkDead.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobMarkDead(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobMarkDead", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobMarkDead), ctx, job)
+}
+
+// AccrualJobReleaseLeases mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobReleaseLeases(ctx context.Context, owner string, numbers []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobReleaseLeases", ctx, owner, numbers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrualJobReleaseLeases indicates an expected call of AccrualJobReleaseLeases.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobReleaseLeases(ctx, owner, numbers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobReleaseLeases", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobReleaseLeases), ctx, owner, numbers)
+}
+
+// AccrualJobRequeue mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobRequeue(ctx context.Context, orderID domain.OrderID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobRequeue", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrualJobRequeue indicates an expected call of AccrualJobRequeue.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobRequeue(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobRequeue", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobRequeue), ctx, orderID)
+}
+
+// AccrualJobReschedule mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobReschedule(ctx context.Context, job domain.AccrualJob, delay time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobReschedule", ctx, job, delay)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrualJobReschedule indicates an expected call of AccrualJobReschedule.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobReschedule(ctx, job, delay any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobReschedule", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobReschedule), ctx, job, delay)
+}
+
+// AccrualJobStartCorrection mocks base method.
+func (m *MockIAccrualJobRepository) AccrualJobStartCorrection(ctx context.Context, orderID domain.OrderID, window, delay time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrualJobStartCorrection", ctx, orderID, window, delay)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrualJobStartCorrection indicates an expected call of AccrualJobStartCorrection.
+func (mr *MockIAccrualJobRepositoryMockRecorder) AccrualJobStartCorrection(ctx, orderID, window, delay any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrualJobStartCorrection", reflect.TypeOf((*MockIAccrualJobRepository)(nil).AccrualJobStartCorrection), ctx, orderID, window, delay)
+}
@@ -3,114 +3,189 @@
 //
 // Generated by this command:
 //
-//      mockgen -source=internal/storage/repository/orders.go
+//	mockgen -source=internal/storage/repository/orders.go
 //
 
 // Package mock_repository is a generated GoMock package.
 package mock_repository
 
 import (
-        context "context"
-        reflect "reflect"
+	context "context"
+	reflect "reflect"
+	time "time"
 
-        domain "github.com/ex0rcist/gophermart/internal/domain"
-        pgx "github.com/jackc/pgx/v5"
-        gomock "go.uber.org/mock/gomock"
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	decimal "github.com/shopspring/decimal"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockIOrderRepository is a mock of IOrderRepository interface.
 type MockIOrderRepository struct {
-        ctrl     *gomock.Controller
-        recorder *MockIOrderRepositoryMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockIOrderRepositoryMockRecorder
 }
 
 // MockIOrderRepositoryMockRecorder is the mock recorder for MockIOrderRepository.
 type MockIOrderRepositoryMockRecorder struct {
-        mock *MockIOrderRepository
+	mock *MockIOrderRepository
 }
 
 // NewMockIOrderRepository creates a new mock instance.
 func NewMockIOrderRepository(ctrl *gomock.Controller) *MockIOrderRepository {
-        mock := &MockIOrderRepository{ctrl: ctrl}
-        mock.recorder = &MockIOrderRepositoryMockRecorder{mock}
-        return mock
+	mock := &MockIOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockIOrderRepositoryMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockIOrderRepository) EXPECT() *MockIOrderRepositoryMockRecorder {
-        return m.recorder
+	return m.recorder
+}
+
+// OrderAccrualCurrent mocks base method.
+func (m *MockIOrderRepository) OrderAccrualCurrent(ctx context.Context, id domain.OrderID) (decimal.Decimal, decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderAccrualCurrent", ctx, id)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(decimal.Decimal)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OrderAccrualCurrent indicates an expected call of OrderAccrualCurrent.
+func (mr *MockIOrderRepositoryMockRecorder) OrderAccrualCurrent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderAccrualCurrent", reflect.TypeOf((*MockIOrderRepository)(nil).OrderAccrualCurrent), ctx, id)
+}
+
+// OrderAccrualSumSince mocks base method.
+func (m *MockIOrderRepository) OrderAccrualSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderAccrualSumSince", ctx, userID, since)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderAccrualSumSince indicates an expected call of OrderAccrualSumSince.
+func (mr *MockIOrderRepositoryMockRecorder) OrderAccrualSumSince(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderAccrualSumSince", reflect.TypeOf((*MockIOrderRepository)(nil).OrderAccrualSumSince), ctx, userID, since)
+}
+
+// OrderAdjustmentCreate mocks base method.
+func (m *MockIOrderRepository) OrderAdjustmentCreate(ctx context.Context, a domain.OrderAdjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderAdjustmentCreate", ctx, a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OrderAdjustmentCreate indicates an expected call of OrderAdjustmentCreate.
+func (mr *MockIOrderRepositoryMockRecorder) OrderAdjustmentCreate(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderAdjustmentCreate", reflect.TypeOf((*MockIOrderRepository)(nil).OrderAdjustmentCreate), ctx, a)
 }
 
 // OrderCreate mocks base method.
 func (m *MockIOrderRepository) OrderCreate(ctx context.Context, o domain.Order) (*domain.Order, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "OrderCreate", ctx, o)
-        ret0, _ := ret[0].(*domain.Order)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderCreate", ctx, o)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // OrderCreate indicates an expected call of OrderCreate.
 func (mr *MockIOrderRepositoryMockRecorder) OrderCreate(ctx, o any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderCreate", reflect.TypeOf((*MockIOrderRepository)(nil).OrderCreate), ctx, o)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderCreate", reflect.TypeOf((*MockIOrderRepository)(nil).OrderCreate), ctx, o)
 }
 
 // OrderFindByNumber mocks base method.
 func (m *MockIOrderRepository) OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "OrderFindByNumber", ctx, number)
-        ret0, _ := ret[0].(*domain.Order)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderFindByNumber", ctx, number)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // OrderFindByNumber indicates an expected call of OrderFindByNumber.
 func (mr *MockIOrderRepositoryMockRecorder) OrderFindByNumber(ctx, number any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderFindByNumber", reflect.TypeOf((*MockIOrderRepository)(nil).OrderFindByNumber), ctx, number)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderFindByNumber", reflect.TypeOf((*MockIOrderRepository)(nil).OrderFindByNumber), ctx, number)
 }
 
 // OrderList mocks base method.
 func (m *MockIOrderRepository) OrderList(ctx context.Context, userID domain.UserID) ([]*domain.Order, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "OrderList", ctx, userID)
-        ret0, _ := ret[0].([]*domain.Order)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderList", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // OrderList indicates an expected call of OrderList.
 func (mr *MockIOrderRepositoryMockRecorder) OrderList(ctx, userID any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderList", reflect.TypeOf((*MockIOrderRepository)(nil).OrderList), ctx, userID)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderList", reflect.TypeOf((*MockIOrderRepository)(nil).OrderList), ctx, userID)
+}
+
+// OrderListForReview mocks base method.
+func (m *MockIOrderRepository) OrderListForReview(ctx context.Context) ([]*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderListForReview", ctx)
+	ret0, _ := ret[0].([]*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderListForReview indicates an expected call of OrderListForReview.
+func (mr *MockIOrderRepositoryMockRecorder) OrderListForReview(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderListForReview", reflect.TypeOf((*MockIOrderRepository)(nil).OrderListForReview), ctx)
 }
 
-// OrderListForUpdate mocks base method.
-func (m *MockIOrderRepository) OrderListForUpdate(ctx context.Context) ([]*domain.Order, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "OrderListForUpdate", ctx)
-        ret0, _ := ret[0].([]*domain.Order)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+// OrderMarkForReview mocks base method.
+func (m *MockIOrderRepository) OrderMarkForReview(ctx context.Context, o domain.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderMarkForReview", ctx, o)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// OrderListForUpdate indicates an expected call of OrderListForUpdate.
-func (mr *MockIOrderRepositoryMockRecorder) OrderListForUpdate(ctx any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderListForUpdate", reflect.TypeOf((*MockIOrderRepository)(nil).OrderListForUpdate), ctx)
+// OrderMarkForReview indicates an expected call of OrderMarkForReview.
+func (mr *MockIOrderRepositoryMockRecorder) OrderMarkForReview(ctx, o any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderMarkForReview", reflect.TypeOf((*MockIOrderRepository)(nil).OrderMarkForReview), ctx, o)
+}
+
+// OrderPurchaseFindByNumber mocks base method.
+func (m *MockIOrderRepository) OrderPurchaseFindByNumber(ctx context.Context, number string) (*domain.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderPurchaseFindByNumber", ctx, number)
+	ret0, _ := ret[0].(*domain.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderPurchaseFindByNumber indicates an expected call of OrderPurchaseFindByNumber.
+func (mr *MockIOrderRepositoryMockRecorder) OrderPurchaseFindByNumber(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderPurchaseFindByNumber", reflect.TypeOf((*MockIOrderRepository)(nil).OrderPurchaseFindByNumber), ctx, number)
 }
 
 // OrderUpdate mocks base method.
-func (m *MockIOrderRepository) OrderUpdate(ctx context.Context, tx pgx.Tx, o domain.Order) error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "OrderUpdate", ctx, tx, o)
-        ret0, _ := ret[0].(error)
-        return ret0
+func (m *MockIOrderRepository) OrderUpdate(ctx context.Context, o domain.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderUpdate", ctx, o)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // OrderUpdate indicates an expected call of OrderUpdate.
-func (mr *MockIOrderRepositoryMockRecorder) OrderUpdate(ctx, tx, o any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderUpdate", reflect.TypeOf((*MockIOrderRepository)(nil).OrderUpdate), ctx, tx, o)
-}
\ No newline at end of file
+func (mr *MockIOrderRepositoryMockRecorder) OrderUpdate(ctx, o any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderUpdate", reflect.TypeOf((*MockIOrderRepository)(nil).OrderUpdate), ctx, o)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/storage/repository/statement.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/storage/repository/statement.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIStatementRepository is a mock of IStatementRepository interface.
+type MockIStatementRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStatementRepositoryMockRecorder
+}
+
+// MockIStatementRepositoryMockRecorder is the mock recorder for MockIStatementRepository.
+type MockIStatementRepositoryMockRecorder struct {
+	mock *MockIStatementRepository
+}
+
+// NewMockIStatementRepository creates a new mock instance.
+func NewMockIStatementRepository(ctrl *gomock.Controller) *MockIStatementRepository {
+	mock := &MockIStatementRepository{ctrl: ctrl}
+	mock.recorder = &MockIStatementRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStatementRepository) EXPECT() *MockIStatementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// StatementCreate mocks base method.
+func (m *MockIStatementRepository) StatementCreate(ctx context.Context, s domain.Statement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatementCreate", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StatementCreate indicates an expected call of StatementCreate.
+func (mr *MockIStatementRepositoryMockRecorder) StatementCreate(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatementCreate", reflect.TypeOf((*MockIStatementRepository)(nil).StatementCreate), ctx, s)
+}
+
+// StatementFind mocks base method.
+func (m *MockIStatementRepository) StatementFind(ctx context.Context, userID domain.UserID, period time.Time) (*domain.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatementFind", ctx, userID, period)
+	ret0, _ := ret[0].(*domain.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatementFind indicates an expected call of StatementFind.
+func (mr *MockIStatementRepositoryMockRecorder) StatementFind(ctx, userID, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatementFind", reflect.TypeOf((*MockIStatementRepository)(nil).StatementFind), ctx, userID, period)
+}
+
+// StatementTurnover mocks base method.
+func (m *MockIStatementRepository) StatementTurnover(ctx context.Context, userID domain.UserID, from, to time.Time) (*domain.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatementTurnover", ctx, userID, from, to)
+	ret0, _ := ret[0].(*domain.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatementTurnover indicates an expected call of StatementTurnover.
+func (mr *MockIStatementRepositoryMockRecorder) StatementTurnover(ctx, userID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatementTurnover", reflect.TypeOf((*MockIStatementRepository)(nil).StatementTurnover), ctx, userID, from, to)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/storage/repository/transfer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/storage/repository/transfer.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	decimal "github.com/shopspring/decimal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockITransferRepository is a mock of ITransferRepository interface.
+type MockITransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransferRepositoryMockRecorder
+}
+
+// MockITransferRepositoryMockRecorder is the mock recorder for MockITransferRepository.
+type MockITransferRepositoryMockRecorder struct {
+	mock *MockITransferRepository
+}
+
+// NewMockITransferRepository creates a new mock instance.
+func NewMockITransferRepository(ctrl *gomock.Controller) *MockITransferRepository {
+	mock := &MockITransferRepository{ctrl: ctrl}
+	mock.recorder = &MockITransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransferRepository) EXPECT() *MockITransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// TransferCreate mocks base method.
+func (m *MockITransferRepository) TransferCreate(ctx context.Context, t domain.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferCreate", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferCreate indicates an expected call of TransferCreate.
+func (mr *MockITransferRepositoryMockRecorder) TransferCreate(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferCreate", reflect.TypeOf((*MockITransferRepository)(nil).TransferCreate), ctx, t)
+}
+
+// TransferList mocks base method.
+func (m *MockITransferRepository) TransferList(ctx context.Context, userID domain.UserID) ([]*domain.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferList", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferList indicates an expected call of TransferList.
+func (mr *MockITransferRepositoryMockRecorder) TransferList(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferList", reflect.TypeOf((*MockITransferRepository)(nil).TransferList), ctx, userID)
+}
+
+// TransferStatsSince mocks base method.
+func (m *MockITransferRepository) TransferStatsSince(ctx context.Context, senderID domain.UserID, since time.Time) (decimal.Decimal, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferStatsSince", ctx, senderID, since)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TransferStatsSince indicates an expected call of TransferStatsSince.
+func (mr *MockITransferRepositoryMockRecorder) TransferStatsSince(ctx, senderID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferStatsSince", reflect.TypeOf((*MockITransferRepository)(nil).TransferStatsSince), ctx, senderID, since)
+}
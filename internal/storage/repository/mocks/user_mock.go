@@ -3,101 +3,143 @@
 //
 // Generated by this command:
 //
-//      mockgen -source=internal/storage/repository/user.go
+//	mockgen -source=internal/storage/repository/user.go
 //
 
 // Package mock_repository is a generated GoMock package.
 package mock_repository
 
 import (
-        context "context"
-        reflect "reflect"
+	context "context"
+	reflect "reflect"
 
-        domain "github.com/ex0rcist/gophermart/internal/domain"
-        pgx "github.com/jackc/pgx/v5"
-        decimal "github.com/shopspring/decimal"
-        gomock "go.uber.org/mock/gomock"
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	decimal "github.com/shopspring/decimal"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockIUserRepository is a mock of IUserRepository interface.
 type MockIUserRepository struct {
-        ctrl     *gomock.Controller
-        recorder *MockIUserRepositoryMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockIUserRepositoryMockRecorder
 }
 
 // MockIUserRepositoryMockRecorder is the mock recorder for MockIUserRepository.
 type MockIUserRepositoryMockRecorder struct {
-        mock *MockIUserRepository
+	mock *MockIUserRepository
 }
 
 // NewMockIUserRepository creates a new mock instance.
 func NewMockIUserRepository(ctrl *gomock.Controller) *MockIUserRepository {
-        mock := &MockIUserRepository{ctrl: ctrl}
-        mock.recorder = &MockIUserRepositoryMockRecorder{mock}
-        return mock
+	mock := &MockIUserRepository{ctrl: ctrl}
+	mock.recorder = &MockIUserRepositoryMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockIUserRepository) EXPECT() *MockIUserRepositoryMockRecorder {
-        return m.recorder
+	return m.recorder
 }
 
 // UserCreate mocks base method.
 func (m *MockIUserRepository) UserCreate(ctx context.Context, login, password string) (*domain.User, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "UserCreate", ctx, login, password)
-        ret0, _ := ret[0].(*domain.User)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserCreate", ctx, login, password)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UserCreate indicates an expected call of UserCreate.
 func (mr *MockIUserRepositoryMockRecorder) UserCreate(ctx, login, password any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCreate", reflect.TypeOf((*MockIUserRepository)(nil).UserCreate), ctx, login, password)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCreate", reflect.TypeOf((*MockIUserRepository)(nil).UserCreate), ctx, login, password)
 }
 
 // UserFindByLogin mocks base method.
 func (m *MockIUserRepository) UserFindByLogin(ctx context.Context, login string) (*domain.User, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "UserFindByLogin", ctx, login)
-        ret0, _ := ret[0].(*domain.User)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserFindByLogin", ctx, login)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UserFindByLogin indicates an expected call of UserFindByLogin.
 func (mr *MockIUserRepositoryMockRecorder) UserFindByLogin(ctx, login any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserFindByLogin", reflect.TypeOf((*MockIUserRepository)(nil).UserFindByLogin), ctx, login)
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserFindByLogin", reflect.TypeOf((*MockIUserRepository)(nil).UserFindByLogin), ctx, login)
 }
 
 // UserGetBalance mocks base method.
-func (m *MockIUserRepository) UserGetBalance(ctx context.Context, tx pgx.Tx, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "UserGetBalance", ctx, tx, id)
-        ret0, _ := ret[0].(*decimal.Decimal)
-        ret1, _ := ret[1].(*decimal.Decimal)
-        ret2, _ := ret[2].(error)
-        return ret0, ret1, ret2
+func (m *MockIUserRepository) UserGetBalance(ctx context.Context, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserGetBalance", ctx, id)
+	ret0, _ := ret[0].(*decimal.Decimal)
+	ret1, _ := ret[1].(*decimal.Decimal)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // UserGetBalance indicates an expected call of UserGetBalance.
-func (mr *MockIUserRepositoryMockRecorder) UserGetBalance(ctx, tx, id any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserGetBalance", reflect.TypeOf((*MockIUserRepository)(nil).UserGetBalance), ctx, tx, id)
+func (mr *MockIUserRepositoryMockRecorder) UserGetBalance(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserGetBalance", reflect.TypeOf((*MockIUserRepository)(nil).UserGetBalance), ctx, id)
+}
+
+// UserGetTier mocks base method.
+func (m *MockIUserRepository) UserGetTier(ctx context.Context, id domain.UserID) (domain.LoyaltyTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserGetTier", ctx, id)
+	ret0, _ := ret[0].(domain.LoyaltyTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserGetTier indicates an expected call of UserGetTier.
+func (mr *MockIUserRepositoryMockRecorder) UserGetTier(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserGetTier", reflect.TypeOf((*MockIUserRepository)(nil).UserGetTier), ctx, id)
 }
 
 // UserUpdateBalanceAndWithdrawals mocks base method.
-func (m *MockIUserRepository) UserUpdateBalanceAndWithdrawals(ctx context.Context, tx pgx.Tx, id domain.UserID) error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "UserUpdateBalanceAndWithdrawals", ctx, tx, id)
-        ret0, _ := ret[0].(error)
-        return ret0
+func (m *MockIUserRepository) UserUpdateBalanceAndWithdrawals(ctx context.Context, id domain.UserID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserUpdateBalanceAndWithdrawals", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // UserUpdateBalanceAndWithdrawals indicates an expected call of UserUpdateBalanceAndWithdrawals.
-func (mr *MockIUserRepositoryMockRecorder) UserUpdateBalanceAndWithdrawals(ctx, tx, id any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUpdateBalanceAndWithdrawals", reflect.TypeOf((*MockIUserRepository)(nil).UserUpdateBalanceAndWithdrawals), ctx, tx, id)
-}
\ No newline at end of file
+func (mr *MockIUserRepositoryMockRecorder) UserUpdateBalanceAndWithdrawals(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUpdateBalanceAndWithdrawals", reflect.TypeOf((*MockIUserRepository)(nil).UserUpdateBalanceAndWithdrawals), ctx, id)
+}
+
+// UserUpdatePassword mocks base method.
+func (m *MockIUserRepository) UserUpdatePassword(ctx context.Context, id domain.UserID, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserUpdatePassword", ctx, id, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserUpdatePassword indicates an expected call of UserUpdatePassword.
+func (mr *MockIUserRepositoryMockRecorder) UserUpdatePassword(ctx, id, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUpdatePassword", reflect.TypeOf((*MockIUserRepository)(nil).UserUpdatePassword), ctx, id, password)
+}
+
+// UserUpdateTier mocks base method.
+func (m *MockIUserRepository) UserUpdateTier(ctx context.Context, id domain.UserID, tier domain.LoyaltyTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserUpdateTier", ctx, id, tier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserUpdateTier indicates an expected call of UserUpdateTier.
+func (mr *MockIUserRepositoryMockRecorder) UserUpdateTier(ctx, id, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUpdateTier", reflect.TypeOf((*MockIUserRepository)(nil).UserUpdateTier), ctx, id, tier)
+}
@@ -3,69 +3,128 @@
 //
 // Generated by this command:
 //
-//      mockgen -source=internal/storage/repository/withdrawal.go
+//	mockgen -source=internal/storage/repository/withdrawal.go
 //
 
 // Package mock_repository is a generated GoMock package.
 package mock_repository
 
 import (
-        context "context"
-        reflect "reflect"
+	context "context"
+	reflect "reflect"
+	time "time"
 
-        domain "github.com/ex0rcist/gophermart/internal/domain"
-        pgx "github.com/jackc/pgx/v5"
-        gomock "go.uber.org/mock/gomock"
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	decimal "github.com/shopspring/decimal"
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockIWithdrawalRepository is a mock of IWithdrawalRepository interface.
 type MockIWithdrawalRepository struct {
-        ctrl     *gomock.Controller
-        recorder *MockIWithdrawalRepositoryMockRecorder
+	ctrl     *gomock.Controller
+	recorder *MockIWithdrawalRepositoryMockRecorder
 }
 
 // MockIWithdrawalRepositoryMockRecorder is the mock recorder for MockIWithdrawalRepository.
 type MockIWithdrawalRepositoryMockRecorder struct {
-        mock *MockIWithdrawalRepository
+	mock *MockIWithdrawalRepository
 }
 
 // NewMockIWithdrawalRepository creates a new mock instance.
 func NewMockIWithdrawalRepository(ctrl *gomock.Controller) *MockIWithdrawalRepository {
-        mock := &MockIWithdrawalRepository{ctrl: ctrl}
-        mock.recorder = &MockIWithdrawalRepositoryMockRecorder{mock}
-        return mock
+	mock := &MockIWithdrawalRepository{ctrl: ctrl}
+	mock.recorder = &MockIWithdrawalRepositoryMockRecorder{mock}
+	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockIWithdrawalRepository) EXPECT() *MockIWithdrawalRepositoryMockRecorder {
-        return m.recorder
+	return m.recorder
 }
 
 // WithdrawalCreate mocks base method.
-func (m *MockIWithdrawalRepository) WithdrawalCreate(ctx context.Context, tx pgx.Tx, w domain.Withdrawal) error {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "WithdrawalCreate", ctx, tx, w)
-        ret0, _ := ret[0].(error)
-        return ret0
+func (m *MockIWithdrawalRepository) WithdrawalCreate(ctx context.Context, w domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawalCreate", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // WithdrawalCreate indicates an expected call of WithdrawalCreate.
-func (mr *MockIWithdrawalRepositoryMockRecorder) WithdrawalCreate(ctx, tx, w any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalCreate", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalCreate), ctx, tx, w)
+func (mr *MockIWithdrawalRepositoryMockRecorder) WithdrawalCreate(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalCreate", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalCreate), ctx, w)
+}
+
+// WithdrawalFindByOrderNumber mocks base method.
+func (m *MockIWithdrawalRepository) WithdrawalFindByOrderNumber(ctx context.Context, number string) (*domain.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawalFindByOrderNumber", ctx, number)
+	ret0, _ := ret[0].(*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawalFindByOrderNumber indicates an expected call of WithdrawalFindByOrderNumber.
+func (mr *MockIWithdrawalRepositoryMockRecorder) WithdrawalFindByOrderNumber(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalFindByOrderNumber", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalFindByOrderNumber), ctx, number)
 }
 
 // WithdrawalList mocks base method.
 func (m *MockIWithdrawalRepository) WithdrawalList(ctx context.Context, userID domain.UserID) ([]*domain.Withdrawal, error) {
-        m.ctrl.T.Helper()
-        ret := m.ctrl.Call(m, "WithdrawalList", ctx, userID)
-        ret0, _ := ret[0].([]*domain.Withdrawal)
-        ret1, _ := ret[1].(error)
-        return ret0, ret1
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawalList", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // WithdrawalList indicates an expected call of WithdrawalList.
 func (mr *MockIWithdrawalRepositoryMockRecorder) WithdrawalList(ctx, userID any) *gomock.Call {
-        mr.mock.ctrl.T.Helper()
-        return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalList", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalList), ctx, userID)
-}
\ No newline at end of file
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalList", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalList), ctx, userID)
+}
+
+// WithdrawalRefundCreate mocks base method.
+func (m *MockIWithdrawalRepository) WithdrawalRefundCreate(ctx context.Context, r domain.WithdrawalRefund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawalRefundCreate", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawalRefundCreate indicates an expected call of WithdrawalRefundCreate.
+func (mr *MockIWithdrawalRepositoryMockRecorder) WithdrawalRefundCreate(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalRefundCreate", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalRefundCreate), ctx, r)
+}
+
+// WithdrawalSumSince mocks base method.
+func (m *MockIWithdrawalRepository) WithdrawalSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawalSumSince", ctx, userID, since)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawalSumSince indicates an expected call of WithdrawalSumSince.
+func (mr *MockIWithdrawalRepositoryMockRecorder) WithdrawalSumSince(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalSumSince", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalSumSince), ctx, userID, since)
+}
+
+// WithdrawalUpdate mocks base method.
+func (m *MockIWithdrawalRepository) WithdrawalUpdate(ctx context.Context, w domain.Withdrawal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawalUpdate", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawalUpdate indicates an expected call of WithdrawalUpdate.
+func (mr *MockIWithdrawalRepositoryMockRecorder) WithdrawalUpdate(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalUpdate", reflect.TypeOf((*MockIWithdrawalRepository)(nil).WithdrawalUpdate), ctx, w)
+}
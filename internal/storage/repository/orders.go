@@ -4,18 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ex0rcist/gophermart/internal/domain"
 	"github.com/ex0rcist/gophermart/internal/storage"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
 type IOrderRepository interface {
 	OrderCreate(ctx context.Context, o domain.Order) (*domain.Order, error)
 	OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error)
+	OrderPurchaseFindByNumber(ctx context.Context, number string) (*domain.Purchase, error)
 	OrderList(ctx context.Context, userID domain.UserID) ([]*domain.Order, error)
-	OrderListForUpdate(ctx context.Context) ([]*domain.Order, error)
-	OrderUpdate(ctx context.Context, tx pgx.Tx, o domain.Order) error
+	OrderUpdate(ctx context.Context, o domain.Order) error
+	OrderMarkForReview(ctx context.Context, o domain.Order) error
+	OrderListForReview(ctx context.Context) ([]*domain.Order, error)
+	OrderAccrualSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error)
+	OrderAccrualCurrent(ctx context.Context, id domain.OrderID) (decimal.Decimal, decimal.Decimal, error)
+	OrderAdjustmentCreate(ctx context.Context, a domain.OrderAdjustment) error
 }
 
 type orderRepository struct {
@@ -26,10 +33,39 @@ func NewOrderRepository(pool storage.IPGXPool) IOrderRepository {
 	return &orderRepository{pool: pool}
 }
 
+// вместе с заказом создается задача опроса системы начислений и сохраняются данные о покупке
 func (repo *orderRepository) OrderCreate(ctx context.Context, order domain.Order) (*domain.Order, error) {
-	stmt := `INSERT INTO orders (user_id, number, status) VALUES ($1, $2, $3) RETURNING id, user_id, number, status, accrual, created_at, updated_at`
+	stmt := `
+	WITH o AS (
+		INSERT INTO orders (user_id, number, status, accrual_engine, store) VALUES ($1, $2, $3, NULLIF($5, ''), NULLIF($6, ''))
+		RETURNING id, user_id, number, status, accrual, created_at, updated_at
+	), j AS (
+		INSERT INTO accrual_jobs (order_id, request_id) SELECT id, NULLIF($4, '') FROM o
+	), i AS (
+		INSERT INTO order_items (order_id, product, category, price, quantity)
+		SELECT o.id, NULLIF(item.product, ''), NULLIF(item.category, ''), item.price::numeric, item.quantity
+		FROM o, unnest($7::text[], $8::text[], $9::text[], $10::int[]) AS item (product, category, price, quantity)
+	)
+	SELECT id, user_id, number, status, accrual, created_at, updated_at FROM o`
+
+	var store string
+	var products, categories, prices []string
+	var quantities []int32
+	if order.Purchase != nil {
+		store = order.Purchase.Store
+		for _, item := range order.Purchase.Items {
+			products = append(products, item.Product)
+			categories = append(categories, item.Category)
+			prices = append(prices, item.Price.String())
+			quantities = append(quantities, int32(item.Quantity))
+		}
+	}
 
-	rows, err := repo.pool.Query(ctx, stmt, order.UserID, order.Number, order.Status)
+	rows, err := storage.Querier(ctx, repo.pool).Query(
+		ctx, stmt,
+		order.UserID, order.Number, order.Status, order.RequestID, order.Engine, store,
+		products, categories, prices, quantities,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("orderRepository -> OrderCreate() error: %w", err)
 	}
@@ -46,14 +82,20 @@ func (repo *orderRepository) OrderCreate(ctx context.Context, order domain.Order
 		}
 	}
 
+	newOrder.Engine = order.Engine
+	newOrder.Purchase = order.Purchase
+
 	return newOrder, nil
 }
 
 func (repo *orderRepository) OrderList(ctx context.Context, userID domain.UserID) ([]*domain.Order, error) {
-	stmt := `SELECT number, status, accrual, created_at FROM orders WHERE user_id = $1 ORDER BY created_at DESC`
+	// начисление показывается с учетом корректировок
+	stmt := `
+	SELECT o.number, o.status, o.accrual + COALESCE((SELECT SUM(a.amount) FROM order_adjustments a WHERE a.order_id = o.id), 0), o.created_at
+	FROM orders o WHERE o.user_id = $1 ORDER BY o.created_at DESC`
 	orders := make([]*domain.Order, 0)
 
-	rows, err := repo.pool.Query(ctx, stmt, userID)
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, stmt, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, storage.ErrRecordNotFound
@@ -74,13 +116,23 @@ func (repo *orderRepository) OrderList(ctx context.Context, userID domain.UserID
 	return orders, nil
 }
 
+// в транзакции строка заказа блокируется до её завершения
 func (repo *orderRepository) OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error) {
-	stmt := `SELECT id, user_id, number, status, accrual, created_at, updated_at FROM orders WHERE number = $1`
+	stmt := `
+	SELECT id, user_id, number, status, accrual, COALESCE(accrual_engine, ''),
+		COALESCE(review_accrual, 0), COALESCE(review_reason, ''), created_at, updated_at
+	FROM orders WHERE number = $1`
+
+	if storage.HasTx(ctx) {
+		stmt += " FOR UPDATE"
+	}
+
 	order := new(domain.Order)
 
-	err := repo.pool.QueryRow(ctx, stmt, number).Scan(
+	err := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, number).Scan(
 		&order.ID, &order.UserID, &order.Number, &order.Status,
-		&order.Accrual, &order.CreatedAt, &order.UpdatedAt,
+		&order.Accrual, &order.Engine, &order.ReviewAccrual, &order.ReviewReason,
+		&order.CreatedAt, &order.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -92,46 +144,155 @@ func (repo *orderRepository) OrderFindByNumber(ctx context.Context, number strin
 	return order, nil
 }
 
-func (repo *orderRepository) OrderListForUpdate(ctx context.Context) ([]*domain.Order, error) {
-	stmt := `SELECT id, user_id, number, status, created_at FROM orders WHERE status IN ('NEW', 'PROCESSING');`
-	orders := make([]*domain.Order, 0)
+// данные о покупке для встроенного расчета начислений
+func (repo *orderRepository) OrderPurchaseFindByNumber(ctx context.Context, number string) (*domain.Purchase, error) {
+	stmt := `SELECT id, COALESCE(store, ''), created_at FROM orders WHERE number = $1`
 
-	rows, err := repo.pool.Query(ctx, stmt)
+	var orderID domain.OrderID
+	purchase := &domain.Purchase{Items: make([]domain.PurchaseItem, 0)}
+
+	err := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, number).Scan(&orderID, &purchase.Store, &purchase.CreatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, storage.ErrRecordNotFound
 		}
-		return nil, err
+		return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+	}
+
+	itemsStmt := `
+	SELECT COALESCE(product, ''), COALESCE(category, ''), price, quantity
+	FROM order_items WHERE order_id = $1 ORDER BY id`
+
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, itemsStmt, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.PurchaseItem
+		if err = rows.Scan(&item.Product, &item.Category, &item.Price, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+		}
+		purchase.Items = append(purchase.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+	}
+
+	return purchase, nil
+}
+
+func (repo *orderRepository) OrderUpdate(ctx context.Context, order domain.Order) error {
+	stmt := `UPDATE orders SET status = $1, accrual = $2, base_accrual = $3, updated_at = now() WHERE id = $4`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, order.Status, order.Accrual, order.BaseAccrual, order.ID)
+	if err != nil {
+		return fmt.Errorf("orderRepository -> OrderUpdate() error: %w", err)
+	}
+
+	return nil
+}
+
+// откладывает начисление до решения администратора
+func (repo *orderRepository) OrderMarkForReview(ctx context.Context, order domain.Order) error {
+	stmt := `
+	UPDATE orders SET status = 'NEEDS_REVIEW', review_accrual = $1, review_reason = $2, updated_at = now()
+	WHERE id = $3`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, order.ReviewAccrual, order.ReviewReason, order.ID)
+	if err != nil {
+		return fmt.Errorf("orderRepository -> OrderMarkForReview() error: %w", err)
+	}
+
+	return nil
+}
+
+// заказы, ждущие ручной проверки, начиная с самых давних
+func (repo *orderRepository) OrderListForReview(ctx context.Context) ([]*domain.Order, error) {
+	stmt := `
+	SELECT id, user_id, number, status, COALESCE(review_accrual, 0), COALESCE(review_reason, ''), created_at, updated_at
+	FROM orders WHERE status = 'NEEDS_REVIEW' ORDER BY updated_at`
+	orders := make([]*domain.Order, 0)
+
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderListForReview() error: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		order := &domain.Order{}
-		if err = rows.Scan(&order.ID, &order.UserID, &order.Number, &order.Status, &order.CreatedAt); err != nil {
-			return nil, fmt.Errorf("orderRepository -> OrderListForUpdate() error: %w", err)
+		err = rows.Scan(
+			&order.ID, &order.UserID, &order.Number, &order.Status,
+			&order.ReviewAccrual, &order.ReviewReason, &order.CreatedAt, &order.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("orderRepository -> OrderListForReview() error: %w", err)
 		}
 		orders = append(orders, order)
 	}
 
-	err = rows.Err()
-	if err != nil {
-		return nil, fmt.Errorf("orderRepository -> OrderListForUpdate() error: %w", err)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderListForReview() error: %w", err)
 	}
 
 	return orders, nil
 }
 
-func (repo *orderRepository) OrderUpdate(ctx context.Context, tx pgx.Tx, order domain.Order) error {
-	stmt := `UPDATE orders SET status = $1, accrual = $2 WHERE id = $3`
+// сумма начислений системы без множителя уровня по обработанным заказам пользователя начиная с since;
+// от нее зависит уровень, поэтому собственный множитель пользователя на уровень не влияет
+func (repo *orderRepository) OrderAccrualSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	stmt := `
+	SELECT
+		(SELECT COALESCE(SUM(base_accrual), 0) FROM orders
+			WHERE user_id = $1 AND status = 'PROCESSED' AND updated_at >= $2) +
+		(SELECT COALESCE(SUM(d.delta), 0) FROM (
+			SELECT a.created_at, a.base_accrual - COALESCE(
+				LAG(a.base_accrual) OVER (PARTITION BY a.order_id ORDER BY a.id), o.base_accrual, 0) AS delta
+			FROM order_adjustments a JOIN orders o ON o.id = a.order_id
+			WHERE a.user_id = $1) d
+			WHERE d.created_at >= $2)`
 
-	var err error
-	if tx != nil {
-		_, err = tx.Exec(ctx, stmt, order.Status, order.Accrual, order.ID)
-	} else {
-		_, err = repo.pool.Exec(ctx, stmt, order.Status, order.Accrual, order.ID)
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, userID, since)
+
+	var sum decimal.Decimal
+	if err := row.Scan(&sum); err != nil {
+		return decimal.Zero, fmt.Errorf("orderRepository -> OrderAccrualSumSince() error: %w", err)
 	}
 
+	return sum, nil
+}
+
+// начисление по заказу с учетом корректировок и последнее начисление системы до применения множителя уровня
+func (repo *orderRepository) OrderAccrualCurrent(ctx context.Context, id domain.OrderID) (decimal.Decimal, decimal.Decimal, error) {
+	stmt := `
+	SELECT
+		o.accrual + COALESCE((SELECT SUM(a.amount) FROM order_adjustments a WHERE a.order_id = o.id), 0),
+		COALESCE((SELECT a.base_accrual FROM order_adjustments a WHERE a.order_id = o.id ORDER BY a.id DESC LIMIT 1), o.base_accrual, 0)
+	FROM orders o WHERE o.id = $1`
+
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, id)
+
+	var accrual, base decimal.Decimal
+	if err := row.Scan(&accrual, &base); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return decimal.Zero, decimal.Zero, storage.ErrRecordNotFound
+		}
+		return decimal.Zero, decimal.Zero, fmt.Errorf("orderRepository -> OrderAccrualCurrent() error: %w", err)
+	}
+
+	return accrual, base, nil
+}
+
+// проводка исправления начисления; само начисление по заказу не меняется
+func (repo *orderRepository) OrderAdjustmentCreate(ctx context.Context, a domain.OrderAdjustment) error {
+	stmt := `INSERT INTO order_adjustments (order_id, user_id, amount, base_accrual, unrecovered) VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, a.OrderID, a.UserID, a.Amount, a.BaseAccrual, a.Unrecovered)
 	if err != nil {
-		return fmt.Errorf("orderRepository -> OrderUpdate() error: %w", err)
+		return fmt.Errorf("orderRepository -> OrderAdjustmentCreate() error: %w", err)
 	}
 
 	return nil
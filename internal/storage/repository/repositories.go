@@ -0,0 +1,26 @@
+package repository
+
+import "github.com/ex0rcist/gophermart/internal/storage"
+
+// репозитории одного хранилища, общие для HTTP API и сервиса начислений
+type Repositories struct {
+	User        IUserRepository
+	Order       IOrderRepository
+	Withdrawal  IWithdrawalRepository
+	Transfer    ITransferRepository
+	Statement   IStatementRepository
+	AccrualJob  IAccrualJobRepository
+	AccrualCall IAccrualCallRepository
+}
+
+func NewPGXRepositories(pool storage.IPGXPool) *Repositories {
+	return &Repositories{
+		User:        NewUserRepository(pool),
+		Order:       NewOrderRepository(pool),
+		Withdrawal:  NewWithdrawalRepository(pool),
+		Transfer:    NewTransferRepository(pool),
+		Statement:   NewStatementRepository(pool),
+		AccrualJob:  NewAccrualJobRepository(pool),
+		AccrualCall: NewAccrualCallRepository(pool),
+	}
+}
@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/storage/repository/repotest"
+	"github.com/stretchr/testify/require"
+)
+
+// набор тестов выполняется на реальной БД, только если задан TEST_DATABASE_URI
+func TestPGXRepositories(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URI")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URI is not set")
+	}
+
+	s, err := storage.NewPGXStorage(config.DB{DSN: dsn}, nil, true)
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	repotest.Run(t, func(t *testing.T) (storage.IStorage, *repository.Repositories) {
+		_, err := s.GetPool().Exec(context.Background(), `TRUNCATE users, orders, order_items, order_adjustments, withdrawals,
+			withdrawal_refunds, transfers, statements, accrual_jobs, accrual_calls RESTART IDENTITY CASCADE`)
+		require.NoError(t, err)
+
+		return s, repository.NewPGXRepositories(s.GetPool())
+	})
+}
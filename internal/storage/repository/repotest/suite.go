@@ -0,0 +1,602 @@
+// общий набор тестов репозиториев, который должна проходить каждая реализация хранилища
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// создает пустое хранилище для одного теста
+type Factory func(t *testing.T) (storage.IStorage, *repository.Repositories)
+
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.IStorage, repos *repository.Repositories)
+	}{
+		{"UserCreateAndFind", testUserCreateAndFind},
+		{"UserTier", testUserTier},
+		{"UserUpdatePassword", testUserUpdatePassword},
+		{"UserBalance", testUserBalance},
+		{"OrderCreateAndFind", testOrderCreateAndFind},
+		{"OrderList", testOrderList},
+		{"OrderReview", testOrderReview},
+		{"OrderAccruals", testOrderAccruals},
+		{"WithdrawalCreateAndFind", testWithdrawalCreateAndFind},
+		{"WithdrawalRefund", testWithdrawalRefund},
+		{"TransferList", testTransferList},
+		{"Statement", testStatement},
+		{"AccrualJobLifecycle", testAccrualJobLifecycle},
+		{"AccrualCalls", testAccrualCalls},
+		{"TxRollback", testTxRollback},
+		{"TxConcurrentWithdrawals", testTxConcurrentWithdrawals},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repos := newStorage(t)
+			tt.fn(t, s, repos)
+		})
+	}
+}
+
+func createUser(t *testing.T, repos *repository.Repositories, login string) *domain.User {
+	t.Helper()
+
+	user, err := repos.User.UserCreate(context.Background(), login, "hash")
+	require.NoError(t, err)
+
+	return user
+}
+
+func createOrder(t *testing.T, repos *repository.Repositories, userID domain.UserID, number string) *domain.Order {
+	t.Helper()
+
+	order, err := repos.Order.OrderCreate(context.Background(), domain.Order{UserID: userID, Number: number, Status: domain.OrderStatusNew})
+	require.NoError(t, err)
+
+	return order
+}
+
+func processOrder(t *testing.T, repos *repository.Repositories, order *domain.Order, accrual int64) {
+	t.Helper()
+
+	err := repos.Order.OrderUpdate(context.Background(), domain.Order{
+		ID:          order.ID,
+		Status:      domain.OrderStatusProcessed,
+		Accrual:     decimal.NewFromInt(accrual),
+		BaseAccrual: decimal.NewFromInt(accrual),
+	})
+	require.NoError(t, err)
+}
+
+func assertDecimal(t *testing.T, expected int64, actual decimal.Decimal) {
+	t.Helper()
+
+	assert.True(t, decimal.NewFromInt(expected).Equal(actual), "expected %d, got %s", expected, actual)
+}
+
+func testUserCreateAndFind(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+
+	user := createUser(t, repos, "me")
+	assert.NotZero(t, user.ID)
+	assert.Equal(t, "me", user.Login)
+
+	_, err := repos.User.UserCreate(ctx, "me", "other")
+	assert.Error(t, err, "login must be unique")
+
+	found, err := repos.User.UserFindByLogin(ctx, "me")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+	assert.Equal(t, "hash", found.Password)
+
+	_, err = repos.User.UserFindByLogin(ctx, "nobody")
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+
+	_, _, err = repos.User.UserGetBalance(ctx, user.ID+100)
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+}
+
+func testUserTier(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+
+	tier, err := repos.User.UserGetTier(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tier)
+
+	require.NoError(t, repos.User.UserUpdateTier(ctx, user.ID, "GOLD"))
+
+	tier, err = repos.User.UserGetTier(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LoyaltyTier("GOLD"), tier)
+}
+
+func testUserUpdatePassword(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+
+	found, err := repos.User.UserFindByLogin(ctx, "me")
+	require.NoError(t, err)
+	assert.Nil(t, found.PasswordChangedAt, "password set on registration is not a change")
+
+	before := time.Now().Add(-time.Second)
+	require.NoError(t, repos.User.UserUpdatePassword(ctx, user.ID, "new-hash"))
+
+	found, err = repos.User.UserFindByLogin(ctx, "me")
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", found.Password)
+	require.NotNil(t, found.PasswordChangedAt)
+	assert.True(t, found.PasswordChangedAt.After(before), "changed at %s", found.PasswordChangedAt)
+}
+
+// баланс складывается из начислений, корректировок, списаний за вычетом возвратов и переводов
+func testUserBalance(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+	mom := createUser(t, repos, "mom")
+
+	processOrder(t, repos, createOrder(t, repos, user.ID, "12345678903"), 100)
+	createOrder(t, repos, user.ID, "9278923470") // не обработан и не учитывается
+
+	order, err := repos.Order.OrderFindByNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	require.NoError(t, repos.Order.OrderAdjustmentCreate(ctx, domain.OrderAdjustment{
+		OrderID: order.ID, UserID: user.ID, Amount: decimal.NewFromInt(-10), BaseAccrual: decimal.NewFromInt(90),
+	}))
+
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "2377225624", Amount: decimal.NewFromInt(30)}))
+	wd, err := repos.Withdrawal.WithdrawalFindByOrderNumber(ctx, "2377225624")
+	require.NoError(t, err)
+	wd.Refunded = decimal.NewFromInt(5)
+	wd.Status = domain.WithdrawalStatusPartiallyRefunded
+	require.NoError(t, repos.Withdrawal.WithdrawalUpdate(ctx, *wd))
+
+	require.NoError(t, repos.Transfer.TransferCreate(ctx, domain.Transfer{SenderID: user.ID, RecipientID: mom.ID, Amount: decimal.NewFromInt(20)}))
+
+	require.NoError(t, repos.User.UserUpdateBalanceAndWithdrawals(ctx, user.ID))
+	require.NoError(t, repos.User.UserUpdateBalanceAndWithdrawals(ctx, mom.ID))
+
+	balance, withdrawn, err := repos.User.UserGetBalance(ctx, user.ID)
+	require.NoError(t, err)
+	assertDecimal(t, 45, *balance) // 100 - 10 - (30 - 5) - 20
+	assertDecimal(t, 25, *withdrawn)
+
+	balance, withdrawn, err = repos.User.UserGetBalance(ctx, mom.ID)
+	require.NoError(t, err)
+	assertDecimal(t, 20, *balance)
+	assertDecimal(t, 0, *withdrawn)
+}
+
+func testOrderCreateAndFind(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+
+	order, err := repos.Order.OrderCreate(ctx, domain.Order{
+		UserID:    user.ID,
+		Number:    "12345678903",
+		Status:    domain.OrderStatusNew,
+		RequestID: "rid",
+		Engine:    domain.AccrualEngineInternal,
+		Purchase: &domain.Purchase{
+			Store: "shop",
+			Items: []domain.PurchaseItem{{Product: "tea", Category: "food", Price: decimal.NewFromInt(50), Quantity: 2}},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, order.ID)
+	assert.Equal(t, domain.OrderStatusNew, order.Status)
+
+	_, err = repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew})
+	assert.Error(t, err, "order number must be unique")
+
+	found, err := repos.Order.OrderFindByNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, order.ID, found.ID)
+	assert.Equal(t, user.ID, found.UserID)
+	assert.Equal(t, domain.AccrualEngineInternal, found.Engine)
+
+	_, err = repos.Order.OrderFindByNumber(ctx, "0")
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+
+	purchase, err := repos.Order.OrderPurchaseFindByNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, "shop", purchase.Store)
+	require.Len(t, purchase.Items, 1)
+	assertDecimal(t, 100, purchase.Total())
+
+	// вместе с заказом создается задача опроса начислений
+	job, err := repos.AccrualJob.AccrualJobFindByOrderNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, order.ID, job.OrderID)
+	assert.Equal(t, "rid", job.RequestID)
+	assert.Equal(t, "12345678903", job.Order.Number)
+	assert.Equal(t, "shop", job.Order.Store)
+}
+
+func testOrderList(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+	other := createUser(t, repos, "other")
+
+	first := createOrder(t, repos, user.ID, "12345678903")
+	createOrder(t, repos, user.ID, "9278923470")
+	createOrder(t, repos, other.ID, "2377225624")
+
+	processOrder(t, repos, first, 100)
+	require.NoError(t, repos.Order.OrderAdjustmentCreate(ctx, domain.OrderAdjustment{
+		OrderID: first.ID, UserID: user.ID, Amount: decimal.NewFromInt(20), BaseAccrual: decimal.NewFromInt(120),
+	}))
+
+	orders, err := repos.Order.OrderList(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+
+	// от новых к старым, начисление с учетом корректировок
+	assert.Equal(t, "9278923470", orders[0].Number)
+	assert.Equal(t, "12345678903", orders[1].Number)
+	assert.Equal(t, domain.OrderStatusProcessed, orders[1].Status)
+	assertDecimal(t, 120, orders[1].Accrual)
+}
+
+func testOrderReview(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+	order := createOrder(t, repos, user.ID, "12345678903")
+	createOrder(t, repos, user.ID, "9278923470")
+
+	err := repos.Order.OrderMarkForReview(ctx, domain.Order{ID: order.ID, ReviewAccrual: decimal.NewFromInt(5000), ReviewReason: "too much"})
+	require.NoError(t, err)
+
+	orders, err := repos.Order.OrderListForReview(ctx)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, order.ID, orders[0].ID)
+	assert.Equal(t, domain.OrderStatusNeedsReview, orders[0].Status)
+	assert.Equal(t, "too much", orders[0].ReviewReason)
+	assertDecimal(t, 5000, orders[0].ReviewAccrual)
+}
+
+func testOrderAccruals(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+	order := createOrder(t, repos, user.ID, "12345678903")
+
+	err := repos.Order.OrderUpdate(ctx, domain.Order{
+		ID: order.ID, Status: domain.OrderStatusProcessed, Accrual: decimal.NewFromInt(150), BaseAccrual: decimal.NewFromInt(100),
+	})
+	require.NoError(t, err)
+
+	accrual, base, err := repos.Order.OrderAccrualCurrent(ctx, order.ID)
+	require.NoError(t, err)
+	assertDecimal(t, 150, accrual)
+	assertDecimal(t, 100, base)
+
+	require.NoError(t, repos.Order.OrderAdjustmentCreate(ctx, domain.OrderAdjustment{
+		OrderID: order.ID, UserID: user.ID, Amount: decimal.NewFromInt(-30), BaseAccrual: decimal.NewFromInt(80),
+	}))
+
+	accrual, base, err = repos.Order.OrderAccrualCurrent(ctx, order.ID)
+	require.NoError(t, err)
+	assertDecimal(t, 120, accrual)
+	assertDecimal(t, 80, base)
+
+	// уровень считается по начислению системы без множителя: 100 и исправление до 80
+	sum, err := repos.Order.OrderAccrualSumSince(ctx, user.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assertDecimal(t, 80, sum)
+
+	require.NoError(t, repos.Order.OrderAdjustmentCreate(ctx, domain.OrderAdjustment{
+		OrderID: order.ID, UserID: user.ID, Amount: decimal.NewFromInt(15), BaseAccrual: decimal.NewFromInt(90),
+	}))
+
+	sum, err = repos.Order.OrderAccrualSumSince(ctx, user.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assertDecimal(t, 90, sum)
+
+	sum, err = repos.Order.OrderAccrualSumSince(ctx, user.ID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assertDecimal(t, 0, sum)
+
+	_, _, err = repos.Order.OrderAccrualCurrent(ctx, order.ID+100)
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+}
+
+func testWithdrawalCreateAndFind(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromInt(30)}))
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "9278923470", Amount: decimal.NewFromInt(20)}))
+
+	err := repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromInt(1)})
+	assert.Error(t, err, "withdrawal order number must be unique")
+
+	wd, err := repos.Withdrawal.WithdrawalFindByOrderNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, wd.UserID)
+	assert.Equal(t, domain.WithdrawalStatusWithdrawn, wd.Status)
+	assertDecimal(t, 30, wd.Amount)
+	assertDecimal(t, 0, wd.Refunded)
+
+	_, err = repos.Withdrawal.WithdrawalFindByOrderNumber(ctx, "0")
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+
+	wds, err := repos.Withdrawal.WithdrawalList(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, wds, 2)
+	assert.Equal(t, "9278923470", wds[0].OrderNumber)
+
+	sum, err := repos.Withdrawal.WithdrawalSumSince(ctx, user.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assertDecimal(t, 50, sum)
+}
+
+func testWithdrawalRefund(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "12345678903", Amount: decimal.NewFromInt(30)}))
+	wd, err := repos.Withdrawal.WithdrawalFindByOrderNumber(ctx, "12345678903")
+	require.NoError(t, err)
+
+	require.NoError(t, repos.Withdrawal.WithdrawalRefundCreate(ctx, domain.WithdrawalRefund{WithdrawalID: wd.ID, UserID: user.ID, Amount: decimal.NewFromInt(30)}))
+
+	wd.Refunded = decimal.NewFromInt(30)
+	wd.Status = domain.WithdrawalStatusRefunded
+	require.NoError(t, repos.Withdrawal.WithdrawalUpdate(ctx, *wd))
+
+	wd, err = repos.Withdrawal.WithdrawalFindByOrderNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	assert.Equal(t, domain.WithdrawalStatusRefunded, wd.Status)
+	assertDecimal(t, 0, wd.Refundable())
+
+	// возвращенное списание не расходует лимиты
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "9278923470", Amount: decimal.NewFromInt(20)}))
+
+	sum, err := repos.Withdrawal.WithdrawalSumSince(ctx, user.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assertDecimal(t, 20, sum)
+}
+
+func testTransferList(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	me := createUser(t, repos, "me")
+	mom := createUser(t, repos, "mom")
+
+	require.NoError(t, repos.Transfer.TransferCreate(ctx, domain.Transfer{SenderID: me.ID, RecipientID: mom.ID, Amount: decimal.NewFromInt(10)}))
+	require.NoError(t, repos.Transfer.TransferCreate(ctx, domain.Transfer{SenderID: mom.ID, RecipientID: me.ID, Amount: decimal.NewFromInt(5)}))
+
+	err := repos.Transfer.TransferCreate(ctx, domain.Transfer{SenderID: me.ID, RecipientID: mom.ID, Amount: decimal.Zero})
+	assert.Error(t, err, "transfer amount must be positive")
+
+	transfers, err := repos.Transfer.TransferList(ctx, me.ID)
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+	assert.Equal(t, "mom", transfers[0].SenderLogin)
+	assert.Equal(t, "me", transfers[0].RecipientLogin)
+
+	sum, count, err := repos.Transfer.TransferStatsSince(ctx, me.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assertDecimal(t, 10, sum)
+	assert.Equal(t, 1, count)
+}
+
+func testStatement(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+	period := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := repos.Statement.StatementFind(ctx, user.ID, period)
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+
+	st := domain.Statement{UserID: user.ID, Period: period, OpeningBalance: decimal.NewFromInt(10), ClosingBalance: decimal.NewFromInt(20)}
+	require.NoError(t, repos.Statement.StatementCreate(ctx, st))
+
+	// существующая выписка не перезаписывается
+	st.ClosingBalance = decimal.NewFromInt(99)
+	require.NoError(t, repos.Statement.StatementCreate(ctx, st))
+
+	found, err := repos.Statement.StatementFind(ctx, user.ID, period)
+	require.NoError(t, err)
+	assertDecimal(t, 20, found.ClosingBalance)
+
+	processOrder(t, repos, createOrder(t, repos, user.ID, "12345678903"), 100)
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "9278923470", Amount: decimal.NewFromInt(30)}))
+
+	turnover, err := repos.Statement.StatementTurnover(ctx, user.ID, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assertDecimal(t, 100, turnover.Accruals)
+	assertDecimal(t, 30, turnover.Withdrawals)
+	assertDecimal(t, 70, turnover.Turnover())
+}
+
+func testAccrualJobLifecycle(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+	order := createOrder(t, repos, user.ID, "12345678903")
+
+	jobs, err := repos.AccrualJob.AccrualJobClaimDue(ctx, "a", time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "a", jobs[0].LeaseOwner)
+
+	// задача в аренде у другого экземпляра не захватывается
+	_, err = repos.AccrualJob.AccrualJobClaim(ctx, "b", time.Minute, order.ID)
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+
+	// чужой экземпляр не может отложить задачу
+	job := *jobs[0]
+	job.LeaseOwner = "b"
+	job.Attempts = 5
+	require.NoError(t, repos.AccrualJob.AccrualJobReschedule(ctx, job, 0))
+
+	job.LeaseOwner = "a"
+	job.Attempts = 1
+	job.LastError = "timeout"
+	require.NoError(t, repos.AccrualJob.AccrualJobReschedule(ctx, job, 0))
+
+	claimed, err := repos.AccrualJob.AccrualJobClaim(ctx, "b", time.Minute, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed.Attempts)
+	assert.Equal(t, "timeout", claimed.LastError)
+
+	require.NoError(t, repos.AccrualJob.AccrualJobMarkDead(ctx, *claimed))
+
+	dead, err := repos.AccrualJob.AccrualJobListDead(ctx)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.True(t, dead[0].IsDead())
+
+	jobs, err = repos.AccrualJob.AccrualJobClaimDue(ctx, "a", time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	require.NoError(t, repos.AccrualJob.AccrualJobRequeue(ctx, order.ID))
+	assert.ErrorIs(t, repos.AccrualJob.AccrualJobRequeue(ctx, order.ID), storage.ErrRecordNotFound)
+
+	claimed, err = repos.AccrualJob.AccrualJobClaim(ctx, "a", time.Minute, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, claimed.Attempts)
+
+	require.NoError(t, repos.AccrualJob.AccrualJobReleaseLeases(ctx, "a", []string{"12345678903"}))
+	require.NoError(t, repos.AccrualJob.AccrualJobStartCorrection(ctx, order.ID, time.Hour, time.Hour))
+
+	job2, err := repos.AccrualJob.AccrualJobFindByOrderNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	assert.True(t, job2.IsCorrection())
+	assert.Empty(t, job2.LeaseOwner)
+
+	require.NoError(t, repos.AccrualJob.AccrualJobDelete(ctx, order.ID))
+	_, err = repos.AccrualJob.AccrualJobFindByOrderNumber(ctx, "12345678903")
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+}
+
+func testAccrualCalls(t *testing.T, _ storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+
+	for _, status := range []int{500, 200} {
+		err := repos.AccrualCall.AccrualCallCreate(ctx, domain.AccrualCall{
+			OrderNumber: "12345678903", Provider: "default", URL: "http://accrual", HTTPStatus: status, Latency: time.Second,
+		})
+		require.NoError(t, err)
+	}
+
+	calls, err := repos.AccrualCall.AccrualCallListByOrderNumber(ctx, "12345678903")
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, 500, calls[0].HTTPStatus)
+	assert.Equal(t, time.Second, calls[1].Latency)
+
+	deleted, err := repos.AccrualCall.AccrualCallDeleteOlderThan(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+// изменения транзакции, завершившейся ошибкой, не сохраняются
+func testTxRollback(t *testing.T, s storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+	expectedErr := errors.New("rollback")
+
+	err := s.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		if _, err := repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew}); err != nil {
+			return err
+		}
+		return expectedErr
+	})
+	assert.ErrorIs(t, err, expectedErr)
+
+	_, err = repos.Order.OrderFindByNumber(ctx, "12345678903")
+	assert.ErrorIs(t, err, storage.ErrRecordNotFound)
+
+	err = s.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		_, err := repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew})
+		return err
+	})
+	require.NoError(t, err)
+
+	order, err := repos.Order.OrderFindByNumber(ctx, "12345678903")
+	require.NoError(t, err)
+
+	job, err := repos.AccrualJob.AccrualJobClaim(ctx, "a", time.Minute, order.ID)
+	require.NoError(t, err)
+
+	err = s.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		if err := repos.AccrualJob.AccrualJobMarkDead(ctx, *job); err != nil {
+			return err
+		}
+		return expectedErr
+	})
+	assert.ErrorIs(t, err, expectedErr)
+
+	dead, err := repos.AccrualJob.AccrualJobListDead(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+}
+
+// параллельные списания в транзакциях не уводят баланс в минус
+func testTxConcurrentWithdrawals(t *testing.T, s storage.IStorage, repos *repository.Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, "me")
+
+	processOrder(t, repos, createOrder(t, repos, user.ID, "12345678903"), 100)
+	require.NoError(t, repos.User.UserUpdateBalanceAndWithdrawals(ctx, user.ID))
+
+	numbers := []string{"9278923470", "2377225624", "79927398713", "4561261212345467", "1234567812345670"}
+	errInsufficient := errors.New("insufficient balance")
+
+	var wg sync.WaitGroup
+	results := make(chan error, len(numbers))
+	for _, number := range numbers {
+		wg.Add(1)
+		go func(number string) {
+			defer wg.Done()
+
+			results <- s.WithinTx(ctx, storage.DefaultTxOptions, func(ctx context.Context) error {
+				balance, _, err := repos.User.UserGetBalance(ctx, user.ID)
+				if err != nil {
+					return err
+				}
+				if balance.LessThan(decimal.NewFromInt(30)) {
+					return errInsufficient
+				}
+
+				err = repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: number, Amount: decimal.NewFromInt(30)})
+				if err != nil {
+					return err
+				}
+
+				return repos.User.UserUpdateBalanceAndWithdrawals(ctx, user.ID)
+			})
+		}(number)
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, errInsufficient)
+		}
+	}
+	assert.Equal(t, 3, succeeded)
+
+	balance, withdrawn, err := repos.User.UserGetBalance(ctx, user.ID)
+	require.NoError(t, err)
+	assertDecimal(t, 10, *balance)
+	assertDecimal(t, 90, *withdrawn)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/jackc/pgx/v5"
+)
+
+type IStatementRepository interface {
+	StatementFind(ctx context.Context, userID domain.UserID, period time.Time) (*domain.Statement, error)
+	StatementCreate(ctx context.Context, s domain.Statement) error
+	StatementTurnover(ctx context.Context, userID domain.UserID, from time.Time, to time.Time) (*domain.Statement, error)
+}
+
+type statementRepository struct {
+	pool storage.IPGXPool
+}
+
+func NewStatementRepository(pool storage.IPGXPool) IStatementRepository {
+	return &statementRepository{pool: pool}
+}
+
+func (repo *statementRepository) StatementFind(ctx context.Context, userID domain.UserID, period time.Time) (*domain.Statement, error) {
+	stmt := `
+	SELECT id, user_id, period, opening_balance, accruals, withdrawals, refunds,
+		transfers_in, transfers_out, expirations, closing_balance, created_at
+	FROM statements WHERE user_id = $1 AND period = $2`
+
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, userID, period)
+
+	s := new(domain.Statement)
+	err := row.Scan(
+		&s.ID, &s.UserID, &s.Period, &s.OpeningBalance, &s.Accruals, &s.Withdrawals, &s.Refunds,
+		&s.TransfersIn, &s.TransfersOut, &s.Expirations, &s.ClosingBalance, &s.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("statementRepository -> StatementFind() error: %w", err)
+	}
+
+	return s, nil
+}
+
+// сохраняет выписку; уже существующая выписка за период не перезаписывается
+func (repo *statementRepository) StatementCreate(ctx context.Context, s domain.Statement) error {
+	stmt := `
+	INSERT INTO statements (user_id, period, opening_balance, accruals, withdrawals, refunds,
+		transfers_in, transfers_out, expirations, closing_balance)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (user_id, period) DO NOTHING`
+
+	args := []any{
+		s.UserID, s.Period, s.OpeningBalance, s.Accruals, s.Withdrawals, s.Refunds,
+		s.TransfersIn, s.TransfersOut, s.Expirations, s.ClosingBalance,
+	}
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("statementRepository -> StatementCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// обороты пользователя за полуинтервал [from, to); заполняются только суммы операций,
+// начисление относится к моменту обработки заказа, корректировка - к моменту проводки
+func (repo *statementRepository) StatementTurnover(ctx context.Context, userID domain.UserID, from time.Time, to time.Time) (*domain.Statement, error) {
+	stmt := `
+	SELECT
+		(SELECT COALESCE(SUM(accrual), 0) FROM orders
+			WHERE user_id = $1 AND status = 'PROCESSED' AND updated_at >= $2 AND updated_at < $3) +
+		(SELECT COALESCE(SUM(amount), 0) FROM order_adjustments
+			WHERE user_id = $1 AND created_at >= $2 AND created_at < $3),
+		(SELECT COALESCE(SUM(amount), 0) FROM withdrawals
+			WHERE user_id = $1 AND created_at >= $2 AND created_at < $3),
+		(SELECT COALESCE(SUM(amount), 0) FROM withdrawal_refunds
+			WHERE user_id = $1 AND created_at >= $2 AND created_at < $3),
+		(SELECT COALESCE(SUM(amount), 0) FROM transfers
+			WHERE recipient_id = $1 AND created_at >= $2 AND created_at < $3),
+		(SELECT COALESCE(SUM(amount), 0) FROM transfers
+			WHERE sender_id = $1 AND created_at >= $2 AND created_at < $3)`
+
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, userID, from, to)
+
+	s := &domain.Statement{UserID: userID}
+	if err := row.Scan(&s.Accruals, &s.Withdrawals, &s.Refunds, &s.TransfersIn, &s.TransfersOut); err != nil {
+		return nil, fmt.Errorf("statementRepository -> StatementTurnover() error: %w", err)
+	}
+
+	return s, nil
+}
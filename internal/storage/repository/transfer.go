@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type ITransferRepository interface {
+	TransferCreate(ctx context.Context, t domain.Transfer) error
+	TransferList(ctx context.Context, userID domain.UserID) ([]*domain.Transfer, error)
+	TransferStatsSince(ctx context.Context, senderID domain.UserID, since time.Time) (decimal.Decimal, int, error)
+}
+
+type transferRepository struct {
+	pool storage.IPGXPool
+}
+
+func NewTransferRepository(pool storage.IPGXPool) ITransferRepository {
+	return &transferRepository{pool: pool}
+}
+
+func (repo *transferRepository) TransferCreate(ctx context.Context, t domain.Transfer) error {
+	stmt := `INSERT INTO transfers (sender_id, recipient_id, amount) VALUES ($1, $2, $3)`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, t.SenderID, t.RecipientID, t.Amount)
+	if err != nil {
+		return fmt.Errorf("transferRepository -> TransferCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// входящие и исходящие переводы пользователя
+func (repo *transferRepository) TransferList(ctx context.Context, userID domain.UserID) ([]*domain.Transfer, error) {
+	stmt := `
+	SELECT t.sender_id, s.login, t.recipient_id, r.login, t.amount, t.created_at
+	FROM transfers t
+	JOIN users s ON s.id = t.sender_id
+	JOIN users r ON r.id = t.recipient_id
+	WHERE t.sender_id = $1 OR t.recipient_id = $1
+	ORDER BY t.created_at DESC`
+	transfers := make([]*domain.Transfer, 0)
+
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, stmt, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+
+		return nil, fmt.Errorf("transferRepository -> TransferList() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &domain.Transfer{}
+		if err = rows.Scan(&t.SenderID, &t.SenderLogin, &t.RecipientID, &t.RecipientLogin, &t.Amount, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("transferRepository -> TransferList() error: %w", err)
+		}
+		transfers = append(transfers, t)
+	}
+
+	return transfers, nil
+}
+
+// сумма и количество исходящих переводов пользователя начиная с since;
+// created_at записан без пояса во времени сессии, поэтому since переводится в тот же пояс
+func (repo *transferRepository) TransferStatsSince(ctx context.Context, senderID domain.UserID, since time.Time) (decimal.Decimal, int, error) {
+	stmt := `
+	SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM transfers
+	WHERE sender_id = $1 AND created_at >= $2::timestamptz AT TIME ZONE current_setting('TimeZone')`
+
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, senderID, since)
+
+	var sum decimal.Decimal
+	var count int
+	if err := row.Scan(&sum, &count); err != nil {
+		return decimal.Zero, 0, fmt.Errorf("transferRepository -> TransferStatsSince() error: %w", err)
+	}
+
+	return sum, count, nil
+}
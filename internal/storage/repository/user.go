@@ -14,8 +14,11 @@ import (
 type IUserRepository interface {
 	UserCreate(ctx context.Context, login string, password string) (*domain.User, error)
 	UserFindByLogin(ctx context.Context, login string) (*domain.User, error)
-	UserGetBalance(ctx context.Context, tx pgx.Tx, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error)
-	UserUpdateBalanceAndWithdrawals(ctx context.Context, tx pgx.Tx, id domain.UserID) error
+	UserGetBalance(ctx context.Context, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error)
+	UserUpdateBalanceAndWithdrawals(ctx context.Context, id domain.UserID) error
+	UserGetTier(ctx context.Context, id domain.UserID) (domain.LoyaltyTier, error)
+	UserUpdateTier(ctx context.Context, id domain.UserID, tier domain.LoyaltyTier) error
+	UserUpdatePassword(ctx context.Context, id domain.UserID, password string) error
 }
 
 type userRepository struct {
@@ -29,7 +32,7 @@ func NewUserRepository(pool storage.IPGXPool) IUserRepository {
 func (repo *userRepository) UserCreate(ctx context.Context, login string, password string) (*domain.User, error) {
 	stmt := `INSERT INTO users (login, password) VALUES ($1, $2) RETURNING id, login, balance, created_at, updated_at`
 
-	rows, err := repo.pool.Query(ctx, stmt, login, password)
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, stmt, login, password)
 	if err != nil {
 		return nil, err
 	}
@@ -51,12 +54,13 @@ func (repo *userRepository) UserCreate(ctx context.Context, login string, passwo
 }
 
 func (repo *userRepository) UserFindByLogin(ctx context.Context, login string) (*domain.User, error) {
-	stmt := `SELECT id, login, password, balance, created_at, updated_at FROM users WHERE login = $1`
+	stmt := `SELECT id, login, password, balance, tier, created_at, updated_at, password_changed_at FROM users WHERE login = $1`
 	user := new(domain.User)
 
-	err := repo.pool.QueryRow(ctx, stmt, login).Scan(
+	err := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, login).Scan(
 		&user.ID, &user.Login, &user.Password,
-		&user.Balance, &user.CreatedAt, &user.UpdatedAt,
+		&user.Balance, &user.Tier, &user.CreatedAt, &user.UpdatedAt,
+		&user.PasswordChangedAt,
 	)
 
 	if err != nil {
@@ -69,15 +73,10 @@ func (repo *userRepository) UserFindByLogin(ctx context.Context, login string) (
 	return user, nil
 }
 
-func (repo *userRepository) UserGetBalance(ctx context.Context, tx pgx.Tx, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error) {
+func (repo *userRepository) UserGetBalance(ctx context.Context, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error) {
 	stmt := `SELECT balance, withdrawn FROM users WHERE id = $1 FOR UPDATE`
 
-	var row pgx.Row
-	if tx != nil {
-		row = tx.QueryRow(ctx, stmt, id)
-	} else {
-		row = repo.pool.QueryRow(ctx, stmt, id)
-	}
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, id)
 
 	var b, w decimal.Decimal
 	err := row.Scan(&b, &w)
@@ -92,38 +91,85 @@ func (repo *userRepository) UserGetBalance(ctx context.Context, tx pgx.Tx, id do
 	return &b, &w, nil
 }
 
-func (repo *userRepository) UserUpdateBalanceAndWithdrawals(ctx context.Context, tx pgx.Tx, id domain.UserID) error {
+func (repo *userRepository) UserUpdateBalanceAndWithdrawals(ctx context.Context, id domain.UserID) error {
 	stmt := `
 	WITH
-    	accruals AS (        
-			SELECT o.user_id, COALESCE(SUM(o.accrual), 0) AS total_accrual
-        	FROM orders o
-        	WHERE o.status = 'PROCESSED'
-        	GROUP BY o.user_id),
-    	withdrawals AS (
-        	SELECT w.user_id, COALESCE(SUM(w.amount), 0) AS total_withdrawn
-        	FROM withdrawals w       
-        	GROUP BY w.user_id)
+		accruals AS (
+			SELECT COALESCE(SUM(o.accrual), 0) AS total
+			FROM orders o
+			WHERE o.user_id = $1 AND o.status = 'PROCESSED'),
+		adjustments AS (
+			SELECT COALESCE(SUM(a.amount), 0) AS total
+			FROM order_adjustments a
+			WHERE a.user_id = $1),
+		withdrawals AS (
+			SELECT COALESCE(SUM(w.amount - w.refunded), 0) AS total
+			FROM withdrawals w
+			WHERE w.user_id = $1),
+		transfers_out AS (
+			SELECT COALESCE(SUM(t.amount), 0) AS total
+			FROM transfers t
+			WHERE t.sender_id = $1),
+		transfers_in AS (
+			SELECT COALESCE(SUM(t.amount), 0) AS total
+			FROM transfers t
+			WHERE t.recipient_id = $1)
 	UPDATE users u
 	SET
-    	balance = COALESCE(a.total_accrual, 0) - COALESCE(w.total_withdrawn, 0),
-    	withdrawn = COALESCE(w.total_withdrawn, 0)
+		balance = a.total + adj.total - w.total - t_out.total + t_in.total,
+		withdrawn = w.total
 	FROM
-    	accruals a
-    LEFT JOIN 
-		withdrawals w ON a.user_id = w.user_id
+		accruals a, adjustments adj, withdrawals w, transfers_out t_out, transfers_in t_in
 	WHERE
-    	u.id = $1 AND a.user_id = $1`
+		u.id = $1`
 
-	var err error
-	if tx != nil {
-		_, err = tx.Exec(ctx, stmt, id)
-	} else {
-		_, err = repo.pool.Exec(ctx, stmt, id)
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, id)
+	if err != nil {
+		return fmt.Errorf("userRepository -> UserUpdateBalanceAndWithdrawals() error: %w", err)
 	}
 
+	return nil
+}
+
+// в транзакции строка пользователя блокируется до её завершения
+func (repo *userRepository) UserGetTier(ctx context.Context, id domain.UserID) (domain.LoyaltyTier, error) {
+	stmt := `SELECT tier FROM users WHERE id = $1`
+
+	if storage.HasTx(ctx) {
+		stmt += " FOR UPDATE"
+	}
+
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, id)
+
+	var tier domain.LoyaltyTier
+	if err := row.Scan(&tier); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", storage.ErrRecordNotFound
+		}
+		return "", fmt.Errorf("userRepository -> UserGetTier() error: %w", err)
+	}
+
+	return tier, nil
+}
+
+func (repo *userRepository) UserUpdateTier(ctx context.Context, id domain.UserID, tier domain.LoyaltyTier) error {
+	stmt := `UPDATE users SET tier = $1, updated_at = now() WHERE id = $2`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, tier, id)
 	if err != nil {
-		return fmt.Errorf("userRepository -> UserUpdateBalanceAndWithdrawals() error: %w", err)
+		return fmt.Errorf("userRepository -> UserUpdateTier() error: %w", err)
+	}
+
+	return nil
+}
+
+// вместе с паролем запоминается время смены, от которого отсчитывается запрет списаний
+func (repo *userRepository) UserUpdatePassword(ctx context.Context, id domain.UserID, password string) error {
+	stmt := `UPDATE users SET password = $1, password_changed_at = now(), updated_at = now() WHERE id = $2`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, password, id)
+	if err != nil {
+		return fmt.Errorf("userRepository -> UserUpdatePassword() error: %w", err)
 	}
 
 	return nil
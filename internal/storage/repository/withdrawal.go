@@ -4,15 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ex0rcist/gophermart/internal/domain"
 	"github.com/ex0rcist/gophermart/internal/storage"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
 type IWithdrawalRepository interface {
-	WithdrawalCreate(ctx context.Context, tx pgx.Tx, w domain.Withdrawal) error
+	WithdrawalCreate(ctx context.Context, w domain.Withdrawal) error
+	WithdrawalFindByOrderNumber(ctx context.Context, number string) (*domain.Withdrawal, error)
 	WithdrawalList(ctx context.Context, userID domain.UserID) ([]*domain.Withdrawal, error)
+	WithdrawalUpdate(ctx context.Context, w domain.Withdrawal) error
+	WithdrawalRefundCreate(ctx context.Context, r domain.WithdrawalRefund) error
+	WithdrawalSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error)
 }
 
 type withdrawalRepository struct {
@@ -23,15 +29,10 @@ func NewWithdrawalRepository(pool storage.IPGXPool) IWithdrawalRepository {
 	return &withdrawalRepository{pool: pool}
 }
 
-func (repo *withdrawalRepository) WithdrawalCreate(ctx context.Context, tx pgx.Tx, w domain.Withdrawal) error {
+func (repo *withdrawalRepository) WithdrawalCreate(ctx context.Context, w domain.Withdrawal) error {
 	stmt := `INSERT INTO withdrawals (user_id, order_number, amount) VALUES ($1, $2, $3)`
 
-	var err error
-	if tx != nil {
-		_, err = tx.Exec(ctx, stmt, w.UserID, w.OrderNumber, w.Amount)
-	} else {
-		_, err = repo.pool.Exec(ctx, stmt, w.UserID, w.OrderNumber, w.Amount)
-	}
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, w.UserID, w.OrderNumber, w.Amount)
 	if err != nil {
 		return fmt.Errorf("withdrawalRepository -> WithdrawalCreate() error: %w", err)
 	}
@@ -39,11 +40,33 @@ func (repo *withdrawalRepository) WithdrawalCreate(ctx context.Context, tx pgx.T
 	return nil
 }
 
+// в транзакции строка списания блокируется до её завершения
+func (repo *withdrawalRepository) WithdrawalFindByOrderNumber(ctx context.Context, number string) (*domain.Withdrawal, error) {
+	stmt := `SELECT id, user_id, order_number, amount, refunded, status, created_at FROM withdrawals WHERE order_number = $1`
+
+	if storage.HasTx(ctx) {
+		stmt += " FOR UPDATE"
+	}
+
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, number)
+
+	wd := new(domain.Withdrawal)
+	err := row.Scan(&wd.ID, &wd.UserID, &wd.OrderNumber, &wd.Amount, &wd.Refunded, &wd.Status, &wd.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("withdrawalRepository -> WithdrawalFindByOrderNumber() error: %w", err)
+	}
+
+	return wd, nil
+}
+
 func (repo *withdrawalRepository) WithdrawalList(ctx context.Context, userID domain.UserID) ([]*domain.Withdrawal, error) {
-	stmt := `SELECT order_number, amount, created_at FROM withdrawals WHERE user_id = $1 ORDER BY created_at DESC`
+	stmt := `SELECT order_number, amount, refunded, status, created_at FROM withdrawals WHERE user_id = $1 ORDER BY created_at DESC`
 	wds := make([]*domain.Withdrawal, 0)
 
-	rows, err := repo.pool.Query(ctx, stmt, userID)
+	rows, err := storage.Querier(ctx, repo.pool).Query(ctx, stmt, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, storage.ErrRecordNotFound
@@ -55,7 +78,7 @@ func (repo *withdrawalRepository) WithdrawalList(ctx context.Context, userID dom
 
 	for rows.Next() {
 		wd := &domain.Withdrawal{}
-		if err = rows.Scan(&wd.OrderNumber, &wd.Amount, &wd.CreatedAt); err != nil {
+		if err = rows.Scan(&wd.OrderNumber, &wd.Amount, &wd.Refunded, &wd.Status, &wd.CreatedAt); err != nil {
 			return nil, fmt.Errorf("withdrawalRepository -> WithdrawalList() error: %w", err)
 		}
 		wds = append(wds, wd)
@@ -63,3 +86,42 @@ func (repo *withdrawalRepository) WithdrawalList(ctx context.Context, userID dom
 
 	return wds, nil
 }
+
+func (repo *withdrawalRepository) WithdrawalUpdate(ctx context.Context, w domain.Withdrawal) error {
+	stmt := `UPDATE withdrawals SET refunded = $1, status = $2 WHERE id = $3`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, w.Refunded, w.Status, w.ID)
+	if err != nil {
+		return fmt.Errorf("withdrawalRepository -> WithdrawalUpdate() error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *withdrawalRepository) WithdrawalRefundCreate(ctx context.Context, r domain.WithdrawalRefund) error {
+	stmt := `INSERT INTO withdrawal_refunds (withdrawal_id, user_id, amount) VALUES ($1, $2, $3)`
+
+	_, err := storage.Querier(ctx, repo.pool).Exec(ctx, stmt, r.WithdrawalID, r.UserID, r.Amount)
+	if err != nil {
+		return fmt.Errorf("withdrawalRepository -> WithdrawalRefundCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// сумма списаний пользователя начиная с since за вычетом возвратов, как и в балансе;
+// since сравнивается с created_at в часовом поясе сессии
+func (repo *withdrawalRepository) WithdrawalSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	stmt := `
+	SELECT COALESCE(SUM(amount - refunded), 0) FROM withdrawals
+	WHERE user_id = $1 AND created_at >= $2::timestamptz AT TIME ZONE current_setting('TimeZone')`
+
+	row := storage.Querier(ctx, repo.pool).QueryRow(ctx, stmt, userID, since)
+
+	var sum decimal.Decimal
+	if err := row.Scan(&sum); err != nil {
+		return decimal.Zero, fmt.Errorf("withdrawalRepository -> WithdrawalSumSince() error: %w", err)
+	}
+
+	return sum, nil
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type accrualCallRepository struct {
+	storage *Storage
+}
+
+func NewAccrualCallRepository(s *Storage) repository.IAccrualCallRepository {
+	return &accrualCallRepository{storage: s}
+}
+
+func (repo *accrualCallRepository) AccrualCallCreate(ctx context.Context, call domain.AccrualCall) error {
+	stmt := `
+	INSERT INTO accrual_calls (order_number, provider, url, http_status, response_body, error, latency_ms, request_id, created_at)
+	VALUES (?, ?, ?, NULLIF(?, 0), NULLIF(?, ''), NULLIF(?, ''), ?, NULLIF(?, ''), ?)`
+
+	_, err := repo.storage.querier(ctx).ExecContext(
+		ctx, stmt,
+		call.OrderNumber, call.Provider, call.URL, call.HTTPStatus, call.ResponseBody, call.Error, call.Latency.Milliseconds(),
+		call.RequestID, now(),
+	)
+	if err != nil {
+		return fmt.Errorf("accrualCallRepository -> AccrualCallCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// обращения по заказу в хронологическом порядке
+func (repo *accrualCallRepository) AccrualCallListByOrderNumber(ctx context.Context, number string) ([]*domain.AccrualCall, error) {
+	stmt := `
+	SELECT id, order_number, provider, url, COALESCE(http_status, 0), COALESCE(response_body, ''),
+		COALESCE(error, ''), latency_ms, COALESCE(request_id, ''), created_at
+	FROM accrual_calls WHERE order_number = ? ORDER BY created_at, id`
+
+	calls := make([]*domain.AccrualCall, 0)
+
+	rows, err := repo.storage.querier(ctx).QueryContext(ctx, stmt, number)
+	if err != nil {
+		return nil, fmt.Errorf("accrualCallRepository -> AccrualCallListByOrderNumber() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		call := &domain.AccrualCall{}
+
+		var latencyMs int64
+		err = rows.Scan(
+			&call.ID, &call.OrderNumber, &call.Provider, &call.URL, &call.HTTPStatus, &call.ResponseBody,
+			&call.Error, &latencyMs, &call.RequestID, &call.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("accrualCallRepository -> AccrualCallListByOrderNumber() error: %w", err)
+		}
+
+		call.Latency = time.Duration(latencyMs) * time.Millisecond
+		calls = append(calls, call)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("accrualCallRepository -> AccrualCallListByOrderNumber() error: %w", err)
+	}
+
+	return calls, nil
+}
+
+// удаляет обращения старше age, возвращает число удаленных
+func (repo *accrualCallRepository) AccrualCallDeleteOlderThan(ctx context.Context, age time.Duration) (int64, error) {
+	stmt := `DELETE FROM accrual_calls WHERE created_at < ?`
+
+	res, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, now().Add(-age))
+	if err != nil {
+		return 0, fmt.Errorf("accrualCallRepository -> AccrualCallDeleteOlderThan() error: %w", err)
+	}
+
+	return res.RowsAffected()
+}
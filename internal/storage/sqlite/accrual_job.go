@@ -0,0 +1,304 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type accrualJobRepository struct {
+	storage *Storage
+}
+
+func NewAccrualJobRepository(s *Storage) repository.IAccrualJobRepository {
+	return &accrualJobRepository{storage: s}
+}
+
+const accrualJobColumns = `
+	j.order_id, j.attempts, j.failures, j.next_attempt_at, COALESCE(j.last_error, ''),
+	COALESCE(j.last_http_status, 0), COALESCE(j.last_response, ''), COALESCE(j.lease_owner, ''),
+	COALESCE(j.request_id, ''), j.dead_at, j.correct_until, j.created_at, j.updated_at, o.user_id, o.number, o.status,
+	COALESCE(o.accrual_engine, ''), COALESCE(o.store, ''), o.created_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAccrualJob(row scanner) (*domain.AccrualJob, error) {
+	job := &domain.AccrualJob{Order: &domain.Order{}}
+	err := row.Scan(
+		&job.OrderID, &job.Attempts, &job.Failures, &job.NextAttemptAt, &job.LastError,
+		&job.LastHTTPStatus, &job.LastResponse, &job.LeaseOwner,
+		&job.RequestID, &job.DeadAt, &job.CorrectUntil, &job.CreatedAt, &job.UpdatedAt,
+		&job.Order.UserID, &job.Order.Number, &job.Order.Status, &job.Order.Engine, &job.Order.Store, &job.Order.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Order.ID = job.OrderID
+	return job, nil
+}
+
+func (repo *accrualJobRepository) queryJobs(ctx context.Context, stmt string, args ...any) ([]*domain.AccrualJob, error) {
+	jobs := make([]*domain.AccrualJob, 0)
+
+	rows, err := repo.storage.querier(ctx).QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		job, err := scanAccrualJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// ставит аренду на свободные задачи, время которых подошло, и возвращает их вместе с заказами;
+// tail дополняет выборку задач условием, сортировкой или ограничением;
+// SKIP LOCKED не нужен: пишущая транзакция одна, и задачу не захватят дважды
+func (repo *accrualJobRepository) claim(ctx context.Context, owner string, lease time.Duration, tail string, args ...any) ([]*domain.AccrualJob, error) {
+	var jobs []*domain.AccrualJob
+
+	err := repo.storage.WithinTx(ctx, storage.TxOptions{}, func(ctx context.Context) error {
+		claimedAt := now()
+
+		stmt := `
+		UPDATE accrual_jobs SET lease_owner = ?, lease_expires_at = ?
+		WHERE order_id IN (
+			SELECT order_id FROM accrual_jobs
+			WHERE next_attempt_at <= ?
+				AND dead_at IS NULL
+				AND (lease_expires_at IS NULL OR lease_expires_at < ?)
+			` + tail + `
+		)
+		RETURNING order_id`
+
+		rows, err := repo.storage.querier(ctx).QueryContext(ctx, stmt, append([]any{owner, claimedAt.Add(lease), claimedAt, claimedAt}, args...)...)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]domain.OrderID, 0)
+		for rows.Next() {
+			var id domain.OrderID
+			if err = rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			jobs = make([]*domain.AccrualJob, 0)
+			return nil
+		}
+
+		placeholders, idArgs := inArgs(ids)
+		jobs, err = repo.queryJobs(ctx, `SELECT`+accrualJobColumns+`
+		FROM accrual_jobs j
+		JOIN orders o ON o.id = j.order_id
+		WHERE j.order_id IN (`+placeholders+`)
+		ORDER BY j.next_attempt_at, j.order_id`, idArgs...)
+
+		return err
+	})
+
+	return jobs, err
+}
+
+// захватывает в аренду пачку задач, время которых подошло;
+// задачи, захваченные другими экземплярами сервиса, пропускаются до истечения их аренды
+func (repo *accrualJobRepository) AccrualJobClaimDue(ctx context.Context, owner string, lease time.Duration, limit int) ([]*domain.AccrualJob, error) {
+	jobs, err := repo.claim(ctx, owner, lease, `ORDER BY next_attempt_at, order_id LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobClaimDue() error: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// захватывает в аренду задачу по конкретному заказу, если ее время подошло и она свободна;
+// иначе ErrRecordNotFound
+func (repo *accrualJobRepository) AccrualJobClaim(ctx context.Context, owner string, lease time.Duration, orderID domain.OrderID) (*domain.AccrualJob, error) {
+	jobs, err := repo.claim(ctx, owner, lease, `AND order_id = ?`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobClaim() error: %w", err)
+	}
+
+	if len(jobs) == 0 {
+		return nil, storage.ErrRecordNotFound
+	}
+
+	return jobs[0], nil
+}
+
+// откладывает задачу на delay и снимает аренду;
+// если аренда уже перешла к другому экземпляру, задача не меняется
+func (repo *accrualJobRepository) AccrualJobReschedule(ctx context.Context, job domain.AccrualJob, delay time.Duration) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET attempts = ?, failures = ?, last_error = NULLIF(?, ''),
+		last_http_status = NULLIF(?, 0), last_response = NULLIF(?, ''),
+		next_attempt_at = ?, lease_owner = NULL, lease_expires_at = NULL, updated_at = ?
+	WHERE order_id = ? AND lease_owner = ?`
+
+	updatedAt := now()
+	args := []any{
+		job.Attempts, job.Failures, job.LastError, job.LastHTTPStatus, job.LastResponse,
+		updatedAt.Add(delay), updatedAt, job.OrderID, job.LeaseOwner,
+	}
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobReschedule() error: %w", err)
+	}
+
+	return nil
+}
+
+// переводит задачу в dead-letter, сохраняя последний ответ и ошибку
+func (repo *accrualJobRepository) AccrualJobMarkDead(ctx context.Context, job domain.AccrualJob) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET attempts = ?, failures = ?, last_error = NULLIF(?, ''),
+		last_http_status = NULLIF(?, 0), last_response = NULLIF(?, ''),
+		dead_at = ?, lease_owner = NULL, lease_expires_at = NULL, updated_at = ?
+	WHERE order_id = ? AND lease_owner = ?`
+
+	deadAt := now()
+	args := []any{
+		job.Attempts, job.Failures, job.LastError, job.LastHTTPStatus, job.LastResponse,
+		deadAt, deadAt, job.OrderID, job.LeaseOwner,
+	}
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobMarkDead() error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *accrualJobRepository) AccrualJobDelete(ctx context.Context, orderID domain.OrderID) error {
+	stmt := `DELETE FROM accrual_jobs WHERE order_id = ?`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, orderID)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobDelete() error: %w", err)
+	}
+
+	return nil
+}
+
+// после обработки заказа задача остается до конца окна исправлений и опрашивается раз в delay
+func (repo *accrualJobRepository) AccrualJobStartCorrection(ctx context.Context, orderID domain.OrderID, window time.Duration, delay time.Duration) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET correct_until = ?, next_attempt_at = ?,
+		attempts = 0, failures = 0, last_error = NULL, last_http_status = NULL, last_response = NULL,
+		lease_owner = NULL, lease_expires_at = NULL, updated_at = ?
+	WHERE order_id = ?`
+
+	startedAt := now()
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, startedAt.Add(window), startedAt.Add(delay), startedAt, orderID)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobStartCorrection() error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *accrualJobRepository) AccrualJobFindByOrderNumber(ctx context.Context, number string) (*domain.AccrualJob, error) {
+	stmt := `SELECT` + accrualJobColumns + `
+	FROM accrual_jobs j
+	JOIN orders o ON o.id = j.order_id
+	WHERE o.number = ?`
+
+	job, err := scanAccrualJob(repo.storage.querier(ctx).QueryRowContext(ctx, stmt, number))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobFindByOrderNumber() error: %w", err)
+	}
+
+	return job, nil
+}
+
+func (repo *accrualJobRepository) AccrualJobListDead(ctx context.Context) ([]*domain.AccrualJob, error) {
+	stmt := `SELECT` + accrualJobColumns + `
+	FROM accrual_jobs j
+	JOIN orders o ON o.id = j.order_id
+	WHERE j.dead_at IS NOT NULL
+	ORDER BY j.dead_at DESC, j.order_id DESC`
+
+	jobs, err := repo.queryJobs(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("accrualJobRepository -> AccrualJobListDead() error: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// возвращает задачу из dead-letter в очередь с немедленной попыткой
+func (repo *accrualJobRepository) AccrualJobRequeue(ctx context.Context, orderID domain.OrderID) error {
+	stmt := `
+	UPDATE accrual_jobs
+	SET dead_at = NULL, attempts = 0, failures = 0, next_attempt_at = ?1, updated_at = ?1
+	WHERE order_id = ?2 AND dead_at IS NOT NULL`
+
+	res, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, now(), orderID)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobRequeue() error: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobRequeue() error: %w", err)
+	}
+
+	if affected == 0 {
+		return storage.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// снимает аренду задач экземпляра по номерам заказов, время следующей попытки не меняется
+func (repo *accrualJobRepository) AccrualJobReleaseLeases(ctx context.Context, owner string, numbers []string) error {
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	placeholders, args := inArgs(numbers)
+	stmt := `
+	UPDATE accrual_jobs
+	SET lease_owner = NULL, lease_expires_at = NULL, updated_at = ?
+	WHERE lease_owner = ? AND order_id IN (SELECT id FROM orders WHERE number IN (` + placeholders + `))`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, append([]any{now(), owner}, args...)...)
+	if err != nil {
+		return fmt.Errorf("accrualJobRepository -> AccrualJobReleaseLeases() error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS accrual_calls;
+
+DROP TABLE IF EXISTS accrual_jobs;
+
+DROP TABLE IF EXISTS statements;
+
+DROP TABLE IF EXISTS transfers;
+
+DROP TABLE IF EXISTS withdrawal_refunds;
+
+DROP TABLE IF EXISTS withdrawals;
+
+DROP TABLE IF EXISTS order_adjustments;
+
+DROP TABLE IF EXISTS order_items;
+
+DROP TABLE IF EXISTS orders;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,170 @@
+-- схема SQLite соответствует итоговой схеме PostgreSQL: перечисления заменены проверками,
+-- суммы хранятся текстом, чтобы не терять точность, время - в UTC
+CREATE TABLE
+    IF NOT EXISTS users (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        login VARCHAR(100) NOT NULL,
+        password VARCHAR(100) NOT NULL,
+        balance TEXT NOT NULL DEFAULT '0',
+        withdrawn TEXT NOT NULL DEFAULT '0',
+        tier VARCHAR(32) NOT NULL DEFAULT '',
+        password_changed_at TIMESTAMP NULL,
+        created_at TIMESTAMP NOT NULL,
+        updated_at TIMESTAMP NOT NULL,
+        CONSTRAINT login_unique UNIQUE (login)
+    );
+
+CREATE TABLE
+    IF NOT EXISTS orders (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        number VARCHAR(255) NOT NULL,
+        status VARCHAR(20) NOT NULL,
+        accrual TEXT NOT NULL DEFAULT '0',
+        base_accrual TEXT NOT NULL DEFAULT '0',
+        accrual_engine VARCHAR(20) NULL,
+        store VARCHAR(100) NULL,
+        review_accrual TEXT NULL,
+        review_reason TEXT NULL,
+        created_at TIMESTAMP NOT NULL,
+        updated_at TIMESTAMP NOT NULL,
+        CONSTRAINT number_unique UNIQUE (number),
+        CONSTRAINT orders_status_check CHECK (status IN ('NEW', 'PROCESSING', 'INVALID', 'PROCESSED', 'NEEDS_REVIEW')),
+        CONSTRAINT orders_fk_users FOREIGN KEY (user_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS orders_user_id_status_updated_at_idx ON orders (user_id, status, updated_at);
+
+CREATE TABLE
+    IF NOT EXISTS order_items (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        order_id INTEGER NOT NULL,
+        product VARCHAR(255) NULL,
+        category VARCHAR(255) NULL,
+        price TEXT NOT NULL,
+        quantity INTEGER NOT NULL DEFAULT 1,
+        CONSTRAINT order_items_fk_orders FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS order_items_order_id_idx ON order_items (order_id);
+
+CREATE TABLE
+    IF NOT EXISTS order_adjustments (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        order_id INTEGER NOT NULL,
+        user_id INTEGER NOT NULL,
+        amount TEXT NOT NULL,
+        base_accrual TEXT NOT NULL,
+        unrecovered TEXT NOT NULL DEFAULT '0',
+        created_at TIMESTAMP NOT NULL,
+        CONSTRAINT order_adjustments_fk_orders FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE,
+        CONSTRAINT order_adjustments_fk_users FOREIGN KEY (user_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS order_adjustments_order_id_idx ON order_adjustments (order_id);
+
+CREATE INDEX IF NOT EXISTS order_adjustments_user_id_created_at_idx ON order_adjustments (user_id, created_at);
+
+CREATE TABLE
+    IF NOT EXISTS withdrawals (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        order_number VARCHAR(255) NOT NULL,
+        amount TEXT NOT NULL,
+        refunded TEXT NOT NULL DEFAULT '0',
+        status VARCHAR(20) NOT NULL DEFAULT 'WITHDRAWN',
+        created_at TIMESTAMP NOT NULL,
+        CONSTRAINT order_number_unique UNIQUE (order_number),
+        CONSTRAINT withdrawals_status_check CHECK (status IN ('WITHDRAWN', 'PARTIALLY_REFUNDED', 'REFUNDED')),
+        CONSTRAINT withdrawals_fk_users FOREIGN KEY (user_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS withdrawals_user_id_created_at_idx ON withdrawals (user_id, created_at);
+
+CREATE TABLE
+    IF NOT EXISTS withdrawal_refunds (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        withdrawal_id INTEGER NOT NULL,
+        user_id INTEGER NOT NULL,
+        amount TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL,
+        CONSTRAINT withdrawal_refunds_fk_withdrawals FOREIGN KEY (withdrawal_id) REFERENCES withdrawals (id),
+        CONSTRAINT withdrawal_refunds_fk_users FOREIGN KEY (user_id) REFERENCES users (id)
+    );
+
+CREATE INDEX IF NOT EXISTS withdrawal_refunds_user_id_created_at_idx ON withdrawal_refunds (user_id, created_at);
+
+CREATE TABLE
+    IF NOT EXISTS transfers (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        sender_id INTEGER NOT NULL,
+        recipient_id INTEGER NOT NULL,
+        amount TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL,
+        CONSTRAINT transfers_fk_sender FOREIGN KEY (sender_id) REFERENCES users (id),
+        CONSTRAINT transfers_fk_recipient FOREIGN KEY (recipient_id) REFERENCES users (id),
+        CONSTRAINT transfers_amount_positive CHECK (CAST(amount AS REAL) > 0)
+    );
+
+CREATE INDEX IF NOT EXISTS transfers_sender_created_at_idx ON transfers (sender_id, created_at);
+
+CREATE INDEX IF NOT EXISTS transfers_recipient_created_at_idx ON transfers (recipient_id, created_at);
+
+CREATE TABLE
+    IF NOT EXISTS statements (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        period DATE NOT NULL,
+        opening_balance TEXT NOT NULL,
+        accruals TEXT NOT NULL,
+        withdrawals TEXT NOT NULL,
+        refunds TEXT NOT NULL,
+        transfers_in TEXT NOT NULL,
+        transfers_out TEXT NOT NULL,
+        expirations TEXT NOT NULL,
+        closing_balance TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL,
+        CONSTRAINT statements_user_period_unique UNIQUE (user_id, period),
+        CONSTRAINT statements_fk_users FOREIGN KEY (user_id) REFERENCES users (id)
+    );
+
+CREATE TABLE
+    IF NOT EXISTS accrual_jobs (
+        order_id INTEGER PRIMARY KEY,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        failures INTEGER NOT NULL DEFAULT 0,
+        next_attempt_at TIMESTAMP NOT NULL,
+        last_error TEXT NULL,
+        last_http_status INTEGER NULL,
+        last_response TEXT NULL,
+        lease_owner VARCHAR(100) NULL,
+        lease_expires_at TIMESTAMP NULL,
+        request_id VARCHAR(100) NULL,
+        dead_at TIMESTAMP NULL,
+        correct_until TIMESTAMP NULL,
+        created_at TIMESTAMP NOT NULL,
+        updated_at TIMESTAMP NOT NULL,
+        CONSTRAINT accrual_jobs_fk_orders FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS accrual_jobs_next_attempt_at_idx ON accrual_jobs (next_attempt_at);
+
+CREATE INDEX IF NOT EXISTS accrual_jobs_dead_at_idx ON accrual_jobs (dead_at) WHERE dead_at IS NOT NULL;
+
+CREATE TABLE
+    IF NOT EXISTS accrual_calls (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        order_number VARCHAR(255) NOT NULL,
+        provider VARCHAR(100) NOT NULL,
+        url TEXT NOT NULL,
+        http_status INTEGER NULL,
+        response_body TEXT NULL,
+        error TEXT NULL,
+        latency_ms INTEGER NOT NULL,
+        request_id VARCHAR(100) NULL,
+        created_at TIMESTAMP NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS accrual_calls_order_number_created_at_idx ON accrual_calls (order_number, created_at);
+
+CREATE INDEX IF NOT EXISTS accrual_calls_created_at_idx ON accrual_calls (created_at);
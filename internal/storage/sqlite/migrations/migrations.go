@@ -0,0 +1,7 @@
+// миграции схемы SQLite, встроенные в бинарник
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
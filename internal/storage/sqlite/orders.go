@@ -0,0 +1,298 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type orderRepository struct {
+	storage *Storage
+}
+
+func NewOrderRepository(s *Storage) repository.IOrderRepository {
+	return &orderRepository{storage: s}
+}
+
+// вместе с заказом создается задача опроса системы начислений и сохраняются данные о покупке
+func (repo *orderRepository) OrderCreate(ctx context.Context, order domain.Order) (*domain.Order, error) {
+	var store string
+	if order.Purchase != nil {
+		store = order.Purchase.Store
+	}
+
+	newOrder := &domain.Order{
+		UserID:    order.UserID,
+		Number:    order.Number,
+		Status:    order.Status,
+		Engine:    order.Engine,
+		Purchase:  order.Purchase,
+		CreatedAt: now(),
+	}
+	newOrder.UpdatedAt = newOrder.CreatedAt
+
+	err := repo.storage.WithinTx(ctx, storage.TxOptions{}, func(ctx context.Context) error {
+		q := repo.storage.querier(ctx)
+
+		res, err := q.ExecContext(
+			ctx,
+			`INSERT INTO orders (user_id, number, status, accrual_engine, store, created_at, updated_at)
+			VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)`,
+			order.UserID, order.Number, order.Status, order.Engine, store, newOrder.CreatedAt, newOrder.UpdatedAt,
+		)
+		if err != nil {
+			return wrapError(err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		newOrder.ID = domain.OrderID(id)
+
+		_, err = q.ExecContext(
+			ctx,
+			`INSERT INTO accrual_jobs (order_id, request_id, next_attempt_at, created_at, updated_at)
+			VALUES (?, NULLIF(?, ''), ?, ?, ?)`,
+			newOrder.ID, order.RequestID, newOrder.CreatedAt, newOrder.CreatedAt, newOrder.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if order.Purchase == nil {
+			return nil
+		}
+
+		for _, item := range order.Purchase.Items {
+			_, err = q.ExecContext(
+				ctx,
+				`INSERT INTO order_items (order_id, product, category, price, quantity)
+				VALUES (?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)`,
+				newOrder.ID, item.Product, item.Category, item.Price, item.Quantity,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderCreate() error: %w", err)
+	}
+
+	return newOrder, nil
+}
+
+func (repo *orderRepository) OrderList(ctx context.Context, userID domain.UserID) ([]*domain.Order, error) {
+	// начисление показывается с учетом корректировок
+	stmt := `
+	SELECT o.number, o.status, ROUND(o.accrual + (SELECT TOTAL(a.amount) FROM order_adjustments a WHERE a.order_id = o.id), 2), o.created_at
+	FROM orders o WHERE o.user_id = ? ORDER BY o.created_at DESC, o.id DESC`
+	orders := make([]*domain.Order, 0)
+
+	rows, err := repo.storage.querier(ctx).QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderList() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order := &domain.Order{}
+		if err = rows.Scan(&order.Number, &order.Status, &order.Accrual, &order.CreatedAt); err != nil {
+			return nil, fmt.Errorf("orderRepository -> OrderList() error: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderList() error: %w", err)
+	}
+
+	return orders, nil
+}
+
+func (repo *orderRepository) OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error) {
+	stmt := `
+	SELECT id, user_id, number, status, accrual, COALESCE(accrual_engine, ''),
+		COALESCE(review_accrual, '0'), COALESCE(review_reason, ''), created_at, updated_at
+	FROM orders WHERE number = ?`
+	order := new(domain.Order)
+
+	err := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, number).Scan(
+		&order.ID, &order.UserID, &order.Number, &order.Status,
+		&order.Accrual, &order.Engine, &order.ReviewAccrual, &order.ReviewReason,
+		&order.CreatedAt, &order.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("orderRepository -> OrderFindByNumber() error: %w", err)
+	}
+
+	return order, nil
+}
+
+// данные о покупке для встроенного расчета начислений
+func (repo *orderRepository) OrderPurchaseFindByNumber(ctx context.Context, number string) (*domain.Purchase, error) {
+	stmt := `SELECT id, COALESCE(store, ''), created_at FROM orders WHERE number = ?`
+
+	var orderID domain.OrderID
+	purchase := &domain.Purchase{Items: make([]domain.PurchaseItem, 0)}
+
+	err := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, number).Scan(&orderID, &purchase.Store, &purchase.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+	}
+
+	itemsStmt := `
+	SELECT COALESCE(product, ''), COALESCE(category, ''), price, quantity
+	FROM order_items WHERE order_id = ? ORDER BY id`
+
+	rows, err := repo.storage.querier(ctx).QueryContext(ctx, itemsStmt, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.PurchaseItem
+		if err = rows.Scan(&item.Product, &item.Category, &item.Price, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+		}
+		purchase.Items = append(purchase.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderPurchaseFindByNumber() error: %w", err)
+	}
+
+	return purchase, nil
+}
+
+func (repo *orderRepository) OrderUpdate(ctx context.Context, order domain.Order) error {
+	stmt := `UPDATE orders SET status = ?, accrual = ?, base_accrual = ?, updated_at = ? WHERE id = ?`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, order.Status, order.Accrual, order.BaseAccrual, now(), order.ID)
+	if err != nil {
+		return fmt.Errorf("orderRepository -> OrderUpdate() error: %w", err)
+	}
+
+	return nil
+}
+
+// откладывает начисление до решения администратора
+func (repo *orderRepository) OrderMarkForReview(ctx context.Context, order domain.Order) error {
+	stmt := `
+	UPDATE orders SET status = 'NEEDS_REVIEW', review_accrual = ?, review_reason = ?, updated_at = ?
+	WHERE id = ?`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, order.ReviewAccrual, order.ReviewReason, now(), order.ID)
+	if err != nil {
+		return fmt.Errorf("orderRepository -> OrderMarkForReview() error: %w", err)
+	}
+
+	return nil
+}
+
+// заказы, ждущие ручной проверки, начиная с самых давних
+func (repo *orderRepository) OrderListForReview(ctx context.Context) ([]*domain.Order, error) {
+	stmt := `
+	SELECT id, user_id, number, status, COALESCE(review_accrual, '0'), COALESCE(review_reason, ''), created_at, updated_at
+	FROM orders WHERE status = 'NEEDS_REVIEW' ORDER BY updated_at, id`
+	orders := make([]*domain.Order, 0)
+
+	rows, err := repo.storage.querier(ctx).QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderListForReview() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order := &domain.Order{}
+		err = rows.Scan(
+			&order.ID, &order.UserID, &order.Number, &order.Status,
+			&order.ReviewAccrual, &order.ReviewReason, &order.CreatedAt, &order.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("orderRepository -> OrderListForReview() error: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("orderRepository -> OrderListForReview() error: %w", err)
+	}
+
+	return orders, nil
+}
+
+// сумма начислений системы без множителя уровня по обработанным заказам пользователя начиная с since
+func (repo *orderRepository) OrderAccrualSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	stmt := `
+	SELECT ROUND(
+		(SELECT TOTAL(base_accrual) FROM orders
+			WHERE user_id = ?1 AND status = 'PROCESSED' AND updated_at >= ?2) +
+		(SELECT TOTAL(d.delta) FROM (
+			SELECT a.created_at, a.base_accrual - COALESCE(
+				LAG(a.base_accrual) OVER (PARTITION BY a.order_id ORDER BY a.id), o.base_accrual) AS delta
+			FROM order_adjustments a JOIN orders o ON o.id = a.order_id
+			WHERE a.user_id = ?1) d
+			WHERE d.created_at >= ?2), 2)`
+
+	row := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, userID, since.UTC())
+
+	var sum decimal.Decimal
+	if err := row.Scan(&sum); err != nil {
+		return decimal.Zero, fmt.Errorf("orderRepository -> OrderAccrualSumSince() error: %w", err)
+	}
+
+	return sum, nil
+}
+
+// начисление по заказу с учетом корректировок и последнее начисление системы до применения множителя уровня
+func (repo *orderRepository) OrderAccrualCurrent(ctx context.Context, id domain.OrderID) (decimal.Decimal, decimal.Decimal, error) {
+	stmt := `
+	SELECT
+		ROUND(o.accrual + (SELECT TOTAL(a.amount) FROM order_adjustments a WHERE a.order_id = o.id), 2),
+		COALESCE((SELECT a.base_accrual FROM order_adjustments a WHERE a.order_id = o.id ORDER BY a.id DESC LIMIT 1), o.base_accrual, '0')
+	FROM orders o WHERE o.id = ?`
+
+	row := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, id)
+
+	var accrual, base decimal.Decimal
+	if err := row.Scan(&accrual, &base); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return decimal.Zero, decimal.Zero, storage.ErrRecordNotFound
+		}
+		return decimal.Zero, decimal.Zero, fmt.Errorf("orderRepository -> OrderAccrualCurrent() error: %w", err)
+	}
+
+	return accrual, base, nil
+}
+
+// проводка исправления начисления; само начисление по заказу не меняется
+func (repo *orderRepository) OrderAdjustmentCreate(ctx context.Context, a domain.OrderAdjustment) error {
+	stmt := `
+	INSERT INTO order_adjustments (order_id, user_id, amount, base_accrual, unrecovered, created_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, a.OrderID, a.UserID, a.Amount, a.BaseAccrual, a.Unrecovered, now())
+	if err != nil {
+		return fmt.Errorf("orderRepository -> OrderAdjustmentCreate() error: %w", err)
+	}
+
+	return nil
+}
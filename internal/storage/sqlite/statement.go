@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type statementRepository struct {
+	storage *Storage
+}
+
+func NewStatementRepository(s *Storage) repository.IStatementRepository {
+	return &statementRepository{storage: s}
+}
+
+func (repo *statementRepository) StatementFind(ctx context.Context, userID domain.UserID, period time.Time) (*domain.Statement, error) {
+	stmt := `
+	SELECT id, user_id, period, opening_balance, accruals, withdrawals, refunds,
+		transfers_in, transfers_out, expirations, closing_balance, created_at
+	FROM statements WHERE user_id = ? AND period = ?`
+
+	row := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, userID, date(period))
+
+	s := new(domain.Statement)
+	err := row.Scan(
+		&s.ID, &s.UserID, &s.Period, &s.OpeningBalance, &s.Accruals, &s.Withdrawals, &s.Refunds,
+		&s.TransfersIn, &s.TransfersOut, &s.Expirations, &s.ClosingBalance, &s.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("statementRepository -> StatementFind() error: %w", err)
+	}
+
+	return s, nil
+}
+
+// сохраняет выписку; уже существующая выписка за период не перезаписывается
+func (repo *statementRepository) StatementCreate(ctx context.Context, s domain.Statement) error {
+	stmt := `
+	INSERT INTO statements (user_id, period, opening_balance, accruals, withdrawals, refunds,
+		transfers_in, transfers_out, expirations, closing_balance, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (user_id, period) DO NOTHING`
+
+	args := []any{
+		s.UserID, date(s.Period), s.OpeningBalance, s.Accruals, s.Withdrawals, s.Refunds,
+		s.TransfersIn, s.TransfersOut, s.Expirations, s.ClosingBalance, now(),
+	}
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return fmt.Errorf("statementRepository -> StatementCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// обороты пользователя за полуинтервал [from, to); заполняются только суммы операций,
+// начисление относится к моменту обработки заказа, корректировка - к моменту проводки
+func (repo *statementRepository) StatementTurnover(ctx context.Context, userID domain.UserID, from time.Time, to time.Time) (*domain.Statement, error) {
+	stmt := `
+	SELECT
+		ROUND((SELECT TOTAL(accrual) FROM orders
+			WHERE user_id = ?1 AND status = 'PROCESSED' AND updated_at >= ?2 AND updated_at < ?3) +
+		(SELECT TOTAL(amount) FROM order_adjustments
+			WHERE user_id = ?1 AND created_at >= ?2 AND created_at < ?3), 2),
+		ROUND((SELECT TOTAL(amount) FROM withdrawals
+			WHERE user_id = ?1 AND created_at >= ?2 AND created_at < ?3), 2),
+		ROUND((SELECT TOTAL(amount) FROM withdrawal_refunds
+			WHERE user_id = ?1 AND created_at >= ?2 AND created_at < ?3), 2),
+		ROUND((SELECT TOTAL(amount) FROM transfers
+			WHERE recipient_id = ?1 AND created_at >= ?2 AND created_at < ?3), 2),
+		ROUND((SELECT TOTAL(amount) FROM transfers
+			WHERE sender_id = ?1 AND created_at >= ?2 AND created_at < ?3), 2)`
+
+	row := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, userID, from.UTC(), to.UTC())
+
+	s := &domain.Statement{UserID: userID}
+	if err := row.Scan(&s.Accruals, &s.Withdrawals, &s.Refunds, &s.TransfersIn, &s.TransfersOut); err != nil {
+		return nil, fmt.Errorf("statementRepository -> StatementTurnover() error: %w", err)
+	}
+
+	return s, nil
+}
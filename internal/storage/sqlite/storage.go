@@ -0,0 +1,204 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/migrator"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var _ storage.IStorage = (*Storage)(nil)
+
+// хранилище в файле SQLite для запуска на одном сервере без PostgreSQL.
+// транзакции начинаются с BEGIN IMMEDIATE и выполняются по одной, поэтому
+// блокировки строк (FOR UPDATE) не нужны
+type Storage struct {
+	db *sql.DB
+}
+
+// общие для соединения и транзакции методы, которыми пользуются репозитории
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+func NewStorage(config config.DB, migrate bool) (*Storage, error) {
+	if migrate {
+		if err := migrator.Up(config); err != nil {
+			return nil, fmt.Errorf("migrator.Up() failed: %w", err)
+		}
+	}
+
+	dsn, err := driverDSN(config.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("parse DSN failed: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite open failed: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("db connect failed: %w", err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// репозитории, работающие с данными хранилища
+func NewRepositories(s *Storage) *repository.Repositories {
+	return &repository.Repositories{
+		User:        NewUserRepository(s),
+		Order:       NewOrderRepository(s),
+		Withdrawal:  NewWithdrawalRepository(s),
+		Transfer:    NewTransferRepository(s),
+		Statement:   NewStatementRepository(s),
+		AccrualJob:  NewAccrualJobRepository(s),
+		AccrualCall: NewAccrualCallRepository(s),
+	}
+}
+
+// fn, вызванная внутри другой транзакции, выполняется в ней же; транзакция откатывается,
+// если fn вернула ошибку или запаниковала. уровень изоляции не настраивается: пишущая
+// транзакция в SQLite всегда одна. при занятой другим процессом базе fn повторяется
+func (s *Storage) WithinTx(ctx context.Context, opts storage.TxOptions, fn func(ctx context.Context) error) error {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		err = s.runTx(ctx, fn)
+		if !isBusy(err) {
+			return err
+		}
+
+		logging.LogWarnCtx(ctx, fmt.Sprintf("storage: database is busy, attempt %d of %d: %s", attempt+1, opts.MaxRetries+1, err))
+	}
+
+	return err
+}
+
+func (s *Storage) runTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		rbErr := tx.Rollback()
+		if rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			logging.LogErrorCtx(ctx, rbErr, "storage: WithinTx(): error rolling tx back")
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) Close() {
+	if err := s.db.Close(); err != nil {
+		logging.LogError(err, "storage: error closing sqlite database")
+	}
+}
+
+// транзакция из контекста, если репозиторий вызван внутри WithinTx, иначе база
+func (s *Storage) querier(ctx context.Context) querier {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return s.db
+}
+
+func txFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sql.Tx)
+	return tx
+}
+
+// sqlite://path?params -> path?params с параметрами драйвера, на которые рассчитаны репозитории:
+// время пишется в формате, сравнимом как строка, транзакции сразу захватывают запись
+func driverDSN(dsn string) (string, error) {
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, "sqlite://"), "?")
+	if path == "" {
+		return "", fmt.Errorf("empty sqlite database path")
+	}
+
+	userParams, err := url.ParseQuery(query)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{"_pragma": {"foreign_keys(1)", "busy_timeout(10000)", "journal_mode(WAL)"}}
+	for key, values := range userParams {
+		for _, v := range values {
+			params.Add(key, v)
+		}
+	}
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+
+	return path + "?" + params.Encode(), nil
+}
+
+// база заблокирована другим соединением дольше busy_timeout
+func isBusy(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	code := sqliteErr.Code() & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+// нарушение уникальности превращается в storage.ErrRecordExists
+func wrapError(err error) error {
+	var sqliteErr *sqlitedriver.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		if code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+			return fmt.Errorf("%w: %w", storage.ErrRecordExists, err)
+		}
+	}
+
+	return err
+}
+
+// время хранится в UTC, чтобы строки сравнивались в хронологическом порядке
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// дата без времени, как тип DATE в PostgreSQL
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// плейсхолдеры и аргументы для условия IN (...)
+func inArgs[T any](values []T) (string, []any) {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+
+	return strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", "), args
+}
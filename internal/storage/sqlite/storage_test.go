@@ -0,0 +1,76 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ex0rcist/gophermart/internal/config"
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/storage/repository/repotest"
+	"github.com/ex0rcist/gophermart/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStorage(t *testing.T) (storage.IStorage, *repository.Repositories) {
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "gophermart.db")
+
+	s, err := sqlite.NewStorage(config.DB{DSN: dsn}, true)
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	return s, sqlite.NewRepositories(s)
+}
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, newStorage)
+}
+
+func TestDuplicatesReturnErrRecordExists(t *testing.T) {
+	ctx := context.Background()
+	_, repos := newStorage(t)
+
+	user, err := repos.User.UserCreate(ctx, "me", "hash")
+	require.NoError(t, err)
+
+	_, err = repos.User.UserCreate(ctx, "me", "hash")
+	assert.ErrorIs(t, err, storage.ErrRecordExists)
+
+	_, err = repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew})
+	require.NoError(t, err)
+
+	_, err = repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew})
+	assert.ErrorIs(t, err, storage.ErrRecordExists)
+
+	require.NoError(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "9278923470"}))
+	assert.ErrorIs(t, repos.Withdrawal.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: "9278923470"}), storage.ErrRecordExists)
+}
+
+func TestInvalidStatusRejected(t *testing.T) {
+	ctx := context.Background()
+	_, repos := newStorage(t)
+
+	user, err := repos.User.UserCreate(ctx, "me", "hash")
+	require.NoError(t, err)
+
+	_, err = repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: "LOST"})
+	assert.Error(t, err)
+}
+
+func TestMigrationsAreIdempotent(t *testing.T) {
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "gophermart.db")
+
+	for range 2 {
+		s, err := sqlite.NewStorage(config.DB{DSN: dsn}, true)
+		require.NoError(t, err)
+		s.Close()
+	}
+}
+
+func TestNewStorageEmptyPath(t *testing.T) {
+	_, err := sqlite.NewStorage(config.DB{DSN: "sqlite://"}, false)
+	assert.Error(t, err)
+}
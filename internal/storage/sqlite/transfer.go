@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type transferRepository struct {
+	storage *Storage
+}
+
+func NewTransferRepository(s *Storage) repository.ITransferRepository {
+	return &transferRepository{storage: s}
+}
+
+func (repo *transferRepository) TransferCreate(ctx context.Context, t domain.Transfer) error {
+	stmt := `INSERT INTO transfers (sender_id, recipient_id, amount, created_at) VALUES (?, ?, ?, ?)`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, t.SenderID, t.RecipientID, t.Amount, now())
+	if err != nil {
+		return fmt.Errorf("transferRepository -> TransferCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// входящие и исходящие переводы пользователя
+func (repo *transferRepository) TransferList(ctx context.Context, userID domain.UserID) ([]*domain.Transfer, error) {
+	stmt := `
+	SELECT t.sender_id, s.login, t.recipient_id, r.login, t.amount, t.created_at
+	FROM transfers t
+	JOIN users s ON s.id = t.sender_id
+	JOIN users r ON r.id = t.recipient_id
+	WHERE t.sender_id = ?1 OR t.recipient_id = ?1
+	ORDER BY t.created_at DESC, t.id DESC`
+	transfers := make([]*domain.Transfer, 0)
+
+	rows, err := repo.storage.querier(ctx).QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, fmt.Errorf("transferRepository -> TransferList() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &domain.Transfer{}
+		if err = rows.Scan(&t.SenderID, &t.SenderLogin, &t.RecipientID, &t.RecipientLogin, &t.Amount, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("transferRepository -> TransferList() error: %w", err)
+		}
+		transfers = append(transfers, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("transferRepository -> TransferList() error: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// сумма и количество исходящих переводов пользователя начиная с since
+func (repo *transferRepository) TransferStatsSince(ctx context.Context, senderID domain.UserID, since time.Time) (decimal.Decimal, int, error) {
+	stmt := `SELECT ROUND(TOTAL(amount), 2), COUNT(*) FROM transfers WHERE sender_id = ? AND created_at >= ?`
+
+	row := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, senderID, since.UTC())
+
+	var sum decimal.Decimal
+	var count int
+	if err := row.Scan(&sum, &count); err != nil {
+		return decimal.Zero, 0, fmt.Errorf("transferRepository -> TransferStatsSince() error: %w", err)
+	}
+
+	return sum, count, nil
+}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type userRepository struct {
+	storage *Storage
+}
+
+func NewUserRepository(s *Storage) repository.IUserRepository {
+	return &userRepository{storage: s}
+}
+
+func (repo *userRepository) UserCreate(ctx context.Context, login string, password string) (*domain.User, error) {
+	stmt := `INSERT INTO users (login, password, created_at, updated_at) VALUES (?, ?, ?, ?)`
+
+	createdAt := now()
+
+	res, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, login, password, createdAt, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("userRepository -> UserCreate() error: %w", wrapError(err))
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("userRepository -> UserCreate() error: %w", err)
+	}
+
+	return &domain.User{ID: domain.UserID(id), Login: login, CreatedAt: createdAt, UpdatedAt: createdAt}, nil
+}
+
+func (repo *userRepository) UserFindByLogin(ctx context.Context, login string) (*domain.User, error) {
+	stmt := `SELECT id, login, password, balance, tier, created_at, updated_at, password_changed_at FROM users WHERE login = ?`
+	user := new(domain.User)
+
+	err := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, login).Scan(
+		&user.ID, &user.Login, &user.Password,
+		&user.Balance, &user.Tier, &user.CreatedAt, &user.UpdatedAt,
+		&user.PasswordChangedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("userRepository -> UserFindByLogin() error: %w", err)
+	}
+
+	return user, nil
+}
+
+// строку не нужно блокировать: транзакция, в которой читается баланс, уже единственная пишущая
+func (repo *userRepository) UserGetBalance(ctx context.Context, id domain.UserID) (*decimal.Decimal, *decimal.Decimal, error) {
+	stmt := `SELECT balance, withdrawn FROM users WHERE id = ?`
+
+	var b, w decimal.Decimal
+	err := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, id).Scan(&b, &w)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, storage.ErrRecordNotFound
+		}
+		return nil, nil, fmt.Errorf("userRepository -> UserGetBalance() error: %w", err)
+	}
+
+	return &b, &w, nil
+}
+
+func (repo *userRepository) UserUpdateBalanceAndWithdrawals(ctx context.Context, id domain.UserID) error {
+	stmt := `
+	UPDATE users
+	SET
+		balance = CAST(ROUND(
+			(SELECT TOTAL(accrual) FROM orders WHERE user_id = ?1 AND status = 'PROCESSED') +
+			(SELECT TOTAL(amount) FROM order_adjustments WHERE user_id = ?1) -
+			(SELECT TOTAL(amount - refunded) FROM withdrawals WHERE user_id = ?1) -
+			(SELECT TOTAL(amount) FROM transfers WHERE sender_id = ?1) +
+			(SELECT TOTAL(amount) FROM transfers WHERE recipient_id = ?1), 2) AS TEXT),
+		withdrawn = CAST(ROUND((SELECT TOTAL(amount - refunded) FROM withdrawals WHERE user_id = ?1), 2) AS TEXT)
+	WHERE
+		id = ?1`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, id)
+	if err != nil {
+		return fmt.Errorf("userRepository -> UserUpdateBalanceAndWithdrawals() error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *userRepository) UserGetTier(ctx context.Context, id domain.UserID) (domain.LoyaltyTier, error) {
+	stmt := `SELECT tier FROM users WHERE id = ?`
+
+	var tier domain.LoyaltyTier
+	if err := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, id).Scan(&tier); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", storage.ErrRecordNotFound
+		}
+		return "", fmt.Errorf("userRepository -> UserGetTier() error: %w", err)
+	}
+
+	return tier, nil
+}
+
+func (repo *userRepository) UserUpdateTier(ctx context.Context, id domain.UserID, tier domain.LoyaltyTier) error {
+	stmt := `UPDATE users SET tier = ?, updated_at = ? WHERE id = ?`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, tier, now(), id)
+	if err != nil {
+		return fmt.Errorf("userRepository -> UserUpdateTier() error: %w", err)
+	}
+
+	return nil
+}
+
+// вместе с паролем запоминается время смены, от которого отсчитывается запрет списаний
+func (repo *userRepository) UserUpdatePassword(ctx context.Context, id domain.UserID, password string) error {
+	stmt := `UPDATE users SET password = ?1, password_changed_at = ?2, updated_at = ?2 WHERE id = ?3`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, password, now(), id)
+	if err != nil {
+		return fmt.Errorf("userRepository -> UserUpdatePassword() error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/shopspring/decimal"
+)
+
+type withdrawalRepository struct {
+	storage *Storage
+}
+
+func NewWithdrawalRepository(s *Storage) repository.IWithdrawalRepository {
+	return &withdrawalRepository{storage: s}
+}
+
+func (repo *withdrawalRepository) WithdrawalCreate(ctx context.Context, w domain.Withdrawal) error {
+	stmt := `INSERT INTO withdrawals (user_id, order_number, amount, created_at) VALUES (?, ?, ?, ?)`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, w.UserID, w.OrderNumber, w.Amount, now())
+	if err != nil {
+		return fmt.Errorf("withdrawalRepository -> WithdrawalCreate() error: %w", wrapError(err))
+	}
+
+	return nil
+}
+
+func (repo *withdrawalRepository) WithdrawalFindByOrderNumber(ctx context.Context, number string) (*domain.Withdrawal, error) {
+	stmt := `SELECT id, user_id, order_number, amount, refunded, status, created_at FROM withdrawals WHERE order_number = ?`
+
+	row := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, number)
+
+	wd := new(domain.Withdrawal)
+	err := row.Scan(&wd.ID, &wd.UserID, &wd.OrderNumber, &wd.Amount, &wd.Refunded, &wd.Status, &wd.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("withdrawalRepository -> WithdrawalFindByOrderNumber() error: %w", err)
+	}
+
+	return wd, nil
+}
+
+func (repo *withdrawalRepository) WithdrawalList(ctx context.Context, userID domain.UserID) ([]*domain.Withdrawal, error) {
+	stmt := `SELECT order_number, amount, refunded, status, created_at FROM withdrawals WHERE user_id = ? ORDER BY created_at DESC, id DESC`
+	wds := make([]*domain.Withdrawal, 0)
+
+	rows, err := repo.storage.querier(ctx).QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, fmt.Errorf("withdrawalRepository -> WithdrawalList() error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		wd := &domain.Withdrawal{}
+		if err = rows.Scan(&wd.OrderNumber, &wd.Amount, &wd.Refunded, &wd.Status, &wd.CreatedAt); err != nil {
+			return nil, fmt.Errorf("withdrawalRepository -> WithdrawalList() error: %w", err)
+		}
+		wds = append(wds, wd)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("withdrawalRepository -> WithdrawalList() error: %w", err)
+	}
+
+	return wds, nil
+}
+
+func (repo *withdrawalRepository) WithdrawalUpdate(ctx context.Context, w domain.Withdrawal) error {
+	stmt := `UPDATE withdrawals SET refunded = ?, status = ? WHERE id = ?`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, w.Refunded, w.Status, w.ID)
+	if err != nil {
+		return fmt.Errorf("withdrawalRepository -> WithdrawalUpdate() error: %w", err)
+	}
+
+	return nil
+}
+
+func (repo *withdrawalRepository) WithdrawalRefundCreate(ctx context.Context, r domain.WithdrawalRefund) error {
+	stmt := `INSERT INTO withdrawal_refunds (withdrawal_id, user_id, amount, created_at) VALUES (?, ?, ?, ?)`
+
+	_, err := repo.storage.querier(ctx).ExecContext(ctx, stmt, r.WithdrawalID, r.UserID, r.Amount, now())
+	if err != nil {
+		return fmt.Errorf("withdrawalRepository -> WithdrawalRefundCreate() error: %w", err)
+	}
+
+	return nil
+}
+
+// сумма списаний пользователя начиная с since за вычетом возвратов, как и в балансе
+func (repo *withdrawalRepository) WithdrawalSumSince(ctx context.Context, userID domain.UserID, since time.Time) (decimal.Decimal, error) {
+	stmt := `SELECT ROUND(TOTAL(amount - refunded), 2) FROM withdrawals WHERE user_id = ? AND created_at >= ?`
+
+	row := repo.storage.querier(ctx).QueryRowContext(ctx, stmt, userID, since.UTC())
+
+	var sum decimal.Decimal
+	if err := row.Scan(&sum); err != nil {
+		return decimal.Zero, fmt.Errorf("withdrawalRepository -> WithdrawalSumSince() error: %w", err)
+	}
+
+	return sum, nil
+}
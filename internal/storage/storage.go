@@ -6,24 +6,30 @@ import (
 	"fmt"
 
 	"github.com/ex0rcist/gophermart/internal/config"
-	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage/migrator"
 	"github.com/ex0rcist/gophermart/internal/storage/tracer"
-	"github.com/golang-migrate/migrate/v4"
 	"github.com/jackc/pgx/v5/pgxpool"
-
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 var ErrRecordNotFound = errors.New("record not found")
+var ErrRecordExists = errors.New("record already exists")
 var _ IPGXStorage = (*PGXStorage)(nil)
 
+// хранилище, не зависящее от СУБД; репозитории берут транзакцию из контекста WithinTx
+type IStorage interface {
+	WithinTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
+	Close()
+}
+
+// хранилище на пуле PostgreSQL: пул нужен для репозиториев pgx и LISTEN/NOTIFY,
+// поэтому его наличие проверяется приведением типа
 type IPGXStorage interface {
+	IStorage
 	GetPool() IPGXPool
-	Close()
 }
 
 type PGXStorage struct {
+	*TxManager
 	pool IPGXPool
 }
 
@@ -31,8 +37,8 @@ func NewPGXStorage(config config.DB, pool IPGXPool, migrate bool) (IPGXStorage,
 	var err error
 
 	if migrate {
-		if err := runMigrations(config); err != nil {
-			return nil, fmt.Errorf("runMigrations() failed: %w", err)
+		if err := migrator.Up(config); err != nil {
+			return nil, fmt.Errorf("migrator.Up() failed: %w", err)
 		}
 	}
 
@@ -43,7 +49,7 @@ func NewPGXStorage(config config.DB, pool IPGXPool, migrate bool) (IPGXStorage,
 		}
 	}
 
-	return &PGXStorage{pool: pool}, err
+	return &PGXStorage{TxManager: NewTxManager(pool), pool: pool}, err
 }
 
 func (s *PGXStorage) GetPool() IPGXPool {
@@ -74,31 +80,3 @@ func createPool(ctx context.Context, config config.DB) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
-
-func runMigrations(config config.DB) error {
-	migrator, err := migrate.New(config.MigrationsSource, config.DSN)
-	if err != nil {
-		return fmt.Errorf("migrate.New() failed: %w", err)
-	}
-
-	err = migrator.Up()
-	if err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			logging.LogInfo("migrations: no change")
-			return nil
-		}
-		return fmt.Errorf("migrations failed: %w", err)
-	}
-
-	defer func() {
-		srcErr, dbErr := migrator.Close()
-		if srcErr != nil {
-			logging.LogError(srcErr, "failed closing migrator", srcErr.Error())
-		}
-		if dbErr != nil {
-			logging.LogError(dbErr, "failed closing migrator", dbErr.Error())
-		}
-	}()
-
-	return nil
-}
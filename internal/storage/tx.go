@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var _ ITxManager = (*TxManager)(nil)
+
+// общие для пула и транзакции методы, которыми пользуются репозитории
+type IPGXQuerier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type ITxManager interface {
+	WithinTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
+}
+
+type TxOptions struct {
+	IsoLevel pgx.TxIsoLevel
+	ReadOnly bool
+
+	// сколько раз повторить fn при конфликте сериализации или deadlock
+	MaxRetries int
+}
+
+// для транзакций, которые блокируют строки и могут конфликтовать с параллельными
+var DefaultTxOptions = TxOptions{MaxRetries: 3}
+
+type txKey struct{}
+
+// выполняет fn в транзакции, которая передается репозиториям через контекст
+type TxManager struct {
+	pool IPGXPool
+}
+
+func NewTxManager(pool IPGXPool) *TxManager {
+	return &TxManager{pool: pool}
+}
+
+// fn, вызванная внутри другой транзакции, выполняется в ней же; транзакция откатывается,
+// если fn вернула ошибку или запаниковала. fn может выполняться повторно, поэтому не должна
+// иметь побочных эффектов вне базы
+func (m *TxManager) WithinTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	if HasTx(ctx) {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		err = m.runTx(ctx, opts, fn)
+		if !isRetryable(err) {
+			return err
+		}
+
+		logging.LogWarnCtx(ctx, fmt.Sprintf("storage: tx conflict, attempt %d of %d: %s", attempt+1, opts.MaxRetries+1, err))
+	}
+
+	return err
+}
+
+func (m *TxManager) runTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) (err error) {
+	txOpts := pgx.TxOptions{IsoLevel: opts.IsoLevel}
+	if opts.ReadOnly {
+		txOpts.AccessMode = pgx.ReadOnly
+	}
+
+	tx, err := m.pool.BeginTx(ctx, txOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		rbErr := tx.Rollback(ctx)
+		if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			logging.LogErrorCtx(ctx, rbErr, "storage: WithinTx(): error rolling tx back")
+		}
+	}()
+
+	if err = fn(ContextWithTx(ctx, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+func TxFromContext(ctx context.Context) pgx.Tx {
+	tx, _ := ctx.Value(txKey{}).(pgx.Tx)
+	return tx
+}
+
+func HasTx(ctx context.Context) bool {
+	return TxFromContext(ctx) != nil
+}
+
+// транзакция из контекста, если репозиторий вызван внутри WithinTx, иначе пул
+func Querier(ctx context.Context, pool IPGXPool) IPGXQuerier {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return pool
+}
+
+// конфликт сериализации или deadlock: транзакцию можно повторить целиком
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
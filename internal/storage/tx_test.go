@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTxManager_WithinTx_Commit(t *testing.T) {
+	pool := NewPGXPoolMock()
+	tx := new(PGXTxMock)
+
+	pool.On("BeginTx", mock.Anything).Return(tx, nil).Once()
+	tx.On("Commit", mock.Anything).Return(nil).Once()
+	tx.On("Rollback", mock.Anything).Return(pgx.ErrTxClosed).Once()
+
+	err := NewTxManager(pool).WithinTx(context.Background(), DefaultTxOptions, func(ctx context.Context) error {
+		assert.Equal(t, tx, TxFromContext(ctx))
+		return nil
+	})
+
+	assert.NoError(t, err)
+	pool.AssertExpectations(t)
+	tx.AssertExpectations(t)
+}
+
+func TestTxManager_WithinTx_RollbackOnError(t *testing.T) {
+	pool := NewPGXPoolMock()
+	tx := new(PGXTxMock)
+	expectedErr := errors.New("fn error")
+
+	pool.On("BeginTx", mock.Anything).Return(tx, nil).Once()
+	tx.On("Rollback", mock.Anything).Return(nil).Once()
+
+	err := NewTxManager(pool).WithinTx(context.Background(), DefaultTxOptions, func(_ context.Context) error {
+		return expectedErr
+	})
+
+	assert.Equal(t, expectedErr, err)
+	tx.AssertNotCalled(t, "Commit", mock.Anything)
+	tx.AssertExpectations(t)
+}
+
+func TestTxManager_WithinTx_BeginError(t *testing.T) {
+	pool := NewPGXPoolMock()
+	expectedErr := errors.New("begin error")
+
+	pool.On("BeginTx", mock.Anything).Return((*PGXTxMock)(nil), expectedErr).Once()
+
+	called := false
+	err := NewTxManager(pool).WithinTx(context.Background(), DefaultTxOptions, func(_ context.Context) error {
+		called = true
+		return nil
+	})
+
+	assert.Equal(t, expectedErr, err)
+	assert.False(t, called)
+}
+
+func TestTxManager_WithinTx_RetriesSerializationFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		retries  int
+		calls    int
+		hasError bool
+	}{
+		{"serialization failure is retried", "40001", 3, 2, false},
+		{"deadlock is retried", "40P01", 3, 2, false},
+		{"retries are exhausted", "40001", 1, 2, true},
+		{"other errors are not retried", "23505", 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewPGXPoolMock()
+			tx := new(PGXTxMock)
+
+			pool.On("BeginTx", mock.Anything).Return(tx, nil)
+			tx.On("Commit", mock.Anything).Return(nil)
+			tx.On("Rollback", mock.Anything).Return(nil)
+
+			calls := 0
+			opts := TxOptions{IsoLevel: pgx.Serializable, MaxRetries: tt.retries}
+			err := NewTxManager(pool).WithinTx(context.Background(), opts, func(_ context.Context) error {
+				calls++
+				if calls == 1 || tt.hasError {
+					return &pgconn.PgError{Code: tt.code}
+				}
+				return nil
+			})
+
+			assert.Equal(t, tt.calls, calls)
+			assert.Equal(t, tt.hasError, err != nil)
+		})
+	}
+}
+
+func TestTxManager_WithinTx_Nested(t *testing.T) {
+	pool := NewPGXPoolMock()
+	tx := new(PGXTxMock)
+
+	// вложенный вызов не открывает новую транзакцию
+	pool.On("BeginTx", mock.Anything).Return(tx, nil).Once()
+	tx.On("Commit", mock.Anything).Return(nil).Once()
+	tx.On("Rollback", mock.Anything).Return(pgx.ErrTxClosed).Once()
+
+	manager := NewTxManager(pool)
+	err := manager.WithinTx(context.Background(), DefaultTxOptions, func(ctx context.Context) error {
+		return manager.WithinTx(ctx, DefaultTxOptions, func(inner context.Context) error {
+			assert.Equal(t, tx, TxFromContext(inner))
+			return nil
+		})
+	})
+
+	assert.NoError(t, err)
+	pool.AssertExpectations(t)
+	tx.AssertExpectations(t)
+}
+
+func TestQuerier(t *testing.T) {
+	pool := NewPGXPoolMock()
+	tx := new(PGXTxMock)
+
+	assert.Equal(t, pool, Querier(context.Background(), pool))
+	assert.Equal(t, tx, Querier(ContextWithTx(context.Background(), tx), pool))
+	assert.False(t, HasTx(context.Background()))
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type IAccrualCallListUsecase interface {
+	Call(ctx context.Context, number string) ([]*AccrualCallResult, error)
+}
+
+type AccrualCallResult struct {
+	Provider   string               `json:"provider"`
+	URL        string               `json:"url"`
+	HTTPStatus int                  `json:"http_status,omitempty"`
+	Response   string               `json:"response,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	LatencyMs  int64                `json:"latency_ms"`
+	RequestID  string               `json:"request_id,omitempty"`
+	CreatedAt  entities.RFC3339Time `json:"created_at"`
+}
+
+func newAccrualCallResult(c *domain.AccrualCall) *AccrualCallResult {
+	return &AccrualCallResult{
+		Provider:   c.Provider,
+		URL:        c.URL,
+		HTTPStatus: c.HTTPStatus,
+		Response:   c.ResponseBody,
+		Error:      c.Error,
+		LatencyMs:  c.Latency.Milliseconds(),
+		RequestID:  c.RequestID,
+		CreatedAt:  entities.RFC3339Time(c.CreatedAt),
+	}
+}
+
+// история обращений к системам начислений по заказу
+type accrualCallListUsecase struct {
+	storage        storage.IStorage
+	repo           repository.IAccrualCallRepository
+	contextTimeout time.Duration
+}
+
+func NewAccrualCallListUsecase(storage storage.IStorage, repo repository.IAccrualCallRepository, timeout time.Duration) IAccrualCallListUsecase {
+	return &accrualCallListUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+func (uc *accrualCallListUsecase) Call(ctx context.Context, number string) ([]*AccrualCallResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	calls, err := uc.repo.AccrualCallListByOrderNumber(tCtx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*AccrualCallResult, 0, len(calls))
+	for _, c := range calls {
+		result = append(result, newAccrualCallResult(c))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccrualCallListUsecase_Call_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIAccrualCallRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	calls := []*domain.AccrualCall{
+		{OrderNumber: "12345678903", Provider: "default", HTTPStatus: 500, Latency: 120 * time.Millisecond, CreatedAt: time.Now()},
+		{OrderNumber: "12345678903", Provider: "default", Error: "connection refused", CreatedAt: time.Now()},
+	}
+
+	mockRepo.EXPECT().AccrualCallListByOrderNumber(gomock.Any(), "12345678903").Return(calls, nil)
+
+	uc := NewAccrualCallListUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), "12345678903")
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, 500, result[0].HTTPStatus)
+	assert.Equal(t, int64(120), result[0].LatencyMs)
+	assert.Equal(t, "connection refused", result[1].Error)
+}
+
+func TestAccrualCallListUsecase_Call_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIAccrualCallRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	expectedError := errors.New("database error")
+	mockRepo.EXPECT().AccrualCallListByOrderNumber(gomock.Any(), "12345678903").Return(nil, expectedError)
+
+	uc := NewAccrualCallListUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), "12345678903")
+
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, result)
+}
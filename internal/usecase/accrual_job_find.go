@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+var ErrAccrualJobNotFound = errors.New("accrual job not found")
+
+type IAccrualJobFindUsecase interface {
+	Call(ctx context.Context, number string) (*AccrualJobResult, error)
+}
+
+type accrualJobFindUsecase struct {
+	storage        storage.IStorage
+	repo           repository.IAccrualJobRepository
+	contextTimeout time.Duration
+}
+
+func NewAccrualJobFindUsecase(storage storage.IStorage, repo repository.IAccrualJobRepository, timeout time.Duration) IAccrualJobFindUsecase {
+	return &accrualJobFindUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+func (uc *accrualJobFindUsecase) Call(ctx context.Context, number string) (*AccrualJobResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	job, err := uc.repo.AccrualJobFindByOrderNumber(tCtx, number)
+	if err != nil {
+		if err == storage.ErrRecordNotFound {
+			return nil, ErrAccrualJobNotFound
+		}
+		return nil, err
+	}
+
+	return newAccrualJobResult(job), nil
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type IAccrualJobListUsecase interface {
+	Call(ctx context.Context) ([]*AccrualJobResult, error)
+}
+
+type AccrualJobResult struct {
+	OrderNumber    string                `json:"order"`
+	OrderStatus    domain.OrderStatus    `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	Failures       int                   `json:"failures"`
+	NextAttemptAt  entities.RFC3339Time  `json:"next_attempt_at"`
+	LastError      string                `json:"last_error,omitempty"`
+	LastHTTPStatus int                   `json:"last_http_status,omitempty"`
+	LastResponse   string                `json:"last_response,omitempty"`
+	RequestID      string                `json:"request_id,omitempty"`
+	DeadAt         *entities.RFC3339Time `json:"dead_at,omitempty"`
+	UpdatedAt      entities.RFC3339Time  `json:"updated_at"`
+}
+
+func newAccrualJobResult(j *domain.AccrualJob) *AccrualJobResult {
+	res := &AccrualJobResult{
+		OrderNumber:    j.Order.Number,
+		OrderStatus:    j.Order.Status,
+		Attempts:       j.Attempts,
+		Failures:       j.Failures,
+		NextAttemptAt:  entities.RFC3339Time(j.NextAttemptAt),
+		LastError:      j.LastError,
+		LastHTTPStatus: j.LastHTTPStatus,
+		LastResponse:   j.LastResponse,
+		RequestID:      j.RequestID,
+		UpdatedAt:      entities.RFC3339Time(j.UpdatedAt),
+	}
+
+	if j.DeadAt != nil {
+		deadAt := entities.RFC3339Time(*j.DeadAt)
+		res.DeadAt = &deadAt
+	}
+
+	return res
+}
+
+// список задач в dead-letter
+type accrualJobListUsecase struct {
+	storage        storage.IStorage
+	repo           repository.IAccrualJobRepository
+	contextTimeout time.Duration
+}
+
+func NewAccrualJobListUsecase(storage storage.IStorage, repo repository.IAccrualJobRepository, timeout time.Duration) IAccrualJobListUsecase {
+	return &accrualJobListUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+func (uc *accrualJobListUsecase) Call(ctx context.Context) ([]*AccrualJobResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	jobs, err := uc.repo.AccrualJobListDead(tCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*AccrualJobResult, 0, len(jobs))
+	for _, j := range jobs {
+		result = append(result, newAccrualJobResult(j))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+var ErrAccrualJobNotDead = errors.New("accrual job is not in dead-letter")
+
+type IAccrualJobRequeueUsecase interface {
+	Call(ctx context.Context, number string) (*AccrualJobResult, error)
+}
+
+type accrualJobRequeueUsecase struct {
+	storage        storage.IStorage
+	repo           repository.IAccrualJobRepository
+	contextTimeout time.Duration
+}
+
+func NewAccrualJobRequeueUsecase(storage storage.IStorage, repo repository.IAccrualJobRepository, timeout time.Duration) IAccrualJobRequeueUsecase {
+	return &accrualJobRequeueUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+func (uc *accrualJobRequeueUsecase) Call(ctx context.Context, number string) (*AccrualJobResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	job, err := uc.repo.AccrualJobFindByOrderNumber(tCtx, number)
+	if err != nil {
+		if err == storage.ErrRecordNotFound {
+			return nil, ErrAccrualJobNotFound
+		}
+		return nil, err
+	}
+
+	if !job.IsDead() {
+		return nil, ErrAccrualJobNotDead
+	}
+
+	// задачу могли вернуть в очередь параллельным запросом
+	err = uc.repo.AccrualJobRequeue(tCtx, job.OrderID)
+	if err != nil {
+		if err == storage.ErrRecordNotFound {
+			return nil, ErrAccrualJobNotDead
+		}
+		return nil, err
+	}
+
+	logging.LogInfoCtx(ctx, "accrual job requeued, order="+number)
+
+	job, err = uc.repo.AccrualJobFindByOrderNumber(tCtx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAccrualJobResult(job), nil
+}
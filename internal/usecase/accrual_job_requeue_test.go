@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccrualJobRequeueUsecase_Call_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	deadAt := time.Now()
+	order := &domain.Order{ID: 7, Number: "12345678903", Status: domain.OrderStatusNew}
+	dead := &domain.AccrualJob{OrderID: 7, Order: order, Failures: 10, DeadAt: &deadAt}
+	requeued := &domain.AccrualJob{OrderID: 7, Order: order}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().AccrualJobFindByOrderNumber(gomock.Any(), "12345678903").Return(dead, nil),
+		mockRepo.EXPECT().AccrualJobRequeue(gomock.Any(), domain.OrderID(7)).Return(nil),
+		mockRepo.EXPECT().AccrualJobFindByOrderNumber(gomock.Any(), "12345678903").Return(requeued, nil),
+	)
+
+	uc := NewAccrualJobRequeueUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), "12345678903")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678903", result.OrderNumber)
+	assert.Nil(t, result.DeadAt)
+	assert.Equal(t, 0, result.Failures)
+}
+
+func TestAccrualJobRequeueUsecase_Call_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockRepo.EXPECT().AccrualJobFindByOrderNumber(gomock.Any(), "12345678903").Return(nil, storage.ErrRecordNotFound)
+
+	uc := NewAccrualJobRequeueUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	_, err := uc.Call(context.Background(), "12345678903")
+
+	assert.Equal(t, ErrAccrualJobNotFound, err)
+}
+
+func TestAccrualJobRequeueUsecase_Call_NotDead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIAccrualJobRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	job := &domain.AccrualJob{OrderID: 7, Order: &domain.Order{ID: 7, Number: "12345678903"}}
+	mockRepo.EXPECT().AccrualJobFindByOrderNumber(gomock.Any(), "12345678903").Return(job, nil)
+	mockRepo.EXPECT().AccrualJobRequeue(gomock.Any(), gomock.Any()).Times(0)
+
+	uc := NewAccrualJobRequeueUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	_, err := uc.Call(context.Background(), "12345678903")
+
+	assert.Equal(t, ErrAccrualJobNotDead, err)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/accrual_call_list.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/accrual_call_list.go -destination=internal/usecase/mocks/accrual_call_list_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAccrualCallListUsecase is a mock of IAccrualCallListUsecase interface.
+type MockIAccrualCallListUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccrualCallListUsecaseMockRecorder
+}
+
+// MockIAccrualCallListUsecaseMockRecorder is the mock recorder for MockIAccrualCallListUsecase.
+type MockIAccrualCallListUsecaseMockRecorder struct {
+	mock *MockIAccrualCallListUsecase
+}
+
+// NewMockIAccrualCallListUsecase creates a new mock instance.
+func NewMockIAccrualCallListUsecase(ctrl *gomock.Controller) *MockIAccrualCallListUsecase {
+	mock := &MockIAccrualCallListUsecase{ctrl: ctrl}
+	mock.recorder = &MockIAccrualCallListUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccrualCallListUsecase) EXPECT() *MockIAccrualCallListUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIAccrualCallListUsecase) Call(ctx context.Context, number string) ([]*usecase.AccrualCallResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, number)
+	ret0, _ := ret[0].([]*usecase.AccrualCallResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIAccrualCallListUsecaseMockRecorder) Call(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIAccrualCallListUsecase)(nil).Call), ctx, number)
+}
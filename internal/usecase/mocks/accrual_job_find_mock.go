@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/accrual_job_find.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/accrual_job_find.go -destination=internal/usecase/mocks/accrual_job_find_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAccrualJobFindUsecase is a mock of IAccrualJobFindUsecase interface.
+type MockIAccrualJobFindUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccrualJobFindUsecaseMockRecorder
+}
+
+// MockIAccrualJobFindUsecaseMockRecorder is the mock recorder for MockIAccrualJobFindUsecase.
+type MockIAccrualJobFindUsecaseMockRecorder struct {
+	mock *MockIAccrualJobFindUsecase
+}
+
+// NewMockIAccrualJobFindUsecase creates a new mock instance.
+func NewMockIAccrualJobFindUsecase(ctrl *gomock.Controller) *MockIAccrualJobFindUsecase {
+	mock := &MockIAccrualJobFindUsecase{ctrl: ctrl}
+	mock.recorder = &MockIAccrualJobFindUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccrualJobFindUsecase) EXPECT() *MockIAccrualJobFindUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIAccrualJobFindUsecase) Call(ctx context.Context, number string) (*usecase.AccrualJobResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, number)
+	ret0, _ := ret[0].(*usecase.AccrualJobResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIAccrualJobFindUsecaseMockRecorder) Call(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIAccrualJobFindUsecase)(nil).Call), ctx, number)
+}
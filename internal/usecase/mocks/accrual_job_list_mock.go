@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/accrual_job_list.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/accrual_job_list.go -destination=internal/usecase/mocks/accrual_job_list_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAccrualJobListUsecase is a mock of IAccrualJobListUsecase interface.
+type MockIAccrualJobListUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccrualJobListUsecaseMockRecorder
+}
+
+// MockIAccrualJobListUsecaseMockRecorder is the mock recorder for MockIAccrualJobListUsecase.
+type MockIAccrualJobListUsecaseMockRecorder struct {
+	mock *MockIAccrualJobListUsecase
+}
+
+// NewMockIAccrualJobListUsecase creates a new mock instance.
+func NewMockIAccrualJobListUsecase(ctrl *gomock.Controller) *MockIAccrualJobListUsecase {
+	mock := &MockIAccrualJobListUsecase{ctrl: ctrl}
+	mock.recorder = &MockIAccrualJobListUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccrualJobListUsecase) EXPECT() *MockIAccrualJobListUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIAccrualJobListUsecase) Call(ctx context.Context) ([]*usecase.AccrualJobResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx)
+	ret0, _ := ret[0].([]*usecase.AccrualJobResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIAccrualJobListUsecaseMockRecorder) Call(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIAccrualJobListUsecase)(nil).Call), ctx)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/accrual_job_requeue.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/accrual_job_requeue.go -destination=internal/usecase/mocks/accrual_job_requeue_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAccrualJobRequeueUsecase is a mock of IAccrualJobRequeueUsecase interface.
+type MockIAccrualJobRequeueUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccrualJobRequeueUsecaseMockRecorder
+}
+
+// MockIAccrualJobRequeueUsecaseMockRecorder is the mock recorder for MockIAccrualJobRequeueUsecase.
+type MockIAccrualJobRequeueUsecaseMockRecorder struct {
+	mock *MockIAccrualJobRequeueUsecase
+}
+
+// NewMockIAccrualJobRequeueUsecase creates a new mock instance.
+func NewMockIAccrualJobRequeueUsecase(ctrl *gomock.Controller) *MockIAccrualJobRequeueUsecase {
+	mock := &MockIAccrualJobRequeueUsecase{ctrl: ctrl}
+	mock.recorder = &MockIAccrualJobRequeueUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAccrualJobRequeueUsecase) EXPECT() *MockIAccrualJobRequeueUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIAccrualJobRequeueUsecase) Call(ctx context.Context, number string) (*usecase.AccrualJobResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, number)
+	ret0, _ := ret[0].(*usecase.AccrualJobResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIAccrualJobRequeueUsecaseMockRecorder) Call(ctx, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIAccrualJobRequeueUsecase)(nil).Call), ctx, number)
+}
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/ex0rcist/gophermart/internal/domain"
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,6 +56,21 @@ func (mr *MockIOrderCreateUsecaseMockRecorder) Create(ctx, user, number any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIOrderCreateUsecase)(nil).Create), ctx, user, number)
 }
 
+// CreateWithPurchase mocks base method.
+func (m *MockIOrderCreateUsecase) CreateWithPurchase(ctx context.Context, req usecase.OrderCreateRequest) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithPurchase", ctx, req)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithPurchase indicates an expected call of CreateWithPurchase.
+func (mr *MockIOrderCreateUsecaseMockRecorder) CreateWithPurchase(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithPurchase", reflect.TypeOf((*MockIOrderCreateUsecase)(nil).CreateWithPurchase), ctx, req)
+}
+
 // OrderFindByNumber mocks base method.
 func (m *MockIOrderCreateUsecase) OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/order_review_list.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/order_review_list.go -destination=internal/usecase/mocks/order_review_list_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIOrderReviewListUsecase is a mock of IOrderReviewListUsecase interface.
+type MockIOrderReviewListUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOrderReviewListUsecaseMockRecorder
+}
+
+// MockIOrderReviewListUsecaseMockRecorder is the mock recorder for MockIOrderReviewListUsecase.
+type MockIOrderReviewListUsecaseMockRecorder struct {
+	mock *MockIOrderReviewListUsecase
+}
+
+// NewMockIOrderReviewListUsecase creates a new mock instance.
+func NewMockIOrderReviewListUsecase(ctrl *gomock.Controller) *MockIOrderReviewListUsecase {
+	mock := &MockIOrderReviewListUsecase{ctrl: ctrl}
+	mock.recorder = &MockIOrderReviewListUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOrderReviewListUsecase) EXPECT() *MockIOrderReviewListUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIOrderReviewListUsecase) Call(ctx context.Context) ([]*usecase.OrderReviewResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx)
+	ret0, _ := ret[0].([]*usecase.OrderReviewResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIOrderReviewListUsecaseMockRecorder) Call(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIOrderReviewListUsecase)(nil).Call), ctx)
+}
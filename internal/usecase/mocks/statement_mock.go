@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/statement.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/statement.go -destination=internal/usecase/mocks/statement_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIStatementUsecase is a mock of IStatementUsecase interface.
+type MockIStatementUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStatementUsecaseMockRecorder
+}
+
+// MockIStatementUsecaseMockRecorder is the mock recorder for MockIStatementUsecase.
+type MockIStatementUsecaseMockRecorder struct {
+	mock *MockIStatementUsecase
+}
+
+// NewMockIStatementUsecase creates a new mock instance.
+func NewMockIStatementUsecase(ctrl *gomock.Controller) *MockIStatementUsecase {
+	mock := &MockIStatementUsecase{ctrl: ctrl}
+	mock.recorder = &MockIStatementUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStatementUsecase) EXPECT() *MockIStatementUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIStatementUsecase) Call(ctx context.Context, user *domain.User, period string) (*usecase.StatementResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, user, period)
+	ret0, _ := ret[0].(*usecase.StatementResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIStatementUsecaseMockRecorder) Call(ctx, user, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIStatementUsecase)(nil).Call), ctx, user, period)
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/transfer_list.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/transfer_list.go -destination=internal/usecase/mocks/transfer_list_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockITransferListUsecase is a mock of ITransferListUsecase interface.
+type MockITransferListUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransferListUsecaseMockRecorder
+}
+
+// MockITransferListUsecaseMockRecorder is the mock recorder for MockITransferListUsecase.
+type MockITransferListUsecaseMockRecorder struct {
+	mock *MockITransferListUsecase
+}
+
+// NewMockITransferListUsecase creates a new mock instance.
+func NewMockITransferListUsecase(ctrl *gomock.Controller) *MockITransferListUsecase {
+	mock := &MockITransferListUsecase{ctrl: ctrl}
+	mock.recorder = &MockITransferListUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransferListUsecase) EXPECT() *MockITransferListUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockITransferListUsecase) Call(ctx context.Context, user *domain.User) ([]*usecase.TransferListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, user)
+	ret0, _ := ret[0].([]*usecase.TransferListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockITransferListUsecaseMockRecorder) Call(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockITransferListUsecase)(nil).Call), ctx, user)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/user_change_password.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/user_change_password.go -destination=internal/usecase/mocks/user_change_password_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIChangePasswordUsecase is a mock of IChangePasswordUsecase interface.
+type MockIChangePasswordUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIChangePasswordUsecaseMockRecorder
+}
+
+// MockIChangePasswordUsecaseMockRecorder is the mock recorder for MockIChangePasswordUsecase.
+type MockIChangePasswordUsecaseMockRecorder struct {
+	mock *MockIChangePasswordUsecase
+}
+
+// NewMockIChangePasswordUsecase creates a new mock instance.
+func NewMockIChangePasswordUsecase(ctrl *gomock.Controller) *MockIChangePasswordUsecase {
+	mock := &MockIChangePasswordUsecase{ctrl: ctrl}
+	mock.recorder = &MockIChangePasswordUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIChangePasswordUsecase) EXPECT() *MockIChangePasswordUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIChangePasswordUsecase) Call(ctx context.Context, user *domain.User, form usecase.ChangePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, user, form)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIChangePasswordUsecaseMockRecorder) Call(ctx, user, form any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIChangePasswordUsecase)(nil).Call), ctx, user, form)
+}
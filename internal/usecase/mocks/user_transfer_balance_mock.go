@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/user_transfer_balance.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/user_transfer_balance.go -destination=internal/usecase/mocks/user_transfer_balance_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/ex0rcist/gophermart/internal/domain"
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockITransferBalanceUsecase is a mock of ITransferBalanceUsecase interface.
+type MockITransferBalanceUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransferBalanceUsecaseMockRecorder
+}
+
+// MockITransferBalanceUsecaseMockRecorder is the mock recorder for MockITransferBalanceUsecase.
+type MockITransferBalanceUsecaseMockRecorder struct {
+	mock *MockITransferBalanceUsecase
+}
+
+// NewMockITransferBalanceUsecase creates a new mock instance.
+func NewMockITransferBalanceUsecase(ctrl *gomock.Controller) *MockITransferBalanceUsecase {
+	mock := &MockITransferBalanceUsecase{ctrl: ctrl}
+	mock.recorder = &MockITransferBalanceUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransferBalanceUsecase) EXPECT() *MockITransferBalanceUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockITransferBalanceUsecase) Call(ctx context.Context, user *domain.User, req usecase.TransferBalanceRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, user, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockITransferBalanceUsecaseMockRecorder) Call(ctx, user, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockITransferBalanceUsecase)(nil).Call), ctx, user, req)
+}
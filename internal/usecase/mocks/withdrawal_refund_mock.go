@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/usecase/withdrawal_refund.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/usecase/withdrawal_refund.go -destination=internal/usecase/mocks/withdrawal_refund_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	usecase "github.com/ex0rcist/gophermart/internal/usecase"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIRefundWithdrawalUsecase is a mock of IRefundWithdrawalUsecase interface.
+type MockIRefundWithdrawalUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRefundWithdrawalUsecaseMockRecorder
+}
+
+// MockIRefundWithdrawalUsecaseMockRecorder is the mock recorder for MockIRefundWithdrawalUsecase.
+type MockIRefundWithdrawalUsecaseMockRecorder struct {
+	mock *MockIRefundWithdrawalUsecase
+}
+
+// NewMockIRefundWithdrawalUsecase creates a new mock instance.
+func NewMockIRefundWithdrawalUsecase(ctrl *gomock.Controller) *MockIRefundWithdrawalUsecase {
+	mock := &MockIRefundWithdrawalUsecase{ctrl: ctrl}
+	mock.recorder = &MockIRefundWithdrawalUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRefundWithdrawalUsecase) EXPECT() *MockIRefundWithdrawalUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockIRefundWithdrawalUsecase) Call(ctx context.Context, form usecase.RefundWithdrawalRequest) (*usecase.WithdrawalListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, form)
+	ret0, _ := ret[0].(*usecase.WithdrawalListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockIRefundWithdrawalUsecaseMockRecorder) Call(ctx, form any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockIRefundWithdrawalUsecase)(nil).Call), ctx, form)
+}
@@ -9,37 +9,99 @@ import (
 	"github.com/ex0rcist/gophermart/internal/storage"
 	"github.com/ex0rcist/gophermart/internal/storage/repository"
 	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/shopspring/decimal"
 )
 
 var ErrOrderNotFound = errors.New("order not found")
 var ErrOrderAlreadyRegistered = errors.New("order already registered")
 var ErrOrderConflict = errors.New("order number already registered by another user")
 var ErrInvalidOrderNumber = errors.New("invalid order number")
+var ErrInvalidAccrualEngine = errors.New("invalid accrual engine")
+var ErrInvalidPurchase = errors.New("invalid purchase item")
+var ErrUserNotFound = errors.New("user not found")
+
+// пределы позиции покупки: цена хранится в DECIMAL(10,2), количество - в INTEGER
+var (
+	maxPurchasePrice    = decimal.RequireFromString("99999999.99")
+	maxPurchaseQuantity = 1_000_000
+)
+
+// заказ с данными о покупке для встроенного расчета начислений; передается магазином
+// через служебный API от имени пользователя с логином Login, а не самим пользователем
+type OrderCreateRequest struct {
+	Login  string                `json:"login" binding:"required"`
+	Number string                `json:"order" binding:"required"`
+	Engine domain.AccrualEngine  `json:"accrual_engine"`
+	Store  string                `json:"store"`
+	Items  []PurchaseItemRequest `json:"items"`
+}
+
+type PurchaseItemRequest struct {
+	Product  string          `json:"product"`
+	Category string          `json:"category"`
+	Price    decimal.Decimal `json:"price"`
+	Quantity int             `json:"quantity"` // по умолчанию 1
+}
 
 type IOrderCreateUsecase interface {
 	Create(ctx context.Context, user *domain.User, number string) (*domain.Order, error)
+	CreateWithPurchase(ctx context.Context, req OrderCreateRequest) (*domain.Order, error)
 	OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error)
 }
 
 type orderCreateUsecase struct {
-	storage        storage.IPGXStorage
+	storage        storage.IStorage
 	repo           repository.IOrderRepository
+	userRepo       repository.IUserRepository
 	contextTimeout time.Duration
 }
 
-func NewOrderCreateUsecase(storage storage.IPGXStorage, repo repository.IOrderRepository, timeout time.Duration) IOrderCreateUsecase {
-	return &orderCreateUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+func NewOrderCreateUsecase(storage storage.IStorage, repo repository.IOrderRepository, userRepo repository.IUserRepository, timeout time.Duration) IOrderCreateUsecase {
+	return &orderCreateUsecase{storage: storage, repo: repo, userRepo: userRepo, contextTimeout: timeout}
 }
 
+// заказ, загруженный пользователем: без данных о покупке, способ расчета берется из конфигурации
 func (uc *orderCreateUsecase) Create(ctx context.Context, user *domain.User, number string) (*domain.Order, error) {
+	return uc.create(ctx, user, OrderCreateRequest{Number: number})
+}
+
+// заказ от магазина: способ расчета и данные о покупке принимаются только от доверенной стороны
+func (uc *orderCreateUsecase) CreateWithPurchase(ctx context.Context, req OrderCreateRequest) (*domain.Order, error) {
 	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
+	user, err := uc.userRepo.UserFindByLogin(tCtx, req.Login)
+	if err != nil {
+		if errors.Is(err, storage.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return uc.create(ctx, user, req)
+}
+
+func (uc *orderCreateUsecase) create(ctx context.Context, user *domain.User, req OrderCreateRequest) (*domain.Order, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	number := req.Number
+
 	// валидируем номер заказа
 	if !utils.LuhnCheck(number) {
 		return nil, ErrInvalidOrderNumber
 	}
 
+	if req.Engine != "" && !req.Engine.IsValid() {
+		return nil, ErrInvalidAccrualEngine
+	}
+
+	purchase, err := newPurchase(req)
+	if err != nil {
+		return nil, err
+	}
+
 	// ищем заказ по номеру
 	existingOrder, err := uc.OrderFindByNumber(tCtx, number)
 	if err != nil && err != ErrOrderNotFound {
@@ -55,7 +117,14 @@ func (uc *orderCreateUsecase) Create(ctx context.Context, user *domain.User, num
 		return nil, ErrOrderConflict
 	}
 
-	order, err := uc.repo.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: number, Status: domain.OrderStatusNew})
+	order, err := uc.repo.OrderCreate(ctx, domain.Order{
+		UserID:    user.ID,
+		Number:    number,
+		Status:    domain.OrderStatusNew,
+		RequestID: utils.RequestIDFromContext(ctx),
+		Engine:    req.Engine,
+		Purchase:  purchase,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +132,40 @@ func (uc *orderCreateUsecase) Create(ctx context.Context, user *domain.User, num
 	return order, nil
 }
 
+// покупка без товаров и магазина не сохраняется; магазин без товаров нужен для выбора
+// системы начислений. цена - не больше двух знаков после запятой
+// и в пределах столбца, количество - не больше maxPurchaseQuantity
+func newPurchase(req OrderCreateRequest) (*domain.Purchase, error) {
+	if len(req.Items) == 0 && req.Store == "" {
+		return nil, nil
+	}
+
+	purchase := &domain.Purchase{Store: req.Store, Items: make([]domain.PurchaseItem, 0, len(req.Items))}
+	for _, item := range req.Items {
+		if !item.Price.IsPositive() || item.Price.GreaterThan(maxPurchasePrice) || !item.Price.Equal(item.Price.Truncate(2)) {
+			return nil, ErrInvalidPurchase
+		}
+
+		if item.Quantity < 0 || item.Quantity > maxPurchaseQuantity {
+			return nil, ErrInvalidPurchase
+		}
+
+		quantity := item.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+
+		purchase.Items = append(purchase.Items, domain.PurchaseItem{
+			Product:  item.Product,
+			Category: item.Category,
+			Price:    item.Price,
+			Quantity: quantity,
+		})
+	}
+
+	return purchase, nil
+}
+
 func (uc *orderCreateUsecase) OrderFindByNumber(ctx context.Context, number string) (*domain.Order, error) {
 	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
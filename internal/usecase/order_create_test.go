@@ -9,6 +9,8 @@ import (
 	"github.com/ex0rcist/gophermart/internal/storage"
 	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
 	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -19,16 +21,19 @@ func TestOrderCreateUsecase_Create_Success(t *testing.T) {
 
 	mockRepo := mock_repository.NewMockIOrderRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	ctx := context.Background()
+	ctx := utils.ContextWithRequestID(context.Background(), "request-id")
 
 	user := &domain.User{ID: 1}
 	orderNumber := "12345678903" // валидный номер Luhn
 	order := &domain.Order{UserID: user.ID, Number: orderNumber, Status: domain.OrderStatusNew}
 
 	mockRepo.EXPECT().OrderFindByNumber(gomock.Any(), orderNumber).Return(nil, ErrOrderNotFound)
-	mockRepo.EXPECT().OrderCreate(gomock.Any(), gomock.Any()).Return(order, nil)
+	mockRepo.EXPECT().OrderCreate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o domain.Order) (*domain.Order, error) {
+		assert.Equal(t, "request-id", o.RequestID)
+		return order, nil
+	})
 
-	uc := NewOrderCreateUsecase(mockStorage, mockRepo, 5*time.Second)
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, nil, 5*time.Second)
 	result, err := uc.Create(ctx, user, orderNumber)
 
 	assert.NoError(t, err)
@@ -48,7 +53,7 @@ func TestOrderCreateUsecase_Create_InvalidOrderNumber(t *testing.T) {
 	user := &domain.User{ID: 1}
 	invalidOrderNumber := "1234567890" // невалидный номер Luhn
 
-	uc := NewOrderCreateUsecase(mockStorage, mockRepo, 5*time.Second)
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, nil, 5*time.Second)
 
 	result, err := uc.Create(ctx, user, invalidOrderNumber)
 
@@ -70,7 +75,7 @@ func TestOrderCreateUsecase_Create_OrderAlreadyRegistered(t *testing.T) {
 
 	mockRepo.EXPECT().OrderFindByNumber(gomock.Any(), orderNumber).Return(existingOrder, nil)
 
-	uc := NewOrderCreateUsecase(mockStorage, mockRepo, 5*time.Second)
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, nil, 5*time.Second)
 
 	result, err := uc.Create(ctx, user, orderNumber)
 
@@ -92,7 +97,7 @@ func TestOrderCreateUsecase_Create_OrderConflict(t *testing.T) {
 
 	mockRepo.EXPECT().OrderFindByNumber(gomock.Any(), orderNumber).Return(existingOrder, nil)
 
-	uc := NewOrderCreateUsecase(mockStorage, mockRepo, 5*time.Second)
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, nil, 5*time.Second)
 
 	result, err := uc.Create(ctx, user, orderNumber)
 
@@ -113,7 +118,7 @@ func TestOrderCreateUsecase_OrderFindByNumber_Success(t *testing.T) {
 
 	mockRepo.EXPECT().OrderFindByNumber(gomock.Any(), orderNumber).Return(expectedOrder, nil)
 
-	uc := NewOrderCreateUsecase(mockStorage, mockRepo, 5*time.Second)
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, nil, 5*time.Second)
 
 	result, err := uc.OrderFindByNumber(ctx, orderNumber)
 
@@ -133,7 +138,7 @@ func TestOrderCreateUsecase_OrderFindByNumber_NotFound(t *testing.T) {
 
 	mockRepo.EXPECT().OrderFindByNumber(gomock.Any(), orderNumber).Return(nil, storage.ErrRecordNotFound)
 
-	uc := NewOrderCreateUsecase(mockStorage, mockRepo, 5*time.Second)
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, nil, 5*time.Second)
 
 	result, err := uc.OrderFindByNumber(ctx, orderNumber)
 
@@ -141,3 +146,95 @@ func TestOrderCreateUsecase_OrderFindByNumber_NotFound(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Equal(t, ErrOrderNotFound, err)
 }
+
+func TestOrderCreateUsecase_CreateWithPurchase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+
+	user := &domain.User{ID: 1, Login: "me"}
+	req := OrderCreateRequest{
+		Login:  "me",
+		Number: "12345678903",
+		Engine: domain.AccrualEngineInternal,
+		Store:  "downtown",
+		Items: []PurchaseItemRequest{
+			{Product: "bork-k800", Category: "electronics", Price: decimal.NewFromInt(7000)},
+			{Product: "cup", Price: decimal.NewFromInt(100), Quantity: 3},
+		},
+	}
+
+	mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "me").Return(user, nil)
+	mockRepo.EXPECT().OrderFindByNumber(gomock.Any(), req.Number).Return(nil, storage.ErrRecordNotFound)
+	mockRepo.EXPECT().OrderCreate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o domain.Order) (*domain.Order, error) {
+		assert.Equal(t, user.ID, o.UserID)
+		assert.Equal(t, domain.AccrualEngineInternal, o.Engine)
+		assert.Equal(t, "downtown", o.Purchase.Store)
+		assert.Len(t, o.Purchase.Items, 2)
+		assert.Equal(t, 1, o.Purchase.Items[0].Quantity)
+		assert.Equal(t, 3, o.Purchase.Items[1].Quantity)
+		return &o, nil
+	})
+
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, mockUserRepo, 5*time.Second)
+	result, err := uc.CreateWithPurchase(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestOrderCreateUsecase_CreateWithPurchase_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      OrderCreateRequest
+		expected error
+	}{
+		{"unknown engine", OrderCreateRequest{Number: "12345678903", Engine: "magic"}, ErrInvalidAccrualEngine},
+		{"zero price", OrderCreateRequest{Number: "12345678903", Items: []PurchaseItemRequest{{Product: "cup"}}}, ErrInvalidPurchase},
+		{"negative quantity", OrderCreateRequest{Number: "12345678903", Items: []PurchaseItemRequest{{Price: decimal.NewFromInt(1), Quantity: -1}}}, ErrInvalidPurchase},
+		{"price out of column range", OrderCreateRequest{Number: "12345678903", Items: []PurchaseItemRequest{{Price: decimal.NewFromInt(100_000_000)}}}, ErrInvalidPurchase},
+		{"price with fractions of a cent", OrderCreateRequest{Number: "12345678903", Items: []PurchaseItemRequest{{Price: decimal.RequireFromString("9.999")}}}, ErrInvalidPurchase},
+		{"huge quantity", OrderCreateRequest{Number: "12345678903", Items: []PurchaseItemRequest{{Price: decimal.NewFromInt(1), Quantity: 1 << 32}}}, ErrInvalidPurchase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockIOrderRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+
+			tt.req.Login = "me"
+			mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "me").Return(&domain.User{ID: 1}, nil)
+
+			uc := NewOrderCreateUsecase(mockStorage, mockRepo, mockUserRepo, 5*time.Second)
+			result, err := uc.CreateWithPurchase(context.Background(), tt.req)
+
+			assert.Equal(t, tt.expected, err)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestOrderCreateUsecase_CreateWithPurchase_UnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+
+	mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "ghost").Return(nil, storage.ErrRecordNotFound)
+
+	uc := NewOrderCreateUsecase(mockStorage, mockRepo, mockUserRepo, 5*time.Second)
+	result, err := uc.CreateWithPurchase(context.Background(), OrderCreateRequest{Login: "ghost", Number: "12345678903"})
+
+	assert.Equal(t, ErrUserNotFound, err)
+	assert.Nil(t, result)
+}
@@ -15,7 +15,7 @@ type IOrderListUsecase interface {
 }
 
 type orderListUsecase struct {
-	storage        storage.IPGXStorage
+	storage        storage.IStorage
 	repo           repository.IOrderRepository
 	contextTimeout time.Duration
 }
@@ -27,7 +27,7 @@ type OrderListResult struct {
 	CreatedAt entities.RFC3339Time `json:"uploaded_at"`
 }
 
-func NewOrderListUsecase(storage storage.IPGXStorage, repo repository.IOrderRepository, timeout time.Duration) IOrderListUsecase {
+func NewOrderListUsecase(storage storage.IStorage, repo repository.IOrderRepository, timeout time.Duration) IOrderListUsecase {
 	return &orderListUsecase{storage: storage, repo: repo, contextTimeout: timeout}
 }
 
@@ -44,6 +44,11 @@ func (uc *orderListUsecase) Call(ctx context.Context, user *domain.User) ([]*Ord
 	for _, o := range orders {
 		el := OrderListResult{Number: o.Number, Status: o.Status, CreatedAt: entities.RFC3339Time(o.CreatedAt)}
 
+		// для пользователя заказ на ручной проверке остается в обработке
+		if o.AwaitsReview() {
+			el.Status = domain.OrderStatusProcessing
+		}
+
 		if o.Status == domain.OrderStatusProcessed {
 			val := entities.GDecimal(o.Accrual)
 			el.Accrual = &val
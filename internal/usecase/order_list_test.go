@@ -51,6 +51,29 @@ func TestOrderListUsecase_Call_Success(t *testing.T) {
 	assert.Nil(t, result[1].Accrual)
 }
 
+func TestOrderListUsecase_Call_HidesReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	user := &domain.User{ID: 1}
+
+	orders := []*domain.Order{
+		{Number: "123456", Status: domain.OrderStatusNeedsReview, ReviewAccrual: decimal.NewFromInt(5000), CreatedAt: time.Now()},
+	}
+
+	mockRepo.EXPECT().OrderList(gomock.Any(), user.ID).Return(orders, nil)
+	uc := NewOrderListUsecase(mockStorage, mockRepo, 5*time.Second)
+	result, err := uc.Call(context.Background(), user)
+
+	// пользователь не видит ни статуса проверки, ни отложенной суммы
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, domain.OrderStatusProcessing, result[0].Status)
+	assert.Nil(t, result[0].Accrual)
+}
+
 func TestOrderListUsecase_Call_RepoError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type IOrderReviewListUsecase interface {
+	Call(ctx context.Context) ([]*OrderReviewResult, error)
+}
+
+type OrderReviewResult struct {
+	Number    string               `json:"number"`
+	UserID    domain.UserID        `json:"user_id"`
+	Accrual   entities.GDecimal    `json:"accrual"`
+	Reason    string               `json:"reason"`
+	UpdatedAt entities.RFC3339Time `json:"updated_at"`
+}
+
+// очередь заказов, начисления по которым ждут решения администратора
+type orderReviewListUsecase struct {
+	storage        storage.IStorage
+	repo           repository.IOrderRepository
+	contextTimeout time.Duration
+}
+
+func NewOrderReviewListUsecase(storage storage.IStorage, repo repository.IOrderRepository, timeout time.Duration) IOrderReviewListUsecase {
+	return &orderReviewListUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+func (uc *orderReviewListUsecase) Call(ctx context.Context) ([]*OrderReviewResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	orders, err := uc.repo.OrderListForReview(tCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*OrderReviewResult, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, &OrderReviewResult{
+			Number:    o.Number,
+			UserID:    o.UserID,
+			Accrual:   entities.GDecimal(o.ReviewAccrual),
+			Reason:    o.ReviewReason,
+			UpdatedAt: entities.RFC3339Time(o.UpdatedAt),
+		})
+	}
+
+	return result, nil
+}
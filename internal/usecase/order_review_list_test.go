@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrderReviewListUsecase_Call_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	orders := []*domain.Order{
+		{
+			UserID:        7,
+			Number:        "12345678903",
+			Status:        domain.OrderStatusNeedsReview,
+			ReviewAccrual: decimal.NewFromInt(1500),
+			ReviewReason:  "accrual 1500 exceeds max 1000 per order",
+			UpdatedAt:     time.Now(),
+		},
+	}
+
+	mockRepo.EXPECT().OrderListForReview(gomock.Any()).Return(orders, nil)
+
+	uc := NewOrderReviewListUsecase(mockStorage, mockRepo, 5*time.Second)
+	result, err := uc.Call(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "12345678903", result[0].Number)
+	assert.Equal(t, domain.UserID(7), result[0].UserID)
+	assert.Equal(t, entities.GDecimal(decimal.NewFromInt(1500)), result[0].Accrual)
+	assert.Equal(t, orders[0].ReviewReason, result[0].Reason)
+}
+
+func TestOrderReviewListUsecase_Call_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIOrderRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	expectedError := errors.New("database error")
+	mockRepo.EXPECT().OrderListForReview(gomock.Any()).Return(nil, expectedError)
+
+	uc := NewOrderReviewListUsecase(mockStorage, mockRepo, 5*time.Second)
+	result, err := uc.Call(context.Background())
+
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, result)
+}
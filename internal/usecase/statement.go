@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+const StatementPeriodLayout = "2006-01"
+
+var ErrInvalidStatementPeriod = errors.New("invalid statement period, expected yyyy-mm")
+var ErrStatementPeriodNotClosed = errors.New("statement period is not closed yet")
+
+type IStatementUsecase interface {
+	Call(ctx context.Context, user *domain.User, period string) (*StatementResult, error)
+}
+
+type StatementResult struct {
+	Period         string               `json:"period"`
+	OpeningBalance entities.GDecimal    `json:"opening_balance"`
+	Accruals       entities.GDecimal    `json:"accruals"`
+	Withdrawals    entities.GDecimal    `json:"withdrawals"`
+	Refunds        entities.GDecimal    `json:"refunds"`
+	TransfersIn    entities.GDecimal    `json:"transfers_in"`
+	TransfersOut   entities.GDecimal    `json:"transfers_out"`
+	Expirations    entities.GDecimal    `json:"expirations"`
+	ClosingBalance entities.GDecimal    `json:"closing_balance"`
+	GeneratedAt    entities.RFC3339Time `json:"generated_at"`
+}
+
+type statementUsecase struct {
+	storage        storage.IStorage
+	repo           repository.IStatementRepository
+	contextTimeout time.Duration
+}
+
+func NewStatementUsecase(storage storage.IStorage, repo repository.IStatementRepository, timeout time.Duration) IStatementUsecase {
+	return &statementUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+func (uc *statementUsecase) Call(ctx context.Context, user *domain.User, period string) (*StatementResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	from, err := time.ParseInLocation(StatementPeriodLayout, period, time.UTC)
+	if err != nil {
+		return nil, ErrInvalidStatementPeriod
+	}
+
+	// выписка формируется только за завершившийся месяц, иначе снимок устареет
+	to := from.AddDate(0, 1, 0)
+	if to.After(time.Now().UTC()) {
+		return nil, ErrStatementPeriodNotClosed
+	}
+
+	s, err := uc.repo.StatementFind(tCtx, user.ID, from)
+	if err == nil {
+		return newStatementResult(s), nil
+	}
+	if err != storage.ErrRecordNotFound {
+		return nil, err
+	}
+
+	s, err = uc.build(tCtx, user.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// при параллельном запросе сохранится только одна выписка,
+	// поэтому возвращаем ту, что оказалась в базе
+	if err = uc.repo.StatementCreate(tCtx, *s); err != nil {
+		logging.LogErrorCtx(ctx, err, "statementUsecase(): error saving statement")
+		return nil, err
+	}
+
+	s, err = uc.repo.StatementFind(tCtx, user.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStatementResult(s), nil
+}
+
+func (uc *statementUsecase) build(ctx context.Context, userID domain.UserID, from time.Time, to time.Time) (*domain.Statement, error) {
+	s, err := uc.repo.StatementTurnover(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Period = from
+
+	// входящий остаток берем из предыдущей выписки, чтобы выписки сходились между собой;
+	// если ее нет - считаем по всем операциям до начала периода
+	prev, err := uc.repo.StatementFind(ctx, userID, from.AddDate(0, -1, 0))
+	switch {
+	case err == nil:
+		s.OpeningBalance = prev.ClosingBalance
+	case err == storage.ErrRecordNotFound:
+		before, err := uc.repo.StatementTurnover(ctx, userID, time.Time{}, from)
+		if err != nil {
+			return nil, err
+		}
+		s.OpeningBalance = before.Turnover()
+	default:
+		return nil, err
+	}
+
+	s.ClosingBalance = s.OpeningBalance.Add(s.Turnover())
+
+	return s, nil
+}
+
+func newStatementResult(s *domain.Statement) *StatementResult {
+	return &StatementResult{
+		Period:         s.Period.Format(StatementPeriodLayout),
+		OpeningBalance: entities.GDecimal(s.OpeningBalance),
+		Accruals:       entities.GDecimal(s.Accruals),
+		Withdrawals:    entities.GDecimal(s.Withdrawals),
+		Refunds:        entities.GDecimal(s.Refunds),
+		TransfersIn:    entities.GDecimal(s.TransfersIn),
+		TransfersOut:   entities.GDecimal(s.TransfersOut),
+		Expirations:    entities.GDecimal(s.Expirations),
+		ClosingBalance: entities.GDecimal(s.ClosingBalance),
+		GeneratedAt:    entities.RFC3339Time(s.CreatedAt),
+	}
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	statementFrom = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	statementTo   = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestStatementUsecase_Call_ExistingSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIStatementRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	user := &domain.User{ID: 1}
+
+	snapshot := &domain.Statement{UserID: 1, Period: statementFrom, ClosingBalance: decimal.NewFromInt(42)}
+	mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom).Return(snapshot, nil)
+
+	uc := NewStatementUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), user, "2024-05")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-05", result.Period)
+	assert.Equal(t, entities.GDecimal(decimal.NewFromInt(42)), result.ClosingBalance)
+}
+
+func TestStatementUsecase_Call_OpeningFromPreviousSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIStatementRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	user := &domain.User{ID: 1}
+
+	turnover := &domain.Statement{
+		UserID:       1,
+		Accruals:     decimal.NewFromInt(100),
+		Withdrawals:  decimal.NewFromInt(40),
+		Refunds:      decimal.NewFromInt(10),
+		TransfersIn:  decimal.NewFromInt(5),
+		TransfersOut: decimal.NewFromInt(15),
+	}
+	prev := &domain.Statement{ClosingBalance: decimal.NewFromInt(200)}
+	saved := &domain.Statement{UserID: 1, Period: statementFrom, ClosingBalance: decimal.NewFromInt(260)}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom).Return(nil, storage.ErrRecordNotFound),
+		mockRepo.EXPECT().StatementTurnover(gomock.Any(), user.ID, statementFrom, statementTo).Return(turnover, nil),
+		mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom.AddDate(0, -1, 0)).Return(prev, nil),
+		mockRepo.EXPECT().StatementCreate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, s domain.Statement) error {
+				assert.Equal(t, statementFrom, s.Period)
+				assert.True(t, decimal.NewFromInt(200).Equal(s.OpeningBalance))
+				assert.True(t, decimal.NewFromInt(260).Equal(s.ClosingBalance))
+				return nil
+			},
+		),
+		mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom).Return(saved, nil),
+	)
+
+	uc := NewStatementUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), user, "2024-05")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.GDecimal(decimal.NewFromInt(260)), result.ClosingBalance)
+}
+
+func TestStatementUsecase_Call_OpeningFromHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIStatementRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	user := &domain.User{ID: 1}
+
+	turnover := &domain.Statement{UserID: 1, Accruals: decimal.NewFromInt(10)}
+	before := &domain.Statement{UserID: 1, Accruals: decimal.NewFromInt(70), Withdrawals: decimal.NewFromInt(20)}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom).Return(nil, storage.ErrRecordNotFound),
+		mockRepo.EXPECT().StatementTurnover(gomock.Any(), user.ID, statementFrom, statementTo).Return(turnover, nil),
+		mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom.AddDate(0, -1, 0)).Return(nil, storage.ErrRecordNotFound),
+		mockRepo.EXPECT().StatementTurnover(gomock.Any(), user.ID, time.Time{}, statementFrom).Return(before, nil),
+		mockRepo.EXPECT().StatementCreate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, s domain.Statement) error {
+				assert.True(t, decimal.NewFromInt(50).Equal(s.OpeningBalance))
+				assert.True(t, decimal.NewFromInt(60).Equal(s.ClosingBalance))
+				return nil
+			},
+		),
+		mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom).Return(&domain.Statement{Period: statementFrom}, nil),
+	)
+
+	uc := NewStatementUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	_, err := uc.Call(context.Background(), user, "2024-05")
+
+	assert.NoError(t, err)
+}
+
+func TestStatementUsecase_Call_InvalidPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIStatementRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	uc := NewStatementUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	for _, period := range []string{"2024-5", "2024-13", "05-2024", "abc"} {
+		_, err := uc.Call(context.Background(), &domain.User{ID: 1}, period)
+		assert.Equal(t, ErrInvalidStatementPeriod, err, period)
+	}
+}
+
+func TestStatementUsecase_Call_PeriodNotClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIStatementRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	uc := NewStatementUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	current := time.Now().UTC().Format(StatementPeriodLayout)
+	_, err := uc.Call(context.Background(), &domain.User{ID: 1}, current)
+
+	assert.Equal(t, ErrStatementPeriodNotClosed, err)
+}
+
+func TestStatementUsecase_Call_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIStatementRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	user := &domain.User{ID: 1}
+
+	expectedError := errors.New("database error")
+	mockRepo.EXPECT().StatementFind(gomock.Any(), user.ID, statementFrom).Return(nil, expectedError)
+
+	uc := NewStatementUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	_, err := uc.Call(context.Background(), user, "2024-05")
+
+	assert.Equal(t, expectedError, err)
+}
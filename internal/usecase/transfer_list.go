@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+)
+
+type TransferDirection string
+
+const (
+	TransferDirectionIncoming TransferDirection = "INCOMING"
+	TransferDirectionOutgoing TransferDirection = "OUTGOING"
+)
+
+type ITransferListUsecase interface {
+	Call(ctx context.Context, user *domain.User) ([]*TransferListResult, error)
+}
+
+type TransferListResult struct {
+	Direction TransferDirection    `json:"direction"`
+	Login     string               `json:"login"` // второй участник перевода
+	Amount    entities.GDecimal    `json:"sum"`
+	CreatedAt entities.RFC3339Time `json:"processed_at"`
+}
+
+type transferListUsecase struct {
+	storage        storage.IStorage
+	repo           repository.ITransferRepository
+	contextTimeout time.Duration
+}
+
+func NewTransferListUsecase(storage storage.IStorage, repo repository.ITransferRepository, timeout time.Duration) ITransferListUsecase {
+	return &transferListUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+func (uc *transferListUsecase) Call(ctx context.Context, u *domain.User) ([]*TransferListResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	transfers, err := uc.repo.TransferList(tCtx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*TransferListResult, 0)
+	for _, t := range transfers {
+		el := TransferListResult{
+			Direction: TransferDirectionIncoming,
+			Login:     t.SenderLogin,
+			Amount:    entities.GDecimal(t.Amount),
+			CreatedAt: entities.RFC3339Time(t.CreatedAt),
+		}
+
+		if t.SenderID == u.ID {
+			el.Direction = TransferDirectionOutgoing
+			el.Login = t.RecipientLogin
+		}
+
+		result = append(result, &el)
+	}
+
+	return result, nil
+}
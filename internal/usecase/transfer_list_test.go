@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransferListUsecase_Call_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockITransferRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	user := &domain.User{ID: 1, Login: "me"}
+
+	transfers := []*domain.Transfer{
+		{SenderID: 1, SenderLogin: "me", RecipientID: 2, RecipientLogin: "mom", Amount: decimal.NewFromInt(10), CreatedAt: time.Now()},
+		{SenderID: 3, SenderLogin: "dad", RecipientID: 1, RecipientLogin: "me", Amount: decimal.NewFromInt(20), CreatedAt: time.Now()},
+	}
+
+	mockRepo.EXPECT().TransferList(gomock.Any(), user.ID).Return(transfers, nil)
+
+	uc := NewTransferListUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), user)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, TransferDirectionOutgoing, result[0].Direction)
+	assert.Equal(t, "mom", result[0].Login)
+	assert.Equal(t, entities.GDecimal(decimal.NewFromInt(10)), result[0].Amount)
+	assert.Equal(t, TransferDirectionIncoming, result[1].Direction)
+	assert.Equal(t, "dad", result[1].Login)
+}
+
+func TestTransferListUsecase_Call_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockITransferRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+	user := &domain.User{ID: 1}
+
+	expectedError := errors.New("database error")
+	mockRepo.EXPECT().TransferList(gomock.Any(), user.ID).Return(nil, expectedError)
+
+	uc := NewTransferListUsecase(mockStorage, mockRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), user)
+
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, result)
+}
@@ -15,17 +15,18 @@ type IGetUserBalanceUsecase interface {
 }
 
 type GetUserBalanceResult struct {
-	Current   entities.GDecimal `json:"current"`
-	Withdrawn entities.GDecimal `json:"withdrawn"`
+	Current   entities.GDecimal  `json:"current"`
+	Withdrawn entities.GDecimal  `json:"withdrawn"`
+	Tier      domain.LoyaltyTier `json:"tier,omitempty"`
 }
 
 type getUserBalanceUsecase struct {
-	storage        storage.IPGXStorage
+	storage        storage.IStorage
 	repo           repository.IUserRepository
 	contextTimeout time.Duration
 }
 
-func NewGetUserBalanceUsecase(storage storage.IPGXStorage, repo repository.IUserRepository, timeout time.Duration) IGetUserBalanceUsecase {
+func NewGetUserBalanceUsecase(storage storage.IStorage, repo repository.IUserRepository, timeout time.Duration) IGetUserBalanceUsecase {
 	return &getUserBalanceUsecase{storage: storage, repo: repo, contextTimeout: timeout}
 }
 
@@ -33,7 +34,7 @@ func (uc *getUserBalanceUsecase) Call(ctx context.Context, user *domain.User) (*
 	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	b, w, err := uc.repo.UserGetBalance(tCtx, nil, user.ID)
+	b, w, err := uc.repo.UserGetBalance(tCtx, user.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +42,7 @@ func (uc *getUserBalanceUsecase) Call(ctx context.Context, user *domain.User) (*
 	result := &GetUserBalanceResult{
 		Current:   entities.GDecimal(*b),
 		Withdrawn: entities.GDecimal(*w),
+		Tier:      user.Tier,
 	}
 
 	return result, nil
@@ -22,12 +22,12 @@ func TestGetUserBalanceUsecase_Call_Success(t *testing.T) {
 	mockRepo := mock_repository.NewMockIUserRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
 	ctx := context.Background()
-	user := domain.User{ID: 1}
+	user := domain.User{ID: 1, Tier: "GOLD"}
 
 	balance := decimal.NewFromFloat(float64(100.50))
 	withdrawn := decimal.NewFromFloat(float64(50.25))
 
-	mockRepo.EXPECT().UserGetBalance(gomock.Any(), nil, gomock.Any()).Return(&balance, &withdrawn, nil)
+	mockRepo.EXPECT().UserGetBalance(gomock.Any(), gomock.Any()).Return(&balance, &withdrawn, nil)
 
 	uc := NewGetUserBalanceUsecase(mockStorage, mockRepo, 5*time.Second)
 
@@ -37,6 +37,7 @@ func TestGetUserBalanceUsecase_Call_Success(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, entities.GDecimal(balance), result.Current)
 	assert.Equal(t, entities.GDecimal(withdrawn), result.Withdrawn)
+	assert.Equal(t, domain.LoyaltyTier("GOLD"), result.Tier)
 }
 
 func TestGetUserBalanceUsecase_Call_Error(t *testing.T) {
@@ -50,7 +51,7 @@ func TestGetUserBalanceUsecase_Call_Error(t *testing.T) {
 
 	expectedError := errors.New("database error")
 
-	mockRepo.EXPECT().UserGetBalance(gomock.Any(), nil, gomock.Any()).Return(nil, nil, expectedError)
+	mockRepo.EXPECT().UserGetBalance(gomock.Any(), gomock.Any()).Return(nil, nil, expectedError)
 
 	uc := NewGetUserBalanceUsecase(mockStorage, mockRepo, 5*time.Second)
 
@@ -72,7 +73,7 @@ func TestGetUserBalanceUsecase_Call_ContextTimeout(t *testing.T) {
 
 	user := domain.User{ID: 1}
 
-	mockRepo.EXPECT().UserGetBalance(gomock.Any(), nil, gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, tx interface{}, id domain.UserID) (*float64, *float64, error) {
+	mockRepo.EXPECT().UserGetBalance(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(ctx context.Context, id domain.UserID) (*float64, *float64, error) {
 		time.Sleep(2 * time.Millisecond) // симуляция задержки, чтобы истек контекст
 		return nil, nil, context.DeadlineExceeded
 	})
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/utils"
+)
+
+var ErrInvalidPassword = errors.New("invalid password")
+
+type IChangePasswordUsecase interface {
+	Call(ctx context.Context, user *domain.User, form ChangePasswordRequest) error
+}
+
+type ChangePasswordRequest struct {
+	Password    string `json:"password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=3"`
+}
+
+type changePasswordUsecase struct {
+	storage        storage.IStorage
+	repo           repository.IUserRepository
+	contextTimeout time.Duration
+}
+
+func NewChangePasswordUsecase(storage storage.IStorage, repo repository.IUserRepository, timeout time.Duration) IChangePasswordUsecase {
+	return &changePasswordUsecase{storage: storage, repo: repo, contextTimeout: timeout}
+}
+
+// смена пароля требует текущий пароль; после смены списания запрещены на время WithdrawalRules.PasswordChangeCooldown
+func (uc *changePasswordUsecase) Call(ctx context.Context, user *domain.User, form ChangePasswordRequest) error {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if err := utils.ComparePassword(user.Password, form.Password); err != nil {
+		return ErrInvalidPassword
+	}
+
+	hash, err := utils.HashPassword(form.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return uc.repo.UserUpdatePassword(tCtx, user.ID, hash)
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage/memory"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangePasswordUsecase_Call_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	hash, err := utils.HashPassword("old")
+	require.NoError(t, err)
+	user := &domain.User{ID: 1, Password: hash}
+
+	mockRepo.EXPECT().UserUpdatePassword(gomock.Any(), user.ID, gomock.Any()).DoAndReturn(func(_ context.Context, _ domain.UserID, password string) error {
+		assert.NoError(t, utils.ComparePassword(password, "new"))
+		return nil
+	})
+
+	uc := NewChangePasswordUsecase(mockStorage, mockRepo, 5*time.Second)
+	err = uc.Call(context.Background(), user, ChangePasswordRequest{Password: "old", NewPassword: "new"})
+
+	assert.NoError(t, err)
+}
+
+func TestChangePasswordUsecase_Call_InvalidPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	hash, err := utils.HashPassword("old")
+	require.NoError(t, err)
+
+	uc := NewChangePasswordUsecase(mockStorage, mockRepo, 5*time.Second)
+	err = uc.Call(context.Background(), &domain.User{ID: 1, Password: hash}, ChangePasswordRequest{Password: "wrong", NewPassword: "new"})
+
+	assert.Equal(t, ErrInvalidPassword, err)
+}
+
+// смена пароля запрещает списания на время PasswordChangeCooldown
+func TestChangePasswordUsecase_Call_StartsWithdrawalCooldown(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	repos := memory.NewRepositories(s)
+
+	hash, err := utils.HashPassword("old")
+	require.NoError(t, err)
+	user, err := repos.User.UserCreate(ctx, "me", hash)
+	require.NoError(t, err)
+
+	order, err := repos.Order.OrderCreate(ctx, domain.Order{UserID: user.ID, Number: "12345678903", Status: domain.OrderStatusNew})
+	require.NoError(t, err)
+	require.NoError(t, repos.Order.OrderUpdate(ctx, domain.Order{ID: order.ID, Status: domain.OrderStatusProcessed, Accrual: decimal.NewFromInt(500)}))
+	require.NoError(t, repos.User.UserUpdateBalanceAndWithdrawals(ctx, user.ID))
+
+	withdraw := NewWithdrawBalanceUsecase(s, repos.User, repos.Withdrawal, WithdrawalRules{PasswordChangeCooldown: time.Hour}, 5*time.Second)
+	form := WithdrawBalanceRequest{OrderNumber: "2377225624", Amount: decimal.NewFromInt(100)}
+
+	user, err = repos.User.UserFindByLogin(ctx, "me")
+	require.NoError(t, err)
+	require.NoError(t, withdraw.Call(ctx, user, form))
+
+	uc := NewChangePasswordUsecase(s, repos.User, 5*time.Second)
+	require.NoError(t, uc.Call(ctx, user, ChangePasswordRequest{Password: "old", NewPassword: "new"}))
+
+	user, err = repos.User.UserFindByLogin(ctx, "me")
+	require.NoError(t, err)
+	assert.Equal(t, ErrWithdrawalCooldown, withdraw.Call(ctx, user, WithdrawBalanceRequest{OrderNumber: "12345678903", Amount: decimal.NewFromInt(100)}))
+}
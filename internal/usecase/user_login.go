@@ -28,13 +28,13 @@ type LoginRequest struct {
 }
 
 type loginUsecase struct {
-	storage        storage.IPGXStorage
+	storage        storage.IStorage
 	repo           repository.IUserRepository
 	secret         entities.Secret
 	contextTimeout time.Duration
 }
 
-func NewLoginUsecase(storage storage.IPGXStorage, repo repository.IUserRepository, secret entities.Secret, timeout time.Duration) ILoginUsecase {
+func NewLoginUsecase(storage storage.IStorage, repo repository.IUserRepository, secret entities.Secret, timeout time.Duration) ILoginUsecase {
 	return &loginUsecase{storage: storage, repo: repo, secret: secret, contextTimeout: timeout}
 }
 
@@ -23,7 +23,7 @@ type IRegisterUsecase interface {
 }
 
 type registerUsecase struct {
-	storage        storage.IPGXStorage
+	storage        storage.IStorage
 	repo           repository.IUserRepository
 	secret         entities.Secret
 	contextTimeout time.Duration
@@ -33,7 +33,7 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required,min=3"`
 }
 
-func NewRegisterUsecase(storage storage.IPGXStorage, repo repository.IUserRepository, secret entities.Secret, timeout time.Duration) IRegisterUsecase {
+func NewRegisterUsecase(storage storage.IStorage, repo repository.IUserRepository, secret entities.Secret, timeout time.Duration) IRegisterUsecase {
 	return &registerUsecase{storage: storage, repo: repo, secret: secret, contextTimeout: timeout}
 }
 
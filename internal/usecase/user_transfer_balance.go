@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+var ErrTransferRecipientNotFound = errors.New("transfer recipient not found")
+var ErrTransferToSelf = errors.New("transfer to yourself is not allowed")
+var ErrInvalidTransferAmount = errors.New("invalid transfer amount")
+var ErrTransferDailyLimitExceeded = errors.New("daily transfer limit exceeded")
+
+type TransferBalanceRequest struct {
+	Login  string          `json:"login" binding:"required"`
+	Amount decimal.Decimal `json:"sum" binding:"required"`
+}
+
+// дневные лимиты отправителя, нулевые значения - без ограничений
+type TransferLimits struct {
+	DailyAmount decimal.Decimal
+	DailyCount  int
+}
+
+type ITransferBalanceUsecase interface {
+	Call(ctx context.Context, user *domain.User, req TransferBalanceRequest) error
+}
+
+type transferBalanceUsecase struct {
+	storage        storage.IStorage
+	userRepo       repository.IUserRepository
+	transferRepo   repository.ITransferRepository
+	limits         TransferLimits
+	contextTimeout time.Duration
+}
+
+func NewTransferBalanceUsecase(
+	storage storage.IStorage,
+	userRepo repository.IUserRepository,
+	transferRepo repository.ITransferRepository,
+	limits TransferLimits,
+	timeout time.Duration,
+) ITransferBalanceUsecase {
+	return &transferBalanceUsecase{
+		storage:        storage,
+		userRepo:       userRepo,
+		transferRepo:   transferRepo,
+		limits:         limits,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *transferBalanceUsecase) Call(ctx context.Context, user *domain.User, form TransferBalanceRequest) error {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if !form.Amount.IsPositive() {
+		return ErrInvalidTransferAmount
+	}
+
+	// находим получателя
+	recipient, err := uc.userRepo.UserFindByLogin(tCtx, form.Login)
+	if err != nil {
+		if err == storage.ErrRecordNotFound {
+			return ErrTransferRecipientNotFound
+		}
+		return err
+	}
+
+	if recipient.ID == user.ID {
+		return ErrTransferToSelf
+	}
+
+	// блокируем балансы обоих пользователей в порядке возрастания id,
+	// чтобы встречные переводы не приводили к взаимной блокировке
+	ids := []domain.UserID{user.ID, recipient.ID}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return uc.storage.WithinTx(tCtx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		var senderBalance *decimal.Decimal
+		for _, id := range ids {
+			b, _, err := uc.userRepo.UserGetBalance(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if id == user.ID {
+				senderBalance = b
+			}
+		}
+
+		// убеждаемся что баланса достаточно
+		if senderBalance.Cmp(form.Amount) == -1 {
+			return ErrInsufficientUserBalance
+		}
+
+		// проверяем дневные лимиты; баланс отправителя заблокирован,
+		// поэтому параллельные переводы не обойдут проверку
+		if err := uc.checkDailyLimits(ctx, user.ID, form.Amount); err != nil {
+			return err
+		}
+
+		err := uc.transferRepo.TransferCreate(ctx, domain.Transfer{SenderID: user.ID, RecipientID: recipient.ID, Amount: form.Amount})
+		if err != nil {
+			logging.LogErrorCtx(ctx, err, "transferBalanceUsecase(): error creating transfer")
+			return err
+		}
+
+		// актуализируем балансы обоих пользователей
+		for _, id := range ids {
+			err = uc.userRepo.UserUpdateBalanceAndWithdrawals(ctx, id)
+			if err != nil {
+				logging.LogErrorCtx(ctx, err, "transferBalanceUsecase(): error recalculating balance")
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (uc *transferBalanceUsecase) checkDailyLimits(ctx context.Context, senderID domain.UserID, amount decimal.Decimal) error {
+	if !uc.limits.DailyAmount.IsPositive() && uc.limits.DailyCount <= 0 {
+		return nil
+	}
+
+	// сутки считаются по UTC независимо от часовых поясов сервера и БД
+	sum, count, err := uc.transferRepo.TransferStatsSince(ctx, senderID, utils.BeginningOfDay(time.Now().UTC()))
+	if err != nil {
+		return err
+	}
+
+	if uc.limits.DailyCount > 0 && count+1 > uc.limits.DailyCount {
+		return ErrTransferDailyLimitExceeded
+	}
+
+	if uc.limits.DailyAmount.IsPositive() && sum.Add(amount).GreaterThan(uc.limits.DailyAmount) {
+		return ErrTransferDailyLimitExceeded
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransferBalanceUsecase_Call_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockTransferRepo := mock_repository.NewMockITransferRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	sender := &domain.User{ID: 5}
+	recipient := &domain.User{ID: 2, Login: "recipient"}
+	senderBalance := decimal.NewFromInt(100)
+	recipientBalance := decimal.NewFromInt(0)
+
+	mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "recipient").Return(recipient, nil)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+
+	// балансы блокируются в порядке возрастания id
+	gomock.InOrder(
+		mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), recipient.ID).Return(&recipientBalance, nil, nil),
+		mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), sender.ID).Return(&senderBalance, nil, nil),
+	)
+
+	// сутки отсчитываются от полуночи по UTC
+	mockTransferRepo.EXPECT().TransferStatsSince(gomock.Any(), sender.ID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.UserID, since time.Time) (decimal.Decimal, int, error) {
+			assert.Equal(t, time.UTC, since.Location())
+			assert.Equal(t, since.Truncate(24*time.Hour), since)
+			return decimal.NewFromInt(10), 1, nil
+		})
+	mockTransferRepo.EXPECT().TransferCreate(gomock.Any(), domain.Transfer{
+		SenderID:    sender.ID,
+		RecipientID: recipient.ID,
+		Amount:      decimal.NewFromInt(40),
+	}).Return(nil)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), recipient.ID).Return(nil)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), sender.ID).Return(nil)
+
+	limits := TransferLimits{DailyAmount: decimal.NewFromInt(50), DailyCount: 2}
+	uc := NewTransferBalanceUsecase(mockStorage, mockUserRepo, mockTransferRepo, limits, 5*time.Second)
+
+	err := uc.Call(context.Background(), sender, TransferBalanceRequest{Login: "recipient", Amount: decimal.NewFromInt(40)})
+
+	assert.NoError(t, err)
+}
+
+func TestTransferBalanceUsecase_Call_InsufficientBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockTransferRepo := mock_repository.NewMockITransferRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	sender := &domain.User{ID: 1}
+	recipient := &domain.User{ID: 2, Login: "recipient"}
+	balance := decimal.NewFromInt(10)
+
+	mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "recipient").Return(recipient, nil)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), gomock.Any()).Return(&balance, nil, nil).Times(2)
+
+	uc := NewTransferBalanceUsecase(mockStorage, mockUserRepo, mockTransferRepo, TransferLimits{}, 5*time.Second)
+
+	err := uc.Call(context.Background(), sender, TransferBalanceRequest{Login: "recipient", Amount: decimal.NewFromInt(40)})
+
+	assert.Equal(t, ErrInsufficientUserBalance, err)
+}
+
+func TestTransferBalanceUsecase_Call_DailyLimitExceeded(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits TransferLimits
+		sum    decimal.Decimal
+		count  int
+	}{
+		{"amount", TransferLimits{DailyAmount: decimal.NewFromInt(50)}, decimal.NewFromInt(20), 1},
+		{"count", TransferLimits{DailyCount: 3}, decimal.NewFromInt(1), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockTransferRepo := mock_repository.NewMockITransferRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+			sender := &domain.User{ID: 1}
+			recipient := &domain.User{ID: 2, Login: "recipient"}
+			balance := decimal.NewFromInt(100)
+
+			mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "recipient").Return(recipient, nil)
+			mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+			mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), gomock.Any()).Return(&balance, nil, nil).Times(2)
+			mockTransferRepo.EXPECT().TransferStatsSince(gomock.Any(), sender.ID, gomock.Any()).Return(tt.sum, tt.count, nil)
+
+			uc := NewTransferBalanceUsecase(mockStorage, mockUserRepo, mockTransferRepo, tt.limits, 5*time.Second)
+
+			err := uc.Call(context.Background(), sender, TransferBalanceRequest{Login: "recipient", Amount: decimal.NewFromInt(40)})
+
+			assert.Equal(t, ErrTransferDailyLimitExceeded, err)
+		})
+	}
+}
+
+func TestTransferBalanceUsecase_Call_ValidationErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockTransferRepo := mock_repository.NewMockITransferRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	sender := &domain.User{ID: 1, Login: "sender"}
+
+	mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "sender").Return(sender, nil)
+	mockUserRepo.EXPECT().UserFindByLogin(gomock.Any(), "unknown").Return(nil, storage.ErrRecordNotFound)
+
+	uc := NewTransferBalanceUsecase(mockStorage, mockUserRepo, mockTransferRepo, TransferLimits{}, 5*time.Second)
+
+	err := uc.Call(context.Background(), sender, TransferBalanceRequest{Login: "sender", Amount: decimal.NewFromInt(-1)})
+	assert.Equal(t, ErrInvalidTransferAmount, err)
+
+	err = uc.Call(context.Background(), sender, TransferBalanceRequest{Login: "sender", Amount: decimal.NewFromInt(1)})
+	assert.Equal(t, ErrTransferToSelf, err)
+
+	err = uc.Call(context.Background(), sender, TransferBalanceRequest{Login: "unknown", Amount: decimal.NewFromInt(1)})
+	assert.Equal(t, ErrTransferRecipientNotFound, err)
+}
@@ -10,35 +10,51 @@ import (
 	"github.com/ex0rcist/gophermart/internal/storage"
 	"github.com/ex0rcist/gophermart/internal/storage/repository"
 	"github.com/ex0rcist/gophermart/internal/utils"
-	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 )
 
 var ErrInsufficientUserBalance = errors.New("insufficient user balance")
+var ErrInvalidWithdrawalAmount = errors.New("invalid withdrawal amount")
+var ErrWithdrawalAmountTooSmall = errors.New("withdrawal amount is below the minimum")
+var ErrWithdrawalAmountTooLarge = errors.New("withdrawal amount is above the maximum")
+var ErrWithdrawalDailyLimitExceeded = errors.New("daily withdrawal limit exceeded")
+var ErrWithdrawalMonthlyLimitExceeded = errors.New("monthly withdrawal limit exceeded")
+var ErrWithdrawalCooldown = errors.New("withdrawals are temporarily disabled after password change")
 
 type WithdrawBalanceRequest struct {
 	OrderNumber string          `json:"order" binding:"required,luhn"`
 	Amount      decimal.Decimal `json:"sum" binding:"required"`
 }
 
+// правила списания, нулевые значения - без ограничений
+type WithdrawalRules struct {
+	MinAmount              decimal.Decimal
+	MaxAmount              decimal.Decimal
+	DailyLimit             decimal.Decimal
+	MonthlyLimit           decimal.Decimal
+	PasswordChangeCooldown time.Duration
+}
+
 type IWithdrawBalanceUsecase interface {
 	Call(ctx context.Context, user *domain.User, req WithdrawBalanceRequest) error
 }
 
 type withdrawBalanceUsecase struct {
-	storage        storage.IPGXStorage
+	storage        storage.IStorage
 	userRepo       repository.IUserRepository
 	wdrwRepo       repository.IWithdrawalRepository
+	rules          WithdrawalRules
 	contextTimeout time.Duration
 }
 
 func NewWithdrawBalanceUsecase(
-	storage storage.IPGXStorage,
+	storage storage.IStorage,
 	userRepo repository.IUserRepository,
 	wdrwRepo repository.IWithdrawalRepository,
+	rules WithdrawalRules,
 	timeout time.Duration,
 ) IWithdrawBalanceUsecase {
-	return &withdrawBalanceUsecase{storage: storage, userRepo: userRepo, wdrwRepo: wdrwRepo, contextTimeout: timeout}
+	return &withdrawBalanceUsecase{storage: storage, userRepo: userRepo, wdrwRepo: wdrwRepo, rules: rules, contextTimeout: timeout}
 }
 
 func (uc *withdrawBalanceUsecase) Call(ctx context.Context, user *domain.User, form WithdrawBalanceRequest) error {
@@ -50,49 +66,94 @@ func (uc *withdrawBalanceUsecase) Call(ctx context.Context, user *domain.User, f
 		return ErrInvalidOrderNumber
 	}
 
-	// стартуем транзакцию
-	tx, err := uc.storage.GetPool().Begin(ctx)
-	if err != nil {
-		logging.LogErrorCtx(ctx, err, "withdrawBalanceUsecase(): error starting tx")
+	// проверяем сумму списания
+	if err := uc.checkAmount(form.Amount); err != nil {
 		return err
 	}
-	defer func() {
-		err := tx.Rollback(ctx)
-		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			logging.LogErrorCtx(ctx, err, "withdrawBalanceUsecase(): error rolling tx back")
+
+	return uc.storage.WithinTx(tCtx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		// получаем активный баланс, транзакция блокирует user.balance и user.withdrawn
+		b, _, err := uc.userRepo.UserGetBalance(ctx, user.ID)
+		if err != nil {
+			return err
 		}
-	}()
 
-	// получаем активный баланс, транзакция блокирует user.balance и user.withdrawn
-	b, _, err := uc.userRepo.UserGetBalance(tCtx, tx, user.ID)
-	if err != nil {
-		return err
+		// убеждаемся что баланса достаточно
+		if b.Cmp(form.Amount) == -1 {
+			return ErrInsufficientUserBalance
+		}
+
+		// проверяем лимиты; баланс заблокирован, поэтому параллельные списания не обойдут проверку
+		if err = uc.checkVelocity(ctx, user, form.Amount); err != nil {
+			return err
+		}
+
+		// создаем списание
+		err = uc.wdrwRepo.WithdrawalCreate(ctx, domain.Withdrawal{UserID: user.ID, OrderNumber: form.OrderNumber, Amount: form.Amount})
+		if err != nil {
+			logging.LogErrorCtx(ctx, err, "UserWithdrawBalance(): error creating withdrawal")
+			return err
+		}
+
+		// актуализируем user.balance и user.withdrawn
+		err = uc.userRepo.UserUpdateBalanceAndWithdrawals(ctx, user.ID)
+		if err != nil {
+			logging.LogErrorCtx(ctx, err, "UserWithdrawBalance(): error recalculating balance/withdrawn")
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (uc *withdrawBalanceUsecase) checkAmount(amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return ErrInvalidWithdrawalAmount
 	}
 
-	// убеждаемся что баланса достаточно
-	if b.Cmp(form.Amount) == -1 {
-		return ErrInsufficientUserBalance
+	if uc.rules.MinAmount.IsPositive() && amount.LessThan(uc.rules.MinAmount) {
+		return ErrWithdrawalAmountTooSmall
 	}
 
-	// создаем списание
-	err = uc.wdrwRepo.WithdrawalCreate(tCtx, tx, domain.Withdrawal{UserID: user.ID, OrderNumber: form.OrderNumber, Amount: form.Amount})
-	if err != nil {
-		logging.LogErrorCtx(ctx, err, "UserWithdrawBalance(): error creating withdrawal")
-		return err
+	if uc.rules.MaxAmount.IsPositive() && amount.GreaterThan(uc.rules.MaxAmount) {
+		return ErrWithdrawalAmountTooLarge
 	}
 
-	// актуализируем user.balance и user.withdrawn
-	err = uc.userRepo.UserUpdateBalanceAndWithdrawals(ctx, tx, user.ID)
-	if err != nil {
-		logging.LogErrorCtx(ctx, err, "UserWithdrawBalance(): error recalculating balance/withdrawn")
-		return err
+	return nil
+}
+
+func (uc *withdrawBalanceUsecase) checkVelocity(ctx context.Context, user *domain.User, amount decimal.Decimal) error {
+	now := time.Now()
+
+	if uc.rules.PasswordChangeCooldown > 0 && user.PasswordChangedAt != nil {
+		if now.Before(user.PasswordChangedAt.Add(uc.rules.PasswordChangeCooldown)) {
+			return ErrWithdrawalCooldown
+		}
 	}
 
-	// завершаем транзакцию
-	err = tx.Commit(ctx)
-	if err != nil {
-		logging.LogErrorCtx(ctx, err, "UserWithdrawBalance(): error commiting tx")
-		return err
+	limits := []struct {
+		limit decimal.Decimal
+		since time.Time
+		err   error
+	}{
+		// сутки и месяц считаются по UTC, как и в выписках
+		{uc.rules.DailyLimit, utils.BeginningOfDay(now.UTC()), ErrWithdrawalDailyLimitExceeded},
+		{uc.rules.MonthlyLimit, utils.BeginningOfMonth(now.UTC()), ErrWithdrawalMonthlyLimitExceeded},
+	}
+
+	for _, l := range limits {
+		if !l.limit.IsPositive() {
+			continue
+		}
+
+		sum, err := uc.wdrwRepo.WithdrawalSumSince(ctx, user.ID, l.since)
+		if err != nil {
+			return err
+		}
+
+		if sum.Add(amount).GreaterThan(l.limit) {
+			return l.err
+		}
 	}
 
 	return nil
@@ -12,10 +12,14 @@ import (
 	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"go.uber.org/mock/gomock"
 )
 
+// выполняет функцию транзакции без обращения к БД
+func passTx(ctx context.Context, _ storage.TxOptions, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 func TestWithdrawBalanceUsecase_Call_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -23,8 +27,6 @@ func TestWithdrawBalanceUsecase_Call_Success(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
 	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
-	mockTx := new(storage.PGXTxMock)
 
 	ctx := context.Background()
 	user := &domain.User{ID: 1}
@@ -35,15 +37,12 @@ func TestWithdrawBalanceUsecase_Call_Success(t *testing.T) {
 
 	balance := decimal.NewFromFloat(200) // баланс больше чем сумма списания
 
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
-	mockPool.EXPECT().Begin(gomock.Any()).Return(mockTx, nil)
-	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), mockTx, user.ID).Return(&balance, nil, nil)
-	mockWdrwRepo.EXPECT().WithdrawalCreate(gomock.Any(), mockTx, gomock.Any()).Return(nil)
-	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), mockTx, user.ID).Return(nil)
-	mockTx.On("Commit", mock.Anything).Return(nil)
-	mockTx.On("Rollback", mock.Anything).Return(nil)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), user.ID).Return(&balance, nil, nil)
+	mockWdrwRepo.EXPECT().WithdrawalCreate(gomock.Any(), gomock.Any()).Return(nil)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), user.ID).Return(nil)
 
-	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, WithdrawalRules{}, 5*time.Second)
 
 	err := uc.Call(ctx, user, form)
 
@@ -65,7 +64,7 @@ func TestWithdrawBalanceUsecase_Call_InvalidOrderNumber(t *testing.T) {
 		Amount:      decimal.NewFromFloat(100),
 	}
 
-	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, WithdrawalRules{}, 5*time.Second)
 
 	err := uc.Call(ctx, user, invalidForm)
 
@@ -80,8 +79,6 @@ func TestWithdrawBalanceUsecase_Call_InsufficientBalance(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
 	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
-	mockTx := new(storage.PGXTxMock)
 
 	ctx := context.Background()
 	user := &domain.User{ID: 1}
@@ -92,14 +89,10 @@ func TestWithdrawBalanceUsecase_Call_InsufficientBalance(t *testing.T) {
 
 	balance := decimal.NewFromFloat(100.0) // баланс меньше чем сумма списания
 
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
-	mockPool.EXPECT().Begin(gomock.Any()).Return(mockTx, nil)
-	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), mockTx, user.ID).Return(&balance, nil, nil)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), user.ID).Return(&balance, nil, nil)
 
-	mockTx.On("Commit", mock.Anything).Return(nil)
-	mockTx.On("Rollback", mock.Anything).Return(nil)
-
-	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, WithdrawalRules{}, 5*time.Second)
 
 	err := uc.Call(ctx, user, form)
 
@@ -114,7 +107,6 @@ func TestWithdrawBalanceUsecase_Call_TransactionError(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
 	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
 
 	ctx := context.Background()
 	user := &domain.User{ID: 1}
@@ -125,10 +117,9 @@ func TestWithdrawBalanceUsecase_Call_TransactionError(t *testing.T) {
 
 	expectedError := errors.New("transaction error")
 
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
-	mockPool.EXPECT().Begin(gomock.Any()).Return(nil, expectedError)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedError)
 
-	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, WithdrawalRules{}, 5*time.Second)
 
 	err := uc.Call(ctx, user, form)
 
@@ -143,8 +134,6 @@ func TestWithdrawBalanceUsecase_Call_CommitError(t *testing.T) {
 	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
 	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
 	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
-	mockPool := mock_storage.NewMockIPGXPool(ctrl)
-	mockTx := new(storage.PGXTxMock)
 
 	ctx := context.Background()
 	user := &domain.User{ID: 1}
@@ -157,19 +146,126 @@ func TestWithdrawBalanceUsecase_Call_CommitError(t *testing.T) {
 
 	commitError := errors.New("commit error")
 
-	mockStorage.EXPECT().GetPool().Return(mockPool).AnyTimes()
-	mockPool.EXPECT().Begin(gomock.Any()).Return(mockTx, nil)
-	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), mockTx, user.ID).Return(&balance, nil, nil)
-	mockWdrwRepo.EXPECT().WithdrawalCreate(gomock.Any(), mockTx, gomock.Any()).Return(nil)
-	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), mockTx, user.ID).Return(nil)
-
-	mockTx.On("Commit", mock.Anything).Return(commitError)
-	mockTx.On("Rollback", mock.Anything).Return(nil)
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, opts storage.TxOptions, fn func(ctx context.Context) error) error {
+			if err := passTx(ctx, opts, fn); err != nil {
+				return err
+			}
+			return commitError
+		},
+	)
+	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), user.ID).Return(&balance, nil, nil)
+	mockWdrwRepo.EXPECT().WithdrawalCreate(gomock.Any(), gomock.Any()).Return(nil)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), user.ID).Return(nil)
 
-	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+	uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, WithdrawalRules{}, 5*time.Second)
 
 	err := uc.Call(ctx, user, form)
 
 	assert.Error(t, err)
 	assert.Equal(t, commitError, err)
 }
+
+func TestWithdrawBalanceUsecase_Call_AmountRules(t *testing.T) {
+	rules := WithdrawalRules{MinAmount: decimal.NewFromInt(10), MaxAmount: decimal.NewFromInt(500)}
+
+	tests := []struct {
+		name   string
+		amount decimal.Decimal
+		err    error
+	}{
+		{"zero", decimal.Zero, ErrInvalidWithdrawalAmount},
+		{"negative", decimal.NewFromInt(-5), ErrInvalidWithdrawalAmount},
+		{"below min", decimal.NewFromInt(5), ErrWithdrawalAmountTooSmall},
+		{"above max", decimal.NewFromInt(501), ErrWithdrawalAmountTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+			uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, rules, 5*time.Second)
+
+			err := uc.Call(context.Background(), &domain.User{ID: 1}, WithdrawBalanceRequest{OrderNumber: "12345678903", Amount: tt.amount})
+
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestWithdrawBalanceUsecase_Call_VelocityRules(t *testing.T) {
+	recently := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		rules      WithdrawalRules
+		user       *domain.User
+		daySum     decimal.Decimal
+		monthSum   decimal.Decimal
+		sumQueries int
+		err        error
+	}{
+		{
+			name:  "cooldown after password change",
+			rules: WithdrawalRules{PasswordChangeCooldown: 24 * time.Hour},
+			user:  &domain.User{ID: 1, PasswordChangedAt: &recently},
+			err:   ErrWithdrawalCooldown,
+		},
+		{
+			name:       "daily limit",
+			rules:      WithdrawalRules{DailyLimit: decimal.NewFromInt(150)},
+			user:       &domain.User{ID: 1},
+			daySum:     decimal.NewFromInt(100),
+			sumQueries: 1,
+			err:        ErrWithdrawalDailyLimitExceeded,
+		},
+		{
+			name:       "monthly limit",
+			rules:      WithdrawalRules{DailyLimit: decimal.NewFromInt(1000), MonthlyLimit: decimal.NewFromInt(1000)},
+			user:       &domain.User{ID: 1},
+			daySum:     decimal.NewFromInt(0),
+			monthSum:   decimal.NewFromInt(950),
+			sumQueries: 2,
+			err:        ErrWithdrawalMonthlyLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
+			mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+			balance := decimal.NewFromInt(1000)
+
+			mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+			mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), tt.user.ID).Return(&balance, nil, nil)
+
+			sums := []decimal.Decimal{tt.daySum, tt.monthSum}
+			for i := 0; i < tt.sumQueries; i++ {
+				sum := sums[i]
+				mockWdrwRepo.EXPECT().WithdrawalSumSince(gomock.Any(), tt.user.ID, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ domain.UserID, since time.Time) (decimal.Decimal, error) {
+						// границы суток и месяца считаются по UTC
+						assert.Equal(t, time.UTC, since.Location())
+						assert.Equal(t, since.Truncate(24*time.Hour), since)
+						return sum, nil
+					})
+			}
+
+			uc := NewWithdrawBalanceUsecase(mockStorage, mockUserRepo, mockWdrwRepo, tt.rules, 5*time.Second)
+
+			err := uc.Call(context.Background(), tt.user, WithdrawBalanceRequest{OrderNumber: "12345678903", Amount: decimal.NewFromInt(100)})
+
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
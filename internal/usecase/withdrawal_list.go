@@ -15,18 +15,20 @@ type IWithdrawalListUsecase interface {
 }
 
 type WithdrawalListResult struct {
-	OrderNumber string               `json:"order"`
-	Amount      entities.GDecimal    `json:"sum"`
-	CreatedAt   entities.RFC3339Time `json:"processed_at"`
+	OrderNumber string                  `json:"order"`
+	Amount      entities.GDecimal       `json:"sum"`
+	Status      domain.WithdrawalStatus `json:"status"`
+	Refunded    *entities.GDecimal      `json:"refunded,omitempty"`
+	CreatedAt   entities.RFC3339Time    `json:"processed_at"`
 }
 
 type withdrawalListUsecase struct {
-	storage        storage.IPGXStorage
+	storage        storage.IStorage
 	repo           repository.IWithdrawalRepository
 	contextTimeout time.Duration
 }
 
-func NewWithdrawalListUsecase(storage storage.IPGXStorage, repo repository.IWithdrawalRepository, timeout time.Duration) IWithdrawalListUsecase {
+func NewWithdrawalListUsecase(storage storage.IStorage, repo repository.IWithdrawalRepository, timeout time.Duration) IWithdrawalListUsecase {
 	return &withdrawalListUsecase{storage: storage, repo: repo, contextTimeout: timeout}
 }
 
@@ -44,8 +46,15 @@ func (uc *withdrawalListUsecase) Call(ctx context.Context, u *domain.User) ([]*W
 		el := WithdrawalListResult{
 			OrderNumber: w.OrderNumber,
 			Amount:      entities.GDecimal(w.Amount),
+			Status:      w.Status,
 			CreatedAt:   entities.RFC3339Time(w.CreatedAt),
 		}
+
+		if w.Refunded.IsPositive() {
+			val := entities.GDecimal(w.Refunded)
+			el.Refunded = &val
+		}
+
 		result = append(result, &el)
 	}
 
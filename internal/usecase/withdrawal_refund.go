@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/logging"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	"github.com/ex0rcist/gophermart/internal/storage/repository"
+	"github.com/ex0rcist/gophermart/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+var ErrWithdrawalNotFound = errors.New("withdrawal not found")
+var ErrInvalidRefundAmount = errors.New("invalid refund amount")
+var ErrRefundExceedsWithdrawal = errors.New("refund amount exceeds refundable withdrawal amount")
+
+// если сумма не указана, возвращается весь остаток списания
+type RefundWithdrawalRequest struct {
+	OrderNumber string          `json:"order" binding:"required,luhn"`
+	Amount      decimal.Decimal `json:"sum"`
+}
+
+type IRefundWithdrawalUsecase interface {
+	Call(ctx context.Context, form RefundWithdrawalRequest) (*WithdrawalListResult, error)
+}
+
+type refundWithdrawalUsecase struct {
+	storage        storage.IStorage
+	userRepo       repository.IUserRepository
+	wdrwRepo       repository.IWithdrawalRepository
+	contextTimeout time.Duration
+}
+
+func NewRefundWithdrawalUsecase(
+	storage storage.IStorage,
+	userRepo repository.IUserRepository,
+	wdrwRepo repository.IWithdrawalRepository,
+	timeout time.Duration,
+) IRefundWithdrawalUsecase {
+	return &refundWithdrawalUsecase{storage: storage, userRepo: userRepo, wdrwRepo: wdrwRepo, contextTimeout: timeout}
+}
+
+func (uc *refundWithdrawalUsecase) Call(ctx context.Context, form RefundWithdrawalRequest) (*WithdrawalListResult, error) {
+	tCtx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if !utils.LuhnCheck(form.OrderNumber) {
+		return nil, ErrInvalidOrderNumber
+	}
+
+	if form.Amount.IsNegative() {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	var wd *domain.Withdrawal
+	err := uc.storage.WithinTx(tCtx, storage.DefaultTxOptions, func(ctx context.Context) error {
+		var err error
+
+		// блокируем списание, чтобы параллельные возвраты не превысили его сумму
+		wd, err = uc.wdrwRepo.WithdrawalFindByOrderNumber(ctx, form.OrderNumber)
+		if err != nil {
+			if err == storage.ErrRecordNotFound {
+				return ErrWithdrawalNotFound
+			}
+			return err
+		}
+
+		amount := form.Amount
+		if amount.IsZero() {
+			amount = wd.Refundable()
+		}
+
+		if !amount.IsPositive() {
+			return ErrInvalidRefundAmount
+		}
+
+		if amount.GreaterThan(wd.Refundable()) {
+			return ErrRefundExceedsWithdrawal
+		}
+
+		// блокируем user.balance и user.withdrawn
+		_, _, err = uc.userRepo.UserGetBalance(ctx, wd.UserID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.wdrwRepo.WithdrawalRefundCreate(ctx, domain.WithdrawalRefund{WithdrawalID: wd.ID, UserID: wd.UserID, Amount: amount})
+		if err != nil {
+			logging.LogErrorCtx(ctx, err, "refundWithdrawalUsecase(): error creating refund")
+			return err
+		}
+
+		wd.Refunded = wd.Refunded.Add(amount)
+		wd.Status = domain.WithdrawalStatusPartiallyRefunded
+		if wd.Refundable().IsZero() {
+			wd.Status = domain.WithdrawalStatusRefunded
+		}
+
+		err = uc.wdrwRepo.WithdrawalUpdate(ctx, *wd)
+		if err != nil {
+			logging.LogErrorCtx(ctx, err, "refundWithdrawalUsecase(): error updating withdrawal")
+			return err
+		}
+
+		// актуализируем user.balance и user.withdrawn
+		err = uc.userRepo.UserUpdateBalanceAndWithdrawals(ctx, wd.UserID)
+		if err != nil {
+			logging.LogErrorCtx(ctx, err, "refundWithdrawalUsecase(): error recalculating balance/withdrawn")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refunded := entities.GDecimal(wd.Refunded)
+	result := &WithdrawalListResult{
+		OrderNumber: wd.OrderNumber,
+		Amount:      entities.GDecimal(wd.Amount),
+		Status:      wd.Status,
+		Refunded:    &refunded,
+		CreatedAt:   entities.RFC3339Time(wd.CreatedAt),
+	}
+
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ex0rcist/gophermart/internal/domain"
+	"github.com/ex0rcist/gophermart/internal/entities"
+	"github.com/ex0rcist/gophermart/internal/storage"
+	mock_storage "github.com/ex0rcist/gophermart/internal/storage/mocks"
+	mock_repository "github.com/ex0rcist/gophermart/internal/storage/repository/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRefundWithdrawalUsecase_Call_FullRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	wd := &domain.Withdrawal{
+		ID:          1,
+		UserID:      2,
+		OrderNumber: "12345678903",
+		Amount:      decimal.NewFromInt(100),
+		Refunded:    decimal.NewFromInt(30),
+		Status:      domain.WithdrawalStatusPartiallyRefunded,
+	}
+
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockWdrwRepo.EXPECT().WithdrawalFindByOrderNumber(gomock.Any(), "12345678903").Return(wd, nil)
+	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), wd.UserID).Return(nil, nil, nil)
+	mockWdrwRepo.EXPECT().WithdrawalRefundCreate(gomock.Any(), domain.WithdrawalRefund{
+		WithdrawalID: wd.ID,
+		UserID:       wd.UserID,
+		Amount:       decimal.NewFromInt(70),
+	}).Return(nil)
+	mockWdrwRepo.EXPECT().WithdrawalUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, w domain.Withdrawal) error {
+			assert.Equal(t, domain.WithdrawalStatusRefunded, w.Status)
+			assert.True(t, w.Refunded.Equal(decimal.NewFromInt(100)))
+			return nil
+		},
+	)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), wd.UserID).Return(nil)
+
+	uc := NewRefundWithdrawalUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+
+	result, err := uc.Call(context.Background(), RefundWithdrawalRequest{OrderNumber: "12345678903"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WithdrawalStatusRefunded, result.Status)
+	assert.Equal(t, entities.GDecimal(decimal.NewFromInt(100)), *result.Refunded)
+}
+
+func TestRefundWithdrawalUsecase_Call_PartialRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	wd := &domain.Withdrawal{
+		ID:          1,
+		UserID:      2,
+		OrderNumber: "12345678903",
+		Amount:      decimal.NewFromInt(100),
+		Status:      domain.WithdrawalStatusWithdrawn,
+	}
+
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockWdrwRepo.EXPECT().WithdrawalFindByOrderNumber(gomock.Any(), "12345678903").Return(wd, nil)
+	mockUserRepo.EXPECT().UserGetBalance(gomock.Any(), wd.UserID).Return(nil, nil, nil)
+	mockWdrwRepo.EXPECT().WithdrawalRefundCreate(gomock.Any(), gomock.Any()).Return(nil)
+	mockWdrwRepo.EXPECT().WithdrawalUpdate(gomock.Any(), gomock.Any()).Return(nil)
+	mockUserRepo.EXPECT().UserUpdateBalanceAndWithdrawals(gomock.Any(), wd.UserID).Return(nil)
+
+	uc := NewRefundWithdrawalUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+
+	form := RefundWithdrawalRequest{OrderNumber: "12345678903", Amount: decimal.NewFromInt(40)}
+	result, err := uc.Call(context.Background(), form)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WithdrawalStatusPartiallyRefunded, result.Status)
+	assert.Equal(t, entities.GDecimal(decimal.NewFromInt(40)), *result.Refunded)
+}
+
+func TestRefundWithdrawalUsecase_Call_ExceedsWithdrawal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	wd := &domain.Withdrawal{
+		ID:       1,
+		UserID:   2,
+		Amount:   decimal.NewFromInt(100),
+		Refunded: decimal.NewFromInt(90),
+	}
+
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockWdrwRepo.EXPECT().WithdrawalFindByOrderNumber(gomock.Any(), "12345678903").Return(wd, nil)
+
+	uc := NewRefundWithdrawalUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+
+	form := RefundWithdrawalRequest{OrderNumber: "12345678903", Amount: decimal.NewFromInt(20)}
+	_, err := uc.Call(context.Background(), form)
+
+	assert.Equal(t, ErrRefundExceedsWithdrawal, err)
+}
+
+func TestRefundWithdrawalUsecase_Call_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	mockStorage.EXPECT().WithinTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(passTx)
+	mockWdrwRepo.EXPECT().WithdrawalFindByOrderNumber(gomock.Any(), "12345678903").Return(nil, storage.ErrRecordNotFound)
+
+	uc := NewRefundWithdrawalUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+
+	_, err := uc.Call(context.Background(), RefundWithdrawalRequest{OrderNumber: "12345678903"})
+
+	assert.Equal(t, ErrWithdrawalNotFound, err)
+}
+
+func TestRefundWithdrawalUsecase_Call_NegativeAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockWdrwRepo := mock_repository.NewMockIWithdrawalRepository(ctrl)
+	mockStorage := mock_storage.NewMockIPGXStorage(ctrl)
+
+	uc := NewRefundWithdrawalUsecase(mockStorage, mockUserRepo, mockWdrwRepo, 5*time.Second)
+
+	form := RefundWithdrawalRequest{OrderNumber: "12345678903", Amount: decimal.NewFromInt(-1)}
+	_, err := uc.Call(context.Background(), form)
+
+	assert.Equal(t, ErrInvalidRefundAmount, err)
+}
@@ -1,9 +1,52 @@
 package utils
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
 	uuid "github.com/satori/go.uuid"
 )
 
+type requestIDKey struct{}
+
 func GenerateRequestID() string {
 	return uuid.NewV4().String()
 }
+
+// сохраняет идентификатор запроса в контексте для передачи во внешние системы
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// заголовок W3C traceparent: trace-id выводится из идентификатора запроса, чтобы все
+// обращения по одному запросу попадали в одну трассу, parent-id свой у каждого обращения
+func TraceParent(requestID string) string {
+	traceID := strings.ToLower(strings.ReplaceAll(requestID, "-", ""))
+	if !isTraceID(traceID) {
+		sum := sha256.Sum256([]byte(requestID))
+		traceID = hex.EncodeToString(sum[:16])
+	}
+
+	parentID := make([]byte, 8)
+	_, _ = rand.Read(parentID)
+
+	return "00-" + traceID + "-" + hex.EncodeToString(parentID) + "-01"
+}
+
+// 16 байт в hex, не из одних нулей
+func isTraceID(s string) bool {
+	if len(s) != 32 || strings.Trim(s, "0") == "" {
+		return false
+	}
+
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
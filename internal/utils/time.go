@@ -5,3 +5,15 @@ import "time"
 func IntToDuration(s int) time.Duration {
 	return time.Duration(s) * time.Second
 }
+
+// начало суток для t в его часовом поясе
+func BeginningOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// начало месяца для t в его часовом поясе
+func BeginningOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"context"
 	"net/http"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 	"unicode/utf8"
@@ -30,6 +32,29 @@ func TestIntToDuration(t *testing.T) {
 	}
 }
 
+func TestBeginningOfDay(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	input := time.Date(2024, time.July, 15, 23, 59, 59, 999, loc)
+
+	result := BeginningOfDay(input)
+
+	expected := time.Date(2024, time.July, 15, 0, 0, 0, 0, loc)
+	if !result.Equal(expected) {
+		t.Errorf("BeginningOfDay(%v) = %v; expected %v", input, result, expected)
+	}
+}
+
+func TestBeginningOfMonth(t *testing.T) {
+	input := time.Date(2024, time.July, 15, 13, 30, 0, 0, time.UTC)
+
+	result := BeginningOfMonth(input)
+
+	expected := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+	if !result.Equal(expected) {
+		t.Errorf("BeginningOfMonth(%v) = %v; expected %v", input, result, expected)
+	}
+}
+
 func TestHeadersToStr(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -176,3 +201,48 @@ func TestLuhnValidation(t *testing.T) {
 		t.Errorf("expected invalid Luhn number, but got no error")
 	}
 }
+
+func TestRequestIDFromContext(t *testing.T) {
+	ctx := ContextWithRequestID(context.Background(), "rid")
+	if got := RequestIDFromContext(ctx); got != "rid" {
+		t.Errorf("RequestIDFromContext() = %q; expected %q", got, "rid")
+	}
+
+	if got := RequestIDFromContext(context.Background()); got != "" {
+		t.Errorf("RequestIDFromContext() = %q; expected empty", got)
+	}
+}
+
+func TestTraceParent(t *testing.T) {
+	re := regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-01$`)
+
+	tests := []struct {
+		requestID string
+		traceID   string
+	}{
+		{"4BF92F35-77B3-4DA6-A3CE-929D0E0E4736", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"custom-request-id", ""},
+		{"00000000-0000-0000-0000-000000000000", ""},
+	}
+
+	for _, tt := range tests {
+		first := re.FindStringSubmatch(TraceParent(tt.requestID))
+		second := re.FindStringSubmatch(TraceParent(tt.requestID))
+		if first == nil || second == nil {
+			t.Fatalf("TraceParent(%q) has invalid format", tt.requestID)
+		}
+
+		if tt.traceID != "" && first[1] != tt.traceID {
+			t.Errorf("TraceParent(%q) trace-id = %s; expected %s", tt.requestID, first[1], tt.traceID)
+		}
+		if first[1] == strings.Repeat("0", 32) {
+			t.Errorf("TraceParent(%q) trace-id is all zeros", tt.requestID)
+		}
+		if first[1] != second[1] {
+			t.Errorf("TraceParent(%q) trace-id is not stable", tt.requestID)
+		}
+		if first[2] == second[2] {
+			t.Errorf("TraceParent(%q) parent-id is reused", tt.requestID)
+		}
+	}
+}
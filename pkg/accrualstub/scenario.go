@@ -0,0 +1,130 @@
+package accrualstub
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidScenario = errors.New("invalid accrual stub scenario")
+
+// особый статус шага: заказ еще не известен системе начислений, ответ 204
+const StatusNoContent = "NO_CONTENT"
+
+var statuses = map[string]bool{
+	"REGISTERED": true, "PROCESSING": true, "PROCESSED": true, "INVALID": true, StatusNoContent: true,
+}
+
+// сценарий поведения заглушки; для заказа применяется первое правило с подходящим префиксом номера
+type Scenario struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Prefix string `yaml:"prefix"` // пустой - любой номер
+
+	// смена статусов отсчитывается от первого запроса по заказу
+	Steps   []Step  `yaml:"steps"`
+	Accrual Accrual `yaml:"accrual"`
+
+	Delay           time.Duration    `yaml:"delay"`             // задержка каждого ответа
+	TooManyRequests *TooManyRequests `yaml:"too_many_requests"` // периодические 429
+	Malformed       bool             `yaml:"malformed"`         // некорректный JSON вместо ответа
+}
+
+type Step struct {
+	After  time.Duration `yaml:"after"`
+	Status string        `yaml:"status"`
+}
+
+// фиксированное начисление или детерминированное по номеру заказа в диапазоне [min, max]
+type Accrual struct {
+	Fixed *decimal.Decimal `yaml:"fixed"`
+	Min   decimal.Decimal  `yaml:"min"`
+	Max   decimal.Decimal  `yaml:"max"`
+}
+
+// каждый every-й запрос к заглушке получает 429
+type TooManyRequests struct {
+	Every      int           `yaml:"every"`
+	RetryAfter time.Duration `yaml:"retry_after"`
+}
+
+// сценарий по умолчанию: заказ регистрируется, через секунду обрабатывается,
+// через три секунды получает начисление от 10 до 1000
+func DefaultScenario() *Scenario {
+	return &Scenario{
+		Rules: []Rule{
+			{
+				Steps: []Step{
+					{After: 0, Status: "REGISTERED"},
+					{After: time.Second, Status: "PROCESSING"},
+					{After: 3 * time.Second, Status: "PROCESSED"},
+				},
+				Accrual: Accrual{Min: decimal.NewFromInt(10), Max: decimal.NewFromInt(1000)},
+			},
+		},
+	}
+}
+
+// загружает сценарий из yaml-файла; пустой путь - сценарий по умолчанию
+func LoadScenario(path string) (*Scenario, error) {
+	if path == "" {
+		return DefaultScenario(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("accrualstub: error reading %s: %w", path, err)
+	}
+
+	return ParseScenario(data)
+}
+
+func ParseScenario(data []byte) (*Scenario, error) {
+	s := &Scenario{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("accrualstub: error parsing scenario: %w", err)
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Scenario) validate() error {
+	if len(s.Rules) == 0 {
+		return fmt.Errorf("%w: no rules", ErrInvalidScenario)
+	}
+
+	for i, r := range s.Rules {
+		if len(r.Steps) == 0 {
+			return fmt.Errorf("%w: rule %d has no steps", ErrInvalidScenario, i)
+		}
+
+		for j, step := range r.Steps {
+			if !statuses[step.Status] {
+				return fmt.Errorf("%w: rule %d has unknown status %q", ErrInvalidScenario, i, step.Status)
+			}
+			if j > 0 && step.After < r.Steps[j-1].After {
+				return fmt.Errorf("%w: rule %d steps are not ordered by time", ErrInvalidScenario, i)
+			}
+		}
+
+		if r.Accrual.Fixed == nil && r.Accrual.Max.LessThan(r.Accrual.Min) {
+			return fmt.Errorf("%w: rule %d accrual max is less than min", ErrInvalidScenario, i)
+		}
+
+		if r.TooManyRequests != nil && r.TooManyRequests.Every < 1 {
+			return fmt.Errorf("%w: rule %d too_many_requests.every must be positive", ErrInvalidScenario, i)
+		}
+	}
+
+	return nil
+}
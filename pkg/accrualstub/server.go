@@ -0,0 +1,155 @@
+package accrualstub
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// заглушка системы начислений: GET /api/orders/{number} по сценарию;
+// реализует http.Handler, поэтому подходит для httptest.NewServer
+type Server struct {
+	scenario *Scenario
+	mux      *http.ServeMux
+
+	// источник времени, в тестах можно подменить для управления сменой статусов
+	Now func() time.Time
+
+	mu        sync.Mutex
+	firstSeen map[string]time.Time
+	requests  map[int]int // счетчик запросов по номеру правила
+	calls     map[string]int
+}
+
+type response struct {
+	Order   string      `json:"order"`
+	Status  string      `json:"status"`
+	Accrual json.Number `json:"accrual,omitempty"` // число, как в настоящей системе начислений
+}
+
+func NewServer(scenario *Scenario) *Server {
+	s := &Server{
+		scenario:  scenario,
+		mux:       http.NewServeMux(),
+		Now:       time.Now,
+		firstSeen: make(map[string]time.Time),
+		requests:  make(map[int]int),
+		calls:     make(map[string]int),
+	}
+
+	s.mux.HandleFunc("GET /api/orders/{number}", s.getOrder)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// число запросов по заказу, для проверок в тестах
+func (s *Server) Calls(number string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[number]
+}
+
+func (s *Server) getOrder(w http.ResponseWriter, r *http.Request) {
+	number := r.PathValue("number")
+
+	idx, rule := s.match(number)
+	if rule == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	s.mu.Lock()
+	now := s.Now()
+	first, ok := s.firstSeen[number]
+	if !ok {
+		first = now
+		s.firstSeen[number] = now
+	}
+	s.requests[idx]++
+	seq := s.requests[idx]
+	s.calls[number]++
+	s.mu.Unlock()
+
+	if rule.Delay > 0 {
+		select {
+		case <-time.After(rule.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if tmr := rule.TooManyRequests; tmr != nil && seq%tmr.Every == 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(tmr.RetryAfter.Seconds())))
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte("No more than N requests per minute allowed"))
+		return
+	}
+
+	status := rule.statusAt(now.Sub(first))
+	if status == StatusNoContent {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if rule.Malformed {
+		_, _ = w.Write([]byte(`{"order":"` + number + `","status":`))
+		return
+	}
+
+	res := response{Order: number, Status: status}
+	if status == "PROCESSED" {
+		res.Accrual = json.Number(rule.Accrual.amount(number).String())
+	}
+
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (s *Server) match(number string) (int, *Rule) {
+	for i := range s.scenario.Rules {
+		if strings.HasPrefix(number, s.scenario.Rules[i].Prefix) {
+			return i, &s.scenario.Rules[i]
+		}
+	}
+
+	return -1, nil
+}
+
+// статус последнего шага, время которого наступило
+func (r *Rule) statusAt(elapsed time.Duration) string {
+	status := r.Steps[0].Status
+	for _, step := range r.Steps {
+		if elapsed >= step.After {
+			status = step.Status
+		}
+	}
+
+	return status
+}
+
+func (a Accrual) amount(number string) decimal.Decimal {
+	if a.Fixed != nil {
+		return *a.Fixed
+	}
+
+	// одинаковый номер всегда получает одинаковое начисление
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(number))
+
+	spread := a.Max.Sub(a.Min)
+	fraction := decimal.NewFromInt(int64(h.Sum32() % 10001)).Div(decimal.NewFromInt(10000))
+
+	return a.Min.Add(spread.Mul(fraction)).Round(2)
+}
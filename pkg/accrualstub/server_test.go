@@ -0,0 +1,159 @@
+package accrualstub
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScenario = `
+rules:
+  - prefix: "1"
+    steps:
+      - {after: 0s, status: NO_CONTENT}
+      - {after: 1s, status: PROCESSING}
+      - {after: 5s, status: PROCESSED}
+    accrual:
+      fixed: 500
+  - prefix: "2"
+    steps: [{status: PROCESSED}]
+    accrual: {min: 10, max: 20}
+    too_many_requests: {every: 2, retry_after: 60s}
+  - prefix: "3"
+    steps: [{status: INVALID}]
+    malformed: true
+  - prefix: "4"
+    steps: [{status: REGISTERED}]
+    delay: 200ms
+`
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *Server, *testClock) {
+	scenario, err := ParseScenario([]byte(testScenario))
+	require.NoError(t, err)
+
+	clock := &testClock{now: time.Now()}
+	stub := NewServer(scenario)
+	stub.Now = clock.Now
+
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	return server, stub, clock
+}
+
+func get(t *testing.T, url string) (*http.Response, map[string]any) {
+	res, err := http.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	var doc map[string]any
+	_ = json.Unmarshal(body, &doc)
+
+	return res, doc
+}
+
+func TestServer_StatusProgression(t *testing.T) {
+	server, stub, clock := newTestServer(t)
+
+	res, _ := get(t, server.URL+"/api/orders/12345678903")
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	clock.Advance(2 * time.Second)
+	res, doc := get(t, server.URL+"/api/orders/12345678903")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "PROCESSING", doc["status"])
+	assert.NotContains(t, doc, "accrual")
+
+	clock.Advance(5 * time.Second)
+	_, doc = get(t, server.URL+"/api/orders/12345678903")
+	assert.Equal(t, "PROCESSED", doc["status"])
+	assert.Equal(t, 500.0, doc["accrual"])
+
+	assert.Equal(t, 3, stub.Calls("12345678903"))
+}
+
+func TestServer_TooManyRequestsAndRuleBasedAccrual(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	res, first := get(t, server.URL+"/api/orders/2377225624")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, _ = get(t, server.URL+"/api/orders/2377225624")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "60", res.Header.Get("Retry-After"))
+
+	// начисление детерминировано по номеру и лежит в диапазоне
+	_, again := get(t, server.URL+"/api/orders/2377225624")
+	assert.Equal(t, first["accrual"], again["accrual"])
+
+	amount := decimal.NewFromFloat(first["accrual"].(float64))
+	assert.True(t, amount.GreaterThanOrEqual(decimal.NewFromInt(10)))
+	assert.True(t, amount.LessThanOrEqual(decimal.NewFromInt(20)))
+}
+
+func TestServer_MalformedSlowAndUnknown(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	res, doc := get(t, server.URL+"/api/orders/3456")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Nil(t, doc)
+
+	started := time.Now()
+	res, doc = get(t, server.URL+"/api/orders/4567")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "REGISTERED", doc["status"])
+	assert.GreaterOrEqual(t, time.Since(started), 200*time.Millisecond)
+
+	// номер не подходит ни под одно правило
+	res, _ = get(t, server.URL+"/api/orders/9999")
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func TestParseScenario_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no rules", "rules: []"},
+		{"no steps", "rules: [{prefix: '1'}]"},
+		{"unknown status", "rules: [{steps: [{status: DONE}]}]"},
+		{"unordered steps", "rules: [{steps: [{after: 2s, status: PROCESSING}, {after: 1s, status: PROCESSED}]}]"},
+		{"invalid range", "rules: [{steps: [{status: PROCESSED}], accrual: {min: 20, max: 10}}]"},
+		{"invalid 429", "rules: [{steps: [{status: PROCESSED}], too_many_requests: {every: 0}}]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScenario([]byte(tt.data))
+			assert.ErrorIs(t, err, ErrInvalidScenario)
+		})
+	}
+}